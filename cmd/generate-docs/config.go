@@ -52,6 +52,9 @@ func generateConfigurationGuide() error {
 		mainConfigSection(generateConfigStructure(schemaSections)),
 		configSchemaSections(schemaSections),
 		sharingSection(),
+		workspaceSection(),
+		portsSection(),
+		networksSection(),
 		serviceConfigSection(generateServiceConfigExample(svcMap), generateCustomEnvExample(svcMap)),
 		serviceMetadataSection(),
 		completeExampleSection(generateCompleteExample(schemaNode), generateCompleteEnvExample(svcMap)),
@@ -85,6 +88,27 @@ func sharingSection() string {
 	return sb.String()
 }
 
+func workspaceSection() string {
+	s := docs.ConfigSections.Workspace
+	return s.Heading + "\n\n" + s.Intro + "\n\n" + s.ExampleLabel + "\n\n" +
+		codeBlock("yaml", s.ExampleContent) +
+		s.Note + "\n\n"
+}
+
+func portsSection() string {
+	s := docs.ConfigSections.Ports
+	return s.Heading + "\n\n" + s.Intro + "\n\n" + s.ExampleLabel + "\n\n" +
+		codeBlock("yaml", s.ExampleContent) +
+		s.Note + "\n\n"
+}
+
+func networksSection() string {
+	s := docs.ConfigSections.Networks
+	return s.Heading + "\n\n" + s.Intro + "\n\n" + s.ExampleLabel + "\n\n" +
+		codeBlock("yaml", s.ExampleContent) +
+		s.Note + "\n\n"
+}
+
 func serviceConfigSection(serviceConfigExample, customEnvExample string) string {
 	s := docs.ConfigSections.ServiceConfig
 	return s.Heading + "\n\n" + s.Intro + "\n\n" + s.EnvGeneratedLabel + "\n\n" +
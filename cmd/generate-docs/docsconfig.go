@@ -70,6 +70,9 @@ type configSections struct {
 	FileStructure    configFileStructureSection   `yaml:"file_structure"`
 	MainConfig       configMainConfigSection      `yaml:"main_config"`
 	Sharing          configSharingSection         `yaml:"sharing"`
+	Workspace        configWorkspaceSection       `yaml:"workspace"`
+	Ports            configPortsSection           `yaml:"ports"`
+	Networks         configNetworksSection        `yaml:"networks"`
 	ServiceConfig    configServiceConfigSection   `yaml:"service_config"`
 	ServiceMetadata  configServiceMetadataSection `yaml:"service_metadata"`
 	CompleteExample  configCompleteExampleSection `yaml:"complete_example"`
@@ -96,6 +99,34 @@ type configSharingSection struct {
 	RegistryNote string `yaml:"registry_note"`
 }
 
+type configWorkspaceSection struct {
+	Heading string `yaml:"heading"`
+	Intro   string `yaml:"intro"`
+	// ExampleLabel is the bold file path shown above the code fence.
+	ExampleLabel string `yaml:"example_label"`
+	// ExampleContent is a block scalar containing the YAML code block content.
+	ExampleContent string `yaml:"example_content"`
+	Note           string `yaml:"note"`
+}
+
+type configPortsSection struct {
+	Heading      string `yaml:"heading"`
+	Intro        string `yaml:"intro"`
+	ExampleLabel string `yaml:"example_label"`
+	// ExampleContent is a block scalar containing the YAML code block content.
+	ExampleContent string `yaml:"example_content"`
+	Note           string `yaml:"note"`
+}
+
+type configNetworksSection struct {
+	Heading      string `yaml:"heading"`
+	Intro        string `yaml:"intro"`
+	ExampleLabel string `yaml:"example_label"`
+	// ExampleContent is a block scalar containing the YAML code block content.
+	ExampleContent string `yaml:"example_content"`
+	Note           string `yaml:"note"`
+}
+
 type configServiceConfigSection struct {
 	Heading            string `yaml:"heading"`
 	Intro              string `yaml:"intro"`
@@ -3,9 +3,11 @@ package cli
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/logger"
 	"github.com/otto-nation/otto-stack/internal/pkg/ui"
 	pkgversion "github.com/otto-nation/otto-stack/internal/pkg/version"
@@ -19,6 +21,9 @@ import (
 	_ "github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/utility"
 )
 
+// outputIndent prefixes each line of captured output printed after an error
+const outputIndent = "  | "
+
 // ExecuteFactory executes the root command using the functional builder
 func ExecuteFactory() error {
 	rootCmd := cli.BuildRootCommand()
@@ -32,10 +37,18 @@ func ExecuteFactory() error {
 	err := rootCmd.Execute()
 	if err != nil && err.Error() != "" {
 		ui.DefaultOutput.Error("%s", err.Error())
+		if output := pkgerrors.OutputOf(err); output != "" {
+			fmt.Fprintln(os.Stderr, indentOutput(output))
+		}
 	}
 	return err
 }
 
+// indentOutput indents captured command output so it reads as part of the error above it
+func indentOutput(output string) string {
+	return outputIndent + strings.ReplaceAll(output, "\n", "\n"+outputIndent)
+}
+
 // initConfig reads in config file and ENV variables if set
 func initConfig() {
 	setupViper()
@@ -22,3 +22,10 @@ func TestRootFactory_SetupViper(t *testing.T) {
 func TestRootFactory_ConfigureLogger(t *testing.T) {
 	configureLogger()
 }
+
+func TestIndentOutput(t *testing.T) {
+	got := indentOutput("line one\nline two")
+	if got != "  | line one\n  | line two" {
+		t.Errorf("unexpected indentation %q", got)
+	}
+}
@@ -62,6 +62,7 @@ const (
 const (
 	PermReadWriteExec = 0755
 	PermReadWrite     = 0644
+	PermPrivate       = 0600
 )
 
 // File extension constants
@@ -85,11 +86,50 @@ const (
 	GeneratedDir        = "generated"
 	LocalFileExtension  = ".local"
 	SharedRegistryFile  = "containers.yaml"
+	SharedHistoryFile   = "history.jsonl"
+	ProjectIndexFile    = "projects.yaml"
+	ProxySettingsFile   = "proxy.yaml"
+	CADir               = "ca"
+	CAIssuedDir         = "issued"
+	LogsDir             = "logs"
+	PreviousLogsDir     = "previous"
+	InitLogsDir         = "init"
+	SchemasDir          = "schemas"
+	WorkspaceFileName   = "otto-stack.workspace.yaml"
+)
+
+// JSON Schema files written for editor completion and validation
+const (
+	ConfigSchemaFileName = "config.schema.json"
+	SchemaFileSuffix     = ".schema.json"
+	// SchemaModeline points the YAML language server used by VS Code and
+	// JetBrains IDEs at the schema for the file it heads
+	SchemaModeline = "# yaml-language-server: $schema=%s\n"
+)
+
+// Local certificate authority files. The CA's own are in CADir; each
+// certificate it issues gets a directory of its own under CAIssuedDir, with a
+// copy of the CA certificate so one mount serves all three.
+const (
+	CACertFile  = "ca.crt"
+	CAKeyFile   = "ca.key"
+	TLSCertFile = "tls.crt"
+	TLSKeyFile  = "tls.key"
 )
 
 // Container naming constants
 const (
 	SharedContainerPrefix = AppName + "-"
+	// SharedInstanceSeparator joins a service and the suffix of a second
+	// shared instance, as in otto-stack-postgres-16
+	SharedInstanceSeparator = "-"
+)
+
+// Policies for a shared container running with a different configuration
+// than the project asks for
+const (
+	SharingOnConflictWarn = "warn"
+	SharingOnConflictFail = "fail"
 )
 
 // Docker command constants
@@ -112,6 +152,8 @@ const (
 	ComposeHeaderProjectShared = GeneratedFileHeader + "# This file contains project-specific services only\n# Shared services are located at: %s/docker-compose.yml\n\n"
 	EnvGeneratedHeader         = GeneratedFileHeader + "# Generated on %s\n"
 	RegistryHeader             = GeneratedFileHeader + "# This file tracks shared containers across projects\n\n"
+	ProjectIndexHeader         = GeneratedFileHeader + "# This file lists the otto-stack projects on this machine\n\n"
+	ProxySettingsHeader        = GeneratedFileHeader + "# This file records the port the otto-stack proxy listens on\n\n"
 )
 
 // HTTP status constants
@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"strings"
 	"time"
@@ -11,9 +12,11 @@ import (
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v5/pkg/api"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
 )
@@ -56,6 +59,9 @@ type InitContainerConfig struct {
 	Volumes     []string
 	WorkingDir  string
 	Networks    []string
+	// Output, when set, receives the container's stdout and stderr once it exits.
+	// The container is then removed explicitly instead of auto-removed.
+	Output io.Writer
 }
 
 // DockerClientInterface defines the interface for Docker operations
@@ -161,6 +167,43 @@ func (c *Client) ListContainers(ctx context.Context, project string) ([]Containe
 	return result, nil
 }
 
+// RunningContainersByProject counts the running containers of each compose
+// project, keyed by project name
+func (c *Client) RunningContainersByProject(ctx context.Context) (map[string]int, error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerListContainersFailed, err)
+	}
+
+	running := make(map[string]int)
+	for _, cont := range containers {
+		if project := cont.Labels[ComposeProjectLabel]; project != "" && cont.State == StateRunning {
+			running[project]++
+		}
+	}
+	return running, nil
+}
+
+// ProjectVolumeUsage returns the disk space the volumes of each compose
+// project use, in bytes, keyed by project name
+func (c *Client) ProjectVolumeUsage(ctx context.Context) (map[string]int64, error) {
+	usage, err := c.cli.DiskUsage(ctx, dockertypes.DiskUsageOptions{Types: []dockertypes.DiskUsageObject{dockertypes.VolumeObject}})
+	if err != nil {
+		return nil, pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerDiskUsageFailed, err)
+	}
+
+	sizes := make(map[string]int64)
+	for _, vol := range usage.Volumes {
+		project := vol.Labels[ComposeProjectLabel]
+		// A size of -1 means Docker could not measure the volume
+		if project == "" || vol.UsageData == nil || vol.UsageData.Size < 0 {
+			continue
+		}
+		sizes[project] += vol.UsageData.Size
+	}
+	return sizes, nil
+}
+
 // InspectContainer returns the full status of a named container.
 // It is a silent fallback — on any error it returns a ContainerStatus with State = ServiceStatusNotFound.
 func (c *Client) InspectContainer(ctx context.Context, name string) ContainerStatus {
@@ -187,6 +230,7 @@ func (c *Client) InspectContainer(ctx context.Context, name string) ContainerSta
 	if startedAt, err := time.Parse(time.RFC3339Nano, resp.State.StartedAt); err == nil {
 		status.StartedAt = startedAt
 	}
+	status.RestartCount = resp.RestartCount
 
 	return status
 }
@@ -196,6 +240,22 @@ func (c *Client) RemoveContainer(ctx context.Context, containerID string, force
 	return c.cli.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: force})
 }
 
+// PauseContainer suspends all processes in the named container
+func (c *Client) PauseContainer(ctx context.Context, name string) error {
+	if err := c.cli.ContainerPause(ctx, name); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerPauseContainerFailed, err)
+	}
+	return nil
+}
+
+// UnpauseContainer resumes all processes in the named container
+func (c *Client) UnpauseContainer(ctx context.Context, name string) error {
+	if err := c.cli.ContainerUnpause(ctx, name); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerUnpauseContainerFailed, err)
+	}
+	return nil
+}
+
 // RunInitContainer runs an init container
 func (c *Client) RunInitContainer(ctx context.Context, name string, config InitContainerConfig) error {
 	// Create container
@@ -207,7 +267,8 @@ func (c *Client) RunInitContainer(ctx context.Context, name string, config InitC
 	}
 
 	hostConfig := &container.HostConfig{
-		AutoRemove: true,
+		// Auto-removal would discard the logs before they can be read
+		AutoRemove: config.Output == nil,
 	}
 
 	// Add volumes
@@ -227,14 +288,28 @@ func (c *Client) RunInitContainer(ctx context.Context, name string, config InitC
 	if err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerCreateContainerFailed, err)
 	}
+	if config.Output != nil {
+		defer func() {
+			_ = c.cli.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true})
+		}()
+	}
 
 	// Start container
 	if err := c.cli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerStartContainerFailed, err)
 	}
 
-	// Wait for completion
-	statusCh, errCh := c.cli.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	waitErr := c.waitForInitContainer(ctx, resp.ID)
+	if config.Output != nil {
+		// Output is best effort: the exit status is what decides success
+		_ = c.copyContainerOutput(ctx, resp.ID, config.Output)
+	}
+	return waitErr
+}
+
+// waitForInitContainer blocks until the container exits and fails on a non-zero exit code
+func (c *Client) waitForInitContainer(ctx context.Context, id string) error {
+	statusCh, errCh := c.cli.ContainerWait(ctx, id, container.WaitConditionNotRunning)
 	select {
 	case err := <-errCh:
 		if err != nil {
@@ -249,24 +324,51 @@ func (c *Client) RunInitContainer(ctx context.Context, name string, config InitC
 	return nil
 }
 
+// copyContainerOutput writes the stdout and stderr of a stopped container to out
+func (c *Client) copyContainerOutput(ctx context.Context, id string, out io.Writer) error {
+	reader, err := c.cli.ContainerLogs(ctx, id, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	_, err = stdcopy.StdCopy(out, out, reader)
+	return err
+}
+
 // ContainerStatus represents basic container status
 type ContainerStatus struct {
-	Name      string
-	State     string
-	Health    string
-	Ports     []string
-	CreatedAt time.Time
-	StartedAt time.Time
+	Name         string
+	State        string
+	Health       string
+	Ports        []string
+	CreatedAt    time.Time
+	StartedAt    time.Time
+	RestartCount int
+	// Stats is nil when the container is not running or stats could not be read
+	Stats *ContainerStats
 }
 
-// GetServiceStatus gets status of services in a project
+// GetServiceStatus gets status of services in a project, without stats
 func (c *Client) GetServiceStatus(ctx context.Context, project string, services []string) ([]ContainerStatus, error) {
+	return c.serviceStatus(ctx, project, services, false)
+}
+
+// GetServiceStatusWithStats gets status of services in a project like
+// GetServiceStatus and samples the stats of running containers, which takes
+// a moment per container
+func (c *Client) GetServiceStatusWithStats(ctx context.Context, project string, services []string) ([]ContainerStatus, error) {
+	return c.serviceStatus(ctx, project, services, true)
+}
+
+func (c *Client) serviceStatus(ctx context.Context, project string, services []string, withStats bool) ([]ContainerStatus, error) {
 	containers, err := c.ListContainers(ctx, project)
 	if err != nil {
 		return nil, err
 	}
 
 	statusMap := make(map[string]*ContainerStatus)
+	running := make(map[string]string)
 
 	// Initialize status for requested services
 	for _, service := range services {
@@ -290,6 +392,9 @@ func (c *Client) GetServiceStatus(ctx context.Context, project string, services
 
 		status.State = cont.State
 		status.Health = getHealthStatus(cont.Status)
+		if cont.State == StateRunning {
+			running[cont.Service] = cont.ID
+		}
 
 		// Get detailed container info
 		details, err := c.cli.ContainerInspect(ctx, cont.ID)
@@ -304,6 +409,11 @@ func (c *Client) GetServiceStatus(ctx context.Context, project string, services
 		if started, err := time.Parse(time.RFC3339Nano, details.State.StartedAt); err == nil {
 			status.StartedAt = started
 		}
+		status.RestartCount = details.RestartCount
+	}
+
+	if withStats {
+		c.attachStats(ctx, statusMap, running)
 	}
 
 	var result []ContainerStatus
@@ -314,6 +424,24 @@ func (c *Client) GetServiceStatus(ctx context.Context, project string, services
 	return result, nil
 }
 
+// attachStats collects stats for running containers in parallel and attaches
+// them to their service status. running maps service names to container IDs.
+func (c *Client) attachStats(ctx context.Context, statusMap map[string]*ContainerStatus, running map[string]string) {
+	if len(running) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(running))
+	for _, id := range running {
+		ids = append(ids, id)
+	}
+	collected, _ := c.CollectStats(ctx, ids)
+	for service, id := range running {
+		if stats, ok := collected[id]; ok {
+			statusMap[service].Stats = &stats
+		}
+	}
+}
+
 // extractPorts extracts port mappings from container network settings
 func extractPorts(portMap nat.PortMap) []string {
 	var ports []string
@@ -349,10 +477,11 @@ func mapToEnvSlice(env map[string]string) []string {
 // whose containers do not yet exist and must be created by compose.
 //
 // For each service, the container name is taken from the explicit ContainerName field, or
-// derived as "{project}-{service}" when unset. Three cases are handled:
+// derived as "{project}-{service}" when unset. Four cases are handled:
 //   - Running: container is already at the desired state — skip entirely.
 //   - Not found: compose must create it — include in the returned list.
-//   - Any other state (stopped, paused, etc.): start the container directly via docker start
+//   - Paused: resume the container via docker unpause — docker start rejects paused containers.
+//   - Any other state (stopped, created, etc.): start the container directly via docker start
 //     to avoid a name-conflict error when compose tries to create it.
 func (c *Client) ResolveServicesToStart(ctx context.Context, proj *types.Project) ([]string, error) {
 	var toCreate []string
@@ -368,6 +497,11 @@ func (c *Client) ResolveServicesToStart(ctx context.Context, proj *types.Project
 			slog.Debug("Container already running, skipping", "container", containerName, "service", name)
 		case ServiceStatusNotFound:
 			toCreate = append(toCreate, name)
+		case StatePaused:
+			slog.Debug("Unpausing existing paused container", "container", containerName, "service", name)
+			if err := c.UnpauseContainer(ctx, containerName); err != nil {
+				return nil, err
+			}
 		default:
 			// Container exists but is not running — start it directly to avoid a name conflict
 			// that would occur if compose tried to create a new container with the same explicit name.
@@ -3,12 +3,17 @@
 package docker
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
 	"testing"
 
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/otto-nation/otto-stack/test/testhelpers"
 )
 
@@ -135,6 +140,59 @@ func TestClient_ResolveServicesToStart_StoppedContainer(t *testing.T) {
 	}
 }
 
+func TestClient_ResolveServicesToStart_PausedContainer(t *testing.T) {
+	startCalled := false
+	unpauseCalled := false
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerInspectFunc: func(ctx context.Context, containerID string) (container.InspectResponse, error) {
+			resp := testhelpers.MockContainerJSON(containerID, containerID, "redis:7-alpine", "shared", true)
+			resp.State.Status = StatePaused
+			return resp, nil
+		},
+		ContainerStartFunc: func(ctx context.Context, containerID string, options container.StartOptions) error {
+			startCalled = true
+			return nil
+		},
+		ContainerUnpauseFunc: func(ctx context.Context, containerID string) error {
+			unpauseCalled = true
+			return nil
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	proj := &composetypes.Project{
+		Name:     "shared",
+		Services: composetypes.Services{"redis": {ContainerName: "otto-stack-redis"}},
+	}
+
+	toCreate, err := client.ResolveServicesToStart(context.Background(), proj)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(toCreate) != 0 {
+		t.Errorf("Expected no services to create for paused container, got %v", toCreate)
+	}
+	if !unpauseCalled {
+		t.Error("Expected ContainerUnpause to be called for paused container")
+	}
+	if startCalled {
+		t.Error("Expected ContainerStart not to be called for paused container")
+	}
+}
+
+func TestClient_PauseContainer_Error(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerPauseFunc: func(ctx context.Context, containerID string) error {
+			return errors.New("container is not running")
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	if err := client.PauseContainer(context.Background(), "otto-stack-redis"); err == nil {
+		t.Error("Expected error when pause fails")
+	}
+}
+
 func TestClient_ResolveServicesToStart_FallbackContainerName(t *testing.T) {
 	inspectedName := ""
 	mockDocker := &testhelpers.MockDockerClient{
@@ -229,3 +287,47 @@ func TestClient_GetServiceStatus_Unit(t *testing.T) {
 		t.Errorf("Expected %d statuses, got %d", len(services), len(statuses))
 	}
 }
+
+func TestClient_RunInitContainer_CapturesOutput(t *testing.T) {
+	var logs bytes.Buffer
+	_, _ = stdcopy.NewStdWriter(&logs, stdcopy.Stdout).Write([]byte("creating topics\n"))
+	_, _ = stdcopy.NewStdWriter(&logs, stdcopy.Stderr).Write([]byte("topic already exists\n"))
+
+	var autoRemove bool
+	var removed string
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerCreateFunc: func(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error) {
+			autoRemove = hostConfig.AutoRemove
+			return container.CreateResponse{ID: "init-1"}, nil
+		},
+		ContainerWaitFunc: func(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+			ch := make(chan container.WaitResponse, 1)
+			ch <- container.WaitResponse{StatusCode: 1}
+			return ch, make(chan error)
+		},
+		ContainerLogsFunc: func(ctx context.Context, id string, options container.LogsOptions) (io.ReadCloser, error) {
+			return io.NopCloser(&logs), nil
+		},
+		ContainerRemoveFunc: func(ctx context.Context, containerID string, options container.RemoveOptions) error {
+			removed = containerID
+			return nil
+		},
+	}
+
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+	var out bytes.Buffer
+	err := client.RunInitContainer(context.Background(), "kafka-init", InitContainerConfig{Image: "alpine", Output: &out})
+
+	if err == nil {
+		t.Fatal("Expected an error for a non-zero exit code")
+	}
+	if autoRemove {
+		t.Error("Expected auto-removal to be disabled when capturing output")
+	}
+	if removed != "init-1" {
+		t.Errorf("Expected the init container to be removed, got %q", removed)
+	}
+	if got := out.String(); got != "creating topics\ntopic already exists\n" {
+		t.Errorf("Unexpected captured output %q", got)
+	}
+}
@@ -145,6 +145,7 @@ type ServiceLogConsumer struct {
 	writer       io.Writer
 	noColor      bool
 	multiService bool
+	filter       *LogFilter
 	mu           sync.Mutex
 	serviceColor map[string]string
 	nextColorIdx int
@@ -162,16 +163,44 @@ func NewServiceLogConsumer(writer io.Writer, noColor bool, serviceCount int) *Se
 	}
 }
 
+// WithFilter applies a filter to every line before it is written. Lines the
+// filter rejects are dropped.
+func (c *ServiceLogConsumer) WithFilter(filter *LogFilter) *ServiceLogConsumer {
+	c.filter = filter
+	return c
+}
+
 func (c *ServiceLogConsumer) Log(containerName, message string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	_, _ = fmt.Fprintln(c.writer, c.formatLine(containerName, message, false))
+	c.write(containerName, message, false)
 }
 
 func (c *ServiceLogConsumer) Err(containerName, message string) {
+	c.write(containerName, message, true)
+}
+
+func (c *ServiceLogConsumer) write(containerName, message string, isErr bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	_, _ = fmt.Fprintln(c.writer, c.formatLine(containerName, message, true))
+	line, level, ok := c.filter.Apply(containerName, message)
+	if !ok {
+		return
+	}
+	_, _ = fmt.Fprintln(c.writer, c.formatLine(containerName, c.highlight(line, level), isErr))
+}
+
+// highlight colors warnings yellow and errors red so they stand out in a busy stream
+func (c *ServiceLogConsumer) highlight(message string, level LogLevel) string {
+	if c.noColor {
+		return message
+	}
+	switch {
+	case level >= LevelError:
+		return ui.ColorRed + message + ui.ColorReset
+	case level == LevelWarn:
+		return ui.ColorYellow + message + ui.ColorReset
+	default:
+		return message
+	}
 }
 
 // Status carries container lifecycle events (e.g. "container started").
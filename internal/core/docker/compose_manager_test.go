@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -57,6 +58,34 @@ func TestServiceLogConsumer_ColorCyclesAcrossServices(t *testing.T) {
 	assert.Contains(t, output, "svc-c")
 }
 
+func TestServiceLogConsumer_WithFilter_MultiService(t *testing.T) {
+	var buf bytes.Buffer
+	filter, err := NewLogFilter("", "warn+", false)
+	assert.NoError(t, err)
+
+	consumer := NewServiceLogConsumer(&buf, true, 2).WithFilter(filter)
+	consumer.Log("kafka", "[2026-01-01 12:00:00,000] INFO started")
+	consumer.Log("kafka", "[2026-01-01 12:00:01,000] WARN broker not available")
+	consumer.Err("postgres", "2026-01-01 12:00:02 UTC [1] ERROR:  relation missing")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "kafka | ")
+	assert.Contains(t, lines[0], "WARN broker not available")
+	assert.Contains(t, lines[1], "postgres | ")
+}
+
+func TestServiceLogConsumer_HighlightsLevels(t *testing.T) {
+	var buf bytes.Buffer
+	consumer := NewServiceLogConsumer(&buf, false, 1)
+	consumer.Log("app", "level=warn msg=slow")
+	consumer.Log("app", "level=info msg=ok")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	assert.Equal(t, ui.ColorYellow+"level=warn msg=slow"+ui.ColorReset, lines[0])
+	assert.Equal(t, "level=info msg=ok", lines[1])
+}
+
 func TestManager_GetService(t *testing.T) {
 	manager := &Manager{service: nil}
 	service := manager.GetService()
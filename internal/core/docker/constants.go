@@ -9,6 +9,7 @@ const (
 	DockerCmd           = "docker"
 	ComposeProjectLabel = "com.docker.compose.project"
 	ComposeServiceLabel = "com.docker.compose.service"
+	ComposeNetworkLabel = "com.docker.compose.network"
 
 	DockerComposeFileName         = "docker-compose.yml"
 	DockerComposeFileNameYaml     = "docker-compose.yaml"
@@ -110,6 +111,7 @@ const (
 	ComposeFieldMemLimit      = "mem_limit"
 	ComposeFieldHealthCheck   = "healthcheck"
 	ComposeFieldLabels        = "labels"
+	ComposeFieldExternal      = "external"
 )
 
 // Health check field names
@@ -125,15 +127,18 @@ const (
 const (
 	VolumeReadOnlySuffix = ":ro"
 	ProtocolSeparator    = "/"
+	MountTypeVolume      = "volume"
 )
 
 // State constants
 const (
-	StateRunning  = "running"
-	StateStopped  = "exited"
-	StateStarting = "starting"
-	StateCreated  = "created"
-	StatePaused   = "paused"
+	StateRunning    = "running"
+	StateStopped    = "exited"
+	StateStarting   = "starting"
+	StateCreated    = "created"
+	StatePaused     = "paused"
+	StateRestarting = "restarting"
+	StateDead       = "dead"
 )
 
 // Health status constants
@@ -148,6 +153,7 @@ const (
 const (
 	DefaultNetworkName    = "default"
 	NetworkNameSuffix     = "-network"
+	NetworkDriverBridge   = "bridge"
 	SharedContainerPrefix = "otto-stack-"
 )
 
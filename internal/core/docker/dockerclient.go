@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
@@ -23,14 +24,25 @@ type DockerClient interface {
 	ContainerStart(ctx context.Context, containerID string, options container.StartOptions) error
 	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
 	ContainerRestart(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerPause(ctx context.Context, containerID string) error
+	ContainerUnpause(ctx context.Context, containerID string) error
 	ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error)
 	VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
 	VolumeRemove(ctx context.Context, volumeID string, force bool) error
+	ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error)
+	ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error)
+	ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error)
+	DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
 	NetworkList(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error)
 	NetworkRemove(ctx context.Context, networkID string) error
+	NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+	NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error
 	ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
 	ImageRemove(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
 	Info(ctx context.Context) (system.Info, error)
 	Ping(ctx context.Context) (types.Ping, error)
 	Close() error
@@ -74,6 +86,14 @@ func (a *dockerClientAdapter) ContainerRestart(ctx context.Context, containerID
 	return a.client.ContainerRestart(ctx, containerID, options)
 }
 
+func (a *dockerClientAdapter) ContainerPause(ctx context.Context, containerID string) error {
+	return a.client.ContainerPause(ctx, containerID)
+}
+
+func (a *dockerClientAdapter) ContainerUnpause(ctx context.Context, containerID string) error {
+	return a.client.ContainerUnpause(ctx, containerID)
+}
+
 func (a *dockerClientAdapter) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
 	return a.client.ContainerWait(ctx, containerID, condition)
 }
@@ -82,6 +102,10 @@ func (a *dockerClientAdapter) ContainerLogs(ctx context.Context, container strin
 	return a.client.ContainerLogs(ctx, container, options)
 }
 
+func (a *dockerClientAdapter) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	return a.client.ContainerStats(ctx, containerID, stream)
+}
+
 func (a *dockerClientAdapter) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
 	return a.client.VolumeList(ctx, options)
 }
@@ -94,10 +118,22 @@ func (a *dockerClientAdapter) NetworkList(ctx context.Context, options network.L
 	return a.client.NetworkList(ctx, options)
 }
 
+func (a *dockerClientAdapter) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	return a.client.NetworkCreate(ctx, name, options)
+}
+
 func (a *dockerClientAdapter) NetworkRemove(ctx context.Context, networkID string) error {
 	return a.client.NetworkRemove(ctx, networkID)
 }
 
+func (a *dockerClientAdapter) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	return a.client.NetworkConnect(ctx, networkID, containerID, config)
+}
+
+func (a *dockerClientAdapter) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	return a.client.NetworkDisconnect(ctx, networkID, containerID, force)
+}
+
 func (a *dockerClientAdapter) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	return a.client.ImageList(ctx, options)
 }
@@ -106,10 +142,30 @@ func (a *dockerClientAdapter) ImageRemove(ctx context.Context, imageID string, o
 	return a.client.ImageRemove(ctx, imageID, options)
 }
 
+func (a *dockerClientAdapter) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	return a.client.Events(ctx, options)
+}
+
 func (a *dockerClientAdapter) Info(ctx context.Context) (system.Info, error) {
 	return a.client.Info(ctx)
 }
 
+func (a *dockerClientAdapter) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	return a.client.ContainerExecCreate(ctx, containerID, options)
+}
+
+func (a *dockerClientAdapter) ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+	return a.client.ContainerExecAttach(ctx, execID, options)
+}
+
+func (a *dockerClientAdapter) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	return a.client.ContainerExecInspect(ctx, execID)
+}
+
+func (a *dockerClientAdapter) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	return a.client.DiskUsage(ctx, options)
+}
+
 func (a *dockerClientAdapter) Ping(ctx context.Context) (types.Ping, error) {
 	return a.client.Ping(ctx)
 }
@@ -100,6 +100,39 @@ func TestDockerClientAdapter_Methods(t *testing.T) {
 		assert.True(t, called)
 	})
 
+	t.Run("ContainerPause", func(t *testing.T) {
+		called := false
+		mock.ContainerPauseFunc = func(ctx context.Context, containerID string) error {
+			called = true
+			return nil
+		}
+		err := mock.ContainerPause(ctx, "test")
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("ContainerUnpause", func(t *testing.T) {
+		called := false
+		mock.ContainerUnpauseFunc = func(ctx context.Context, containerID string) error {
+			called = true
+			return nil
+		}
+		err := mock.ContainerUnpause(ctx, "test")
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
+	t.Run("ContainerStats", func(t *testing.T) {
+		called := false
+		mock.ContainerStatsFunc = func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+			called = true
+			return container.StatsResponseReader{Body: io.NopCloser(strings.NewReader("{}"))}, nil
+		}
+		_, err := mock.ContainerStats(ctx, "test", false)
+		assert.NoError(t, err)
+		assert.True(t, called)
+	})
+
 	t.Run("ContainerLogs", func(t *testing.T) {
 		called := false
 		mock.ContainerLogsFunc = func(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error) {
@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// Event attribute keys reported by Docker for container events
+const (
+	EventAttrName     = "name"
+	EventAttrImage    = "image"
+	EventAttrExitCode = "exitCode"
+
+	// healthStatusActionPrefix prefixes health events, e.g. "health_status: healthy"
+	healthStatusActionPrefix = "health_status: "
+)
+
+// ContainerEvent is a container lifecycle or health event reported by Docker
+type ContainerEvent struct {
+	Time       time.Time         `json:"time" yaml:"time"`
+	Action     string            `json:"action" yaml:"action"`
+	Container  string            `json:"container" yaml:"container"`
+	Service    string            `json:"service,omitempty" yaml:"service,omitempty"`
+	Project    string            `json:"project,omitempty" yaml:"project,omitempty"`
+	Attributes map[string]string `json:"-" yaml:"-"`
+}
+
+// HealthStatus returns the health reported by a health_status event, or "" for other actions.
+func (e ContainerEvent) HealthStatus() string {
+	if after, ok := strings.CutPrefix(e.Action, healthStatusActionPrefix); ok {
+		return after
+	}
+	return ""
+}
+
+// stateChangeActions are container actions that can alter state shown by status.
+// Exec events are deliberately absent: health checks emit them every interval.
+var stateChangeActions = map[events.Action]bool{
+	events.ActionCreate:  true,
+	events.ActionStart:   true,
+	events.ActionRestart: true,
+	events.ActionStop:    true,
+	events.ActionKill:    true,
+	events.ActionDie:     true,
+	events.ActionOOM:     true,
+	events.ActionPause:   true,
+	events.ActionUnPause: true,
+	events.ActionDestroy: true,
+}
+
+// ExitCode returns the exit code reported by a die event, or "" for other actions.
+func (e ContainerEvent) ExitCode() string {
+	if events.Action(e.Action) != events.ActionDie {
+		return ""
+	}
+	return e.Attributes[EventAttrExitCode]
+}
+
+// IsStateChange reports whether the event changes container state or health
+func (e ContainerEvent) IsStateChange() bool {
+	return stateChangeActions[events.Action(e.Action)] || e.HealthStatus() != ""
+}
+
+// StreamContainerEvents streams container events matching filter until ctx is cancelled.
+// The event channel is closed when the stream ends; the error channel receives at most one error.
+func (c *Client) StreamContainerEvents(ctx context.Context, filter filters.Args) (<-chan ContainerEvent, <-chan error) {
+	filter.Add("type", string(events.ContainerEventType))
+	msgCh, errCh := c.cli.Events(ctx, events.ListOptions{Filters: filter})
+
+	out := make(chan ContainerEvent)
+	outErr := make(chan error, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-errCh:
+				if ok && err != nil && ctx.Err() == nil {
+					outErr <- err
+				}
+				return
+			case msg, ok := <-msgCh:
+				if !ok {
+					return
+				}
+				select {
+				case out <- toContainerEvent(msg):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, outErr
+}
+
+// toContainerEvent converts a raw Docker event message to a ContainerEvent
+func toContainerEvent(msg events.Message) ContainerEvent {
+	attrs := msg.Actor.Attributes
+	name := attrs[EventAttrName]
+	if name == "" {
+		name = msg.Actor.ID
+	}
+	at := time.Unix(0, msg.TimeNano)
+	if msg.TimeNano == 0 {
+		at = time.Unix(msg.Time, 0)
+	}
+	return ContainerEvent{
+		Time:       at,
+		Action:     string(msg.Action),
+		Container:  name,
+		Service:    attrs[ComposeServiceLabel],
+		Project:    attrs[ComposeProjectLabel],
+		Attributes: attrs,
+	}
+}
+
+// RestartLoopDetector flags containers that die repeatedly within a time window,
+// which is how a crash-looping container with a restart policy shows up in the
+// event stream.
+type RestartLoopDetector struct {
+	window    time.Duration
+	threshold int
+	deaths    map[string][]time.Time
+}
+
+// NewRestartLoopDetector creates a detector that reports a loop once a container
+// dies threshold times within window
+func NewRestartLoopDetector(window time.Duration, threshold int) *RestartLoopDetector {
+	return &RestartLoopDetector{
+		window:    window,
+		threshold: threshold,
+		deaths:    make(map[string][]time.Time),
+	}
+}
+
+// Observe records an event and returns the number of deaths within the window when
+// it completes a restart loop, or 0 otherwise. Deaths counted towards a reported
+// loop are forgotten, so a container that keeps crashing is reported once per
+// threshold deaths rather than on every event.
+func (d *RestartLoopDetector) Observe(event ContainerEvent) int {
+	if events.Action(event.Action) != events.ActionDie {
+		return 0
+	}
+
+	cutoff := event.Time.Add(-d.window)
+	recent := d.deaths[event.Container][:0]
+	for _, at := range d.deaths[event.Container] {
+		if at.After(cutoff) {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, event.Time)
+
+	if len(recent) >= d.threshold {
+		delete(d.deaths, event.Container)
+		return len(recent)
+	}
+	d.deaths[event.Container] = recent
+	return 0
+}
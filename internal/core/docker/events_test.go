@@ -0,0 +1,101 @@
+//go:build unit
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerEvent_HealthStatus(t *testing.T) {
+	assert.Equal(t, HealthHealthy, ContainerEvent{Action: "health_status: healthy"}.HealthStatus())
+	assert.Empty(t, ContainerEvent{Action: "start"}.HealthStatus())
+}
+
+func TestContainerEvent_IsStateChange(t *testing.T) {
+	assert.True(t, ContainerEvent{Action: "die"}.IsStateChange())
+	assert.True(t, ContainerEvent{Action: "pause"}.IsStateChange())
+	assert.True(t, ContainerEvent{Action: "health_status: unhealthy"}.IsStateChange())
+	assert.False(t, ContainerEvent{Action: "exec_start: pg_isready"}.IsStateChange())
+}
+
+func TestClient_StreamContainerEvents(t *testing.T) {
+	var gotType []string
+	mockDocker := &testhelpers.MockDockerClient{
+		EventsFunc: func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+			gotType = options.Filters.Get("type")
+			msgCh := make(chan events.Message, 1)
+			msgCh <- events.Message{
+				Action: events.ActionStart,
+				Actor: events.Actor{ID: "abc", Attributes: map[string]string{
+					EventAttrName:       "my-app-postgres-1",
+					ComposeServiceLabel: "postgres",
+					ComposeProjectLabel: "my-app",
+				}},
+				TimeNano: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+			}
+			close(msgCh)
+			return msgCh, make(chan error)
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	eventsCh, _ := client.StreamContainerEvents(context.Background(), NewProjectFilter("my-app"))
+	event, ok := <-eventsCh
+	require.True(t, ok)
+	assert.Equal(t, "start", event.Action)
+	assert.Equal(t, "my-app-postgres-1", event.Container)
+	assert.Equal(t, "postgres", event.Service)
+	assert.Equal(t, "my-app", event.Project)
+	assert.Equal(t, []string{string(events.ContainerEventType)}, gotType)
+
+	_, ok = <-eventsCh
+	assert.False(t, ok)
+}
+
+func TestClient_StreamContainerEvents_Error(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		EventsFunc: func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+			errCh := make(chan error, 1)
+			errCh <- errors.New("daemon went away")
+			return make(chan events.Message), errCh
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	_, errCh := client.StreamContainerEvents(context.Background(), NewSharedFilter())
+	assert.EqualError(t, <-errCh, "daemon went away")
+}
+
+func TestContainerEvent_ExitCode(t *testing.T) {
+	attrs := map[string]string{EventAttrExitCode: "137"}
+	assert.Equal(t, "137", ContainerEvent{Action: "die", Attributes: attrs}.ExitCode())
+	assert.Empty(t, ContainerEvent{Action: "kill", Attributes: attrs}.ExitCode())
+}
+
+func TestRestartLoopDetector(t *testing.T) {
+	detector := NewRestartLoopDetector(time.Minute, 3)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	die := func(container string, offset time.Duration) int {
+		return detector.Observe(ContainerEvent{Action: "die", Container: container, Time: start.Add(offset)})
+	}
+
+	assert.Zero(t, die("a", 0))
+	assert.Zero(t, detector.Observe(ContainerEvent{Action: "start", Container: "a", Time: start}))
+	assert.Zero(t, die("b", time.Second), "deaths are counted per container")
+	assert.Zero(t, die("a", 10*time.Second))
+	assert.Equal(t, 3, die("a", 20*time.Second))
+
+	assert.Zero(t, die("a", 30*time.Second), "a reported loop starts counting afresh")
+
+	assert.Zero(t, die("c", 0))
+	assert.Zero(t, die("c", 2*time.Minute), "deaths outside the window are forgotten")
+	assert.Zero(t, die("c", 2*time.Minute+time.Second))
+}
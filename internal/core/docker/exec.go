@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// ExecResult is the outcome of a command run in a container
+type ExecResult struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// Exec runs a command in a running container and waits for it to finish. env
+// holds extra KEY=value variables for the command. A non-zero exit code is
+// returned in the result, not as an error.
+func (c *Client) Exec(ctx context.Context, containerName string, cmd []string, env []string) (ExecResult, error) {
+	created, err := c.cli.ContainerExecCreate(ctx, containerName, container.ExecOptions{
+		Cmd:          cmd,
+		Env:          env,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return ExecResult{}, pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsDockerExecFailed, containerName), err)
+	}
+
+	attached, err := c.cli.ContainerExecAttach(ctx, created.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return ExecResult{}, pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsDockerExecFailed, containerName), err)
+	}
+	var stdout, stderr bytes.Buffer
+	if attached.Reader != nil {
+		_, err = stdcopy.StdCopy(&stdout, &stderr, attached.Reader)
+		attached.Close()
+		if err != nil {
+			return ExecResult{}, pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsDockerExecFailed, containerName), err)
+		}
+	}
+
+	inspect, err := c.cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return ExecResult{}, pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsDockerExecFailed, containerName), err)
+	}
+	return ExecResult{ExitCode: inspect.ExitCode, Stdout: stdout.String(), Stderr: stderr.String()}, nil
+}
+
+// Err reports a non-zero exit as an error carrying the command's stderr
+func (r ExecResult) Err(containerName string) error {
+	if r.ExitCode == 0 {
+		return nil
+	}
+	return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail,
+		fmt.Sprintf(messages.ErrorsDockerExecExit, containerName, r.ExitCode, strings.TrimSpace(r.Stderr)), nil)
+}
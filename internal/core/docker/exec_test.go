@@ -0,0 +1,75 @@
+//go:build unit
+
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+// hijackedOutput frames stdout and stderr the way the daemon streams exec output
+func hijackedOutput(t *testing.T, stdout, stderr string) types.HijackedResponse {
+	t.Helper()
+	var framed bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&framed, stdcopy.Stdout).Write([]byte(stdout))
+	require.NoError(t, err)
+	_, err = stdcopy.NewStdWriter(&framed, stdcopy.Stderr).Write([]byte(stderr))
+	require.NoError(t, err)
+
+	conn, peer := net.Pipe()
+	t.Cleanup(func() { _ = peer.Close() })
+	return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&framed)}
+}
+
+func TestClient_Exec(t *testing.T) {
+	var created container.ExecOptions
+	mock := &testhelpers.MockDockerClient{
+		ExecCreateFunc: func(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+			assert.Equal(t, "otto-stack-postgres", containerID)
+			created = options
+			return container.ExecCreateResponse{ID: "exec1"}, nil
+		},
+		ExecAttachFunc: func(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+			return hijackedOutput(t, "ok\n", "warning\n"), nil
+		},
+		ExecInspectFunc: func(ctx context.Context, execID string) (container.ExecInspect, error) {
+			return container.ExecInspect{ExitCode: 3}, nil
+		},
+	}
+
+	client := NewClientWithDependencies(mock, nil, testhelpers.MockLogger())
+	result, err := client.Exec(context.Background(), "otto-stack-postgres", []string{"sh", "-c", "true"}, []string{"A=b"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sh", "-c", "true"}, created.Cmd)
+	assert.Equal(t, []string{"A=b"}, created.Env)
+	assert.Equal(t, ExecResult{ExitCode: 3, Stdout: "ok\n", Stderr: "warning\n"}, result)
+
+	err = result.Err("otto-stack-postgres")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exited with code 3: warning")
+	assert.NoError(t, ExecResult{}.Err("otto-stack-postgres"))
+}
+
+func TestClient_Exec_CreateFails(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		ExecCreateFunc: func(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+			return container.ExecCreateResponse{}, errors.New("no such container")
+		},
+	}
+
+	_, err := NewClientWithDependencies(mock, nil, testhelpers.MockLogger()).Exec(context.Background(), "missing", []string{"true"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no such container")
+}
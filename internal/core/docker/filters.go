@@ -15,3 +15,27 @@ func NewProjectFilter(projectName string) filters.Args {
 	}
 	return f
 }
+
+// NewManagedFilter creates a filter for resources labelled as managed by otto-stack,
+// optionally narrowed to a single project.
+func NewManagedFilter(projectName string) filters.Args {
+	f := NewProjectFilter(projectName)
+	f.Add("label", fmt.Sprintf("%s=true", LabelOttoManaged))
+	return f
+}
+
+// NewSharedFilter creates a filter for shared containers managed by otto-stack
+func NewSharedFilter() filters.Args {
+	return filters.NewArgs(filters.Arg("label", fmt.Sprintf("%s=true", LabelOttoShared)))
+}
+
+// NewNameFilter creates a filter for the resource named name. Docker matches
+// names as substrings, so callers still compare them exactly.
+func NewNameFilter(name string) filters.Args {
+	return filters.NewArgs(filters.Arg("name", name))
+}
+
+// NewNetworkFilter creates a filter for containers connected to the network
+func NewNetworkFilter(networkName string) filters.Args {
+	return filters.NewArgs(filters.Arg("network", networkName))
+}
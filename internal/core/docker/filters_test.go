@@ -17,3 +17,10 @@ func TestNewProjectFilter(t *testing.T) {
 		assert.Equal(t, 0, filter.Len())
 	})
 }
+
+func TestNewManagedFilter(t *testing.T) {
+	assert.Equal(t, []string{LabelOttoManaged + "=true"}, NewManagedFilter("").Get("label"))
+	assert.ElementsMatch(t,
+		[]string{LabelOttoManaged + "=true", ComposeProjectLabel + "=my-app"},
+		NewManagedFilter("my-app").Get("label"))
+}
@@ -0,0 +1,101 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// ContainerDetails is what the shared command reads from a container beyond
+// its status
+type ContainerDetails struct {
+	Name   string
+	Image  string
+	State  string
+	Labels map[string]string
+	Ports  []string
+	Mounts []ContainerMount
+}
+
+// ContainerMount is a volume or host directory mounted into a container
+type ContainerMount struct {
+	// Type is volume, bind or tmpfs
+	Type string
+	// Name is the volume name; it is empty for bind mounts
+	Name        string
+	Source      string
+	Destination string
+}
+
+// HostPort returns the host port a container port is published on
+func (d *ContainerDetails) HostPort(containerPort int) (int, bool) {
+	suffix := ":" + strconv.Itoa(containerPort)
+	for _, port := range d.Ports {
+		if host, ok := strings.CutSuffix(port, suffix); ok {
+			if published, err := strconv.Atoi(host); err == nil {
+				return published, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// IsVolume reports whether the mount is a named or anonymous Docker volume
+func (m ContainerMount) IsVolume() bool {
+	return m.Type == MountTypeVolume
+}
+
+// InspectDetails returns the image, labels, ports and mounts of a named
+// container. Unlike InspectContainer it fails when the container is missing.
+func (c *Client) InspectDetails(ctx context.Context, name string) (*ContainerDetails, error) {
+	resp, err := c.cli.ContainerInspect(ctx, name)
+	if err != nil {
+		return nil, pkgerrors.NewDockerError(pkgerrors.ErrCodeNotFound, fmt.Sprintf(messages.ErrorsDockerInspectFailed, name), err)
+	}
+
+	details := &ContainerDetails{Name: name}
+	if resp.ContainerJSONBase != nil {
+		details.Name = strings.TrimPrefix(resp.Name, "/")
+		if resp.State != nil {
+			details.State = resp.State.Status
+		}
+	}
+	if resp.Config != nil {
+		details.Image = resp.Config.Image
+		details.Labels = resp.Config.Labels
+	}
+	if resp.NetworkSettings != nil {
+		details.Ports = extractPorts(resp.NetworkSettings.Ports)
+	}
+	for _, m := range resp.Mounts {
+		details.Mounts = append(details.Mounts, ContainerMount{
+			Type:        string(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+		})
+	}
+	return details, nil
+}
+
+// StopContainer stops a named container, waiting up to timeout seconds
+func (c *Client) StopContainer(ctx context.Context, name string, timeout int) error {
+	if err := c.cli.ContainerStop(ctx, name, container.StopOptions{Timeout: &timeout}); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerStopContainerFailed, err)
+	}
+	return nil
+}
+
+// StartContainer starts a named container that already exists
+func (c *Client) StartContainer(ctx context.Context, name string) error {
+	if err := c.cli.ContainerStart(ctx, name, container.StartOptions{}); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentDocker, messages.ErrorsDockerStartContainerFailed, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+//go:build unit
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+func TestClient_InspectDetails(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerInspectFunc: func(ctx context.Context, containerID string) (container.InspectResponse, error) {
+			resp := testhelpers.MockContainerJSON("abc", "/otto-stack-postgres", "postgres:16", "shared", true)
+			resp.Mounts = []container.MountPoint{
+				{Type: mount.TypeVolume, Name: "pgdata", Source: "/var/lib/docker/volumes/pgdata/_data", Destination: "/var/lib/postgresql/data"},
+				{Type: mount.TypeBind, Source: "/home/me/init", Destination: "/docker-entrypoint-initdb.d"},
+			}
+			return resp, nil
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	details, err := client.InspectDetails(context.Background(), "otto-stack-postgres")
+	require.NoError(t, err)
+	assert.Equal(t, "otto-stack-postgres", details.Name)
+	assert.Equal(t, "postgres:16", details.Image)
+	assert.Equal(t, StateRunning, details.State)
+	require.Len(t, details.Mounts, 2)
+	assert.True(t, details.Mounts[0].IsVolume())
+	assert.Equal(t, "pgdata", details.Mounts[0].Name)
+	assert.False(t, details.Mounts[1].IsVolume())
+}
+
+func TestClient_InspectDetails_Missing(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerInspectFunc: func(ctx context.Context, containerID string) (container.InspectResponse, error) {
+			return container.InspectResponse{}, errors.New("No such container")
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	_, err := client.InspectDetails(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestContainerDetails_HostPort(t *testing.T) {
+	details := &ContainerDetails{Ports: []string{"15432:5432", "26686:16686"}}
+
+	port, ok := details.HostPort(16686)
+	assert.True(t, ok)
+	assert.Equal(t, 26686, port)
+
+	_, ok = details.HostPort(686)
+	assert.False(t, ok)
+}
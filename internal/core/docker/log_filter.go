@@ -0,0 +1,272 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// LogLevel is a normalized severity detected in a log line
+type LogLevel int
+
+// Log levels in increasing severity. LevelUnknown means no level was detected.
+const (
+	LevelUnknown LogLevel = iota
+	LevelTrace
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// levelAtLeastSuffix turns a level into a minimum, e.g. "warn+"
+const levelAtLeastSuffix = "+"
+
+// levelNames maps the spellings used by common log formats to a level
+var levelNames = map[string]LogLevel{
+	"trace":    LevelTrace,
+	"debug":    LevelDebug,
+	"info":     LevelInfo,
+	"notice":   LevelInfo,
+	"log":      LevelInfo,
+	"warn":     LevelWarn,
+	"warning":  LevelWarn,
+	"error":    LevelError,
+	"err":      LevelError,
+	"fatal":    LevelFatal,
+	"panic":    LevelFatal,
+	"critical": LevelFatal,
+	"crit":     LevelFatal,
+	// Numeric levels used by pino and bunyan
+	"10": LevelTrace,
+	"20": LevelDebug,
+	"30": LevelInfo,
+	"40": LevelWarn,
+	"50": LevelError,
+	"60": LevelFatal,
+}
+
+// redisLevels maps the Redis log marker character to a level
+var redisLevels = map[string]LogLevel{
+	".": LevelDebug,
+	"-": LevelDebug,
+	"*": LevelInfo,
+	"#": LevelWarn,
+}
+
+// jsonLevelKeys, jsonTimeKeys and jsonMessageKeys are the field names structured
+// loggers commonly use, in order of preference
+var (
+	jsonLevelKeys   = []string{"level", "lvl", "severity", "loglevel"}
+	jsonTimeKeys    = []string{"time", "ts", "timestamp", "@timestamp"}
+	jsonMessageKeys = []string{"msg", "message"}
+)
+
+var (
+	// logfmtLevelPattern matches level=warn and level="warn"
+	logfmtLevelPattern = regexp.MustCompile(`(?i)\b(?:level|lvl)="?([a-z]+)`)
+	// tokenLevelPattern matches upper-case level tokens such as "[WARN]", "ERROR:" or "| INFO |"
+	tokenLevelPattern = regexp.MustCompile(`(?:^|[\s\[|(])(TRACE|DEBUG|INFO|NOTICE|LOG|WARN|WARNING|ERROR|ERR|FATAL|PANIC|CRITICAL)(?:[\]\s:|)]|$)`)
+	// redisLevelPattern matches "1:M 01 Jan 2024 12:00:00.000 # message"
+	redisLevelPattern = regexp.MustCompile(`^\d+:[A-Z] \d{2} [A-Za-z]{3} \d{4} [\d:.]+ ([.\-*#]) `)
+)
+
+// String returns the canonical name of the level
+func (l LogLevel) String() string {
+	switch l {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLogLevel returns the level for a name such as "warn" or "WARNING"
+func ParseLogLevel(name string) (LogLevel, bool) {
+	level, ok := levelNames[strings.ToLower(strings.TrimSpace(name))]
+	return level, ok
+}
+
+// DetectLogLevel finds the severity of a log line. It understands structured JSON
+// logs, logfmt, Redis markers and upper-case level tokens used by most servers
+// (Postgres, Kafka, Java and Go loggers).
+func DetectLogLevel(line string) LogLevel {
+	if fields, ok := parseJSONLog(line); ok {
+		if value, _ := firstField(fields, jsonLevelKeys); value != "" {
+			if level, ok := ParseLogLevel(value); ok {
+				return level
+			}
+		}
+	}
+	if m := logfmtLevelPattern.FindStringSubmatch(line); m != nil {
+		if level, ok := ParseLogLevel(m[1]); ok {
+			return level
+		}
+	}
+	if m := redisLevelPattern.FindStringSubmatch(line); m != nil {
+		return redisLevels[m[1]]
+	}
+	if m := tokenLevelPattern.FindStringSubmatch(line); m != nil {
+		level, _ := ParseLogLevel(m[1])
+		return level
+	}
+	return LevelUnknown
+}
+
+// LogFilter selects and reformats log lines for the logs command. A nil filter
+// passes every line through unchanged.
+type LogFilter struct {
+	grep       *regexp.Regexp
+	level      LogLevel
+	atLeast    bool
+	jsonPretty bool
+	// lastLevel remembers the level of the previous line per container so that
+	// continuation lines, such as stack traces, follow the line they belong to.
+	lastLevel map[string]LogLevel
+}
+
+// NewLogFilter builds a filter from the logs flags. grep is a regular expression;
+// level is a level name, optionally suffixed with "+" to include more severe levels.
+// It returns nil when no option is set.
+func NewLogFilter(grep, level string, jsonPretty bool) (*LogFilter, error) {
+	if grep == "" && level == "" && !jsonPretty {
+		return nil, nil
+	}
+
+	f := &LogFilter{jsonPretty: jsonPretty, lastLevel: make(map[string]LogLevel)}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --grep pattern: %w", err)
+		}
+		f.grep = re
+	}
+	if level != "" {
+		name, atLeast := strings.CutSuffix(level, levelAtLeastSuffix)
+		parsed, ok := ParseLogLevel(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown log level %q", level)
+		}
+		f.level, f.atLeast = parsed, atLeast
+	}
+	return f, nil
+}
+
+// Apply returns the line to print for a message from container, its detected
+// level and whether it should be printed at all. It is not safe for concurrent use.
+func (f *LogFilter) Apply(container, message string) (string, LogLevel, bool) {
+	if f == nil {
+		return message, DetectLogLevel(message), true
+	}
+
+	level := DetectLogLevel(message)
+	if level == LevelUnknown {
+		level = f.lastLevel[container]
+	} else {
+		f.lastLevel[container] = level
+	}
+
+	if f.level != LevelUnknown {
+		if level == LevelUnknown || level < f.level || (!f.atLeast && level != f.level) {
+			return "", level, false
+		}
+	}
+	if f.grep != nil && !f.grep.MatchString(message) {
+		return "", level, false
+	}
+	if f.jsonPretty {
+		if fields, ok := parseJSONLog(message); ok {
+			return formatJSONLog(fields), level, true
+		}
+	}
+	return message, level, true
+}
+
+// parseJSONLog decodes a line holding a single JSON object
+func parseJSONLog(line string) (map[string]any, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !strings.HasSuffix(trimmed, "}") {
+		return nil, false
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(trimmed), &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+// firstField returns the first present key from keys, as a string, and its key
+func firstField(fields map[string]any, keys []string) (string, string) {
+	for _, key := range keys {
+		if value, ok := fields[key]; ok {
+			return fmt.Sprint(value), key
+		}
+	}
+	return "", ""
+}
+
+// formatJSONLog renders a structured log line as "time LEVEL message key=value ...",
+// with the remaining fields in key order
+func formatJSONLog(fields map[string]any) string {
+	var parts []string
+	used := make(map[string]bool)
+	for _, keys := range [][]string{jsonTimeKeys, jsonLevelKeys, jsonMessageKeys} {
+		value, key := firstField(fields, keys)
+		if key == "" {
+			continue
+		}
+		used[key] = true
+		if slices.Contains(jsonLevelKeys, key) {
+			if level, ok := ParseLogLevel(value); ok {
+				value = level.String()
+			}
+			value = strings.ToUpper(value)
+		}
+		parts = append(parts, value)
+	}
+
+	rest := make([]string, 0, len(fields))
+	for key := range fields {
+		if !used[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	for _, key := range rest {
+		parts = append(parts, key+"="+formatJSONValue(fields[key]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatJSONValue renders nested values compactly and quotes strings with spaces
+func formatJSONValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case map[string]any, []any:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v)
+	}
+}
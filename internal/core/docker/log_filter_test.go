@@ -0,0 +1,98 @@
+//go:build unit
+
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want LogLevel
+	}{
+		{"json level", `{"level":"warn","msg":"slow query"}`, LevelWarn},
+		{"json severity", `{"severity":"ERROR","message":"boom"}`, LevelError},
+		{"json numeric", `{"level":50,"msg":"boom"}`, LevelError},
+		{"logfmt", `time=2026-01-01 level=debug msg="cache miss"`, LevelDebug},
+		{"bracketed", `[2026-01-01 12:00:00,000] WARN [Broker id=1] not available`, LevelWarn},
+		{"postgres", `2026-01-01 12:00:00 UTC [1] FATAL:  password authentication failed`, LevelFatal},
+		{"postgres log", `2026-01-01 12:00:00 UTC [1] LOG:  database system is ready`, LevelInfo},
+		{"redis warning", `1:M 01 Jan 2026 12:00:00.000 # WARNING overcommit_memory is set to 0`, LevelWarn},
+		{"redis notice", `1:M 01 Jan 2026 12:00:00.000 * Ready to accept connections`, LevelInfo},
+		{"lower-case words are not levels", `no error occurred`, LevelUnknown},
+		{"plain", `listening on :8080`, LevelUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectLogLevel(tt.line))
+		})
+	}
+}
+
+func TestNewLogFilter(t *testing.T) {
+	filter, err := NewLogFilter("", "", false)
+	require.NoError(t, err)
+	assert.Nil(t, filter, "no options should not filter")
+
+	_, err = NewLogFilter("(unclosed", "", false)
+	assert.Error(t, err)
+
+	_, err = NewLogFilter("", "loud+", false)
+	assert.Error(t, err)
+}
+
+func TestLogFilter_Level(t *testing.T) {
+	atLeast, err := NewLogFilter("", "warn+", false)
+	require.NoError(t, err)
+	exact, err := NewLogFilter("", "warn", false)
+	require.NoError(t, err)
+
+	_, _, ok := atLeast.Apply("app", "INFO started")
+	assert.False(t, ok)
+	_, _, ok = atLeast.Apply("app", "ERROR failed")
+	assert.True(t, ok)
+	_, _, ok = exact.Apply("app", "ERROR failed")
+	assert.False(t, ok)
+	_, _, ok = exact.Apply("app", "WARN retrying")
+	assert.True(t, ok)
+}
+
+func TestLogFilter_ContinuationLinesFollowPreviousLevel(t *testing.T) {
+	filter, err := NewLogFilter("", "error+", false)
+	require.NoError(t, err)
+
+	_, _, ok := filter.Apply("app", "ERROR java.lang.IllegalStateException")
+	assert.True(t, ok)
+	_, _, ok = filter.Apply("app", "\tat com.example.Main.run(Main.java:42)")
+	assert.True(t, ok, "stack trace lines belong to the error above")
+	_, _, ok = filter.Apply("other", "\tat com.example.Main.run(Main.java:42)")
+	assert.False(t, ok, "levels are tracked per container")
+}
+
+func TestLogFilter_Grep(t *testing.T) {
+	filter, err := NewLogFilter("timeout|refused", "", false)
+	require.NoError(t, err)
+
+	_, _, ok := filter.Apply("app", "connection refused")
+	assert.True(t, ok)
+	_, _, ok = filter.Apply("app", "connected")
+	assert.False(t, ok)
+}
+
+func TestLogFilter_JSONPretty(t *testing.T) {
+	filter, err := NewLogFilter("", "", true)
+	require.NoError(t, err)
+
+	line, level, ok := filter.Apply("app", `{"time":"12:00:00","level":40,"msg":"slow query","duration_ms":812,"query":"select 1","ctx":{"id":7}}`)
+	assert.True(t, ok)
+	assert.Equal(t, LevelWarn, level)
+	assert.Equal(t, `12:00:00 WARN slow query ctx={"id":7} duration_ms=812 query="select 1"`, line)
+
+	line, _, _ = filter.Apply("app", "not json")
+	assert.Equal(t, "not json", line)
+}
@@ -0,0 +1,108 @@
+package docker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+// ProjectNetworkName is the name of the network a project's compose file
+// creates for its services
+func ProjectNetworkName(project string) string {
+	return project + NetworkNameSuffix
+}
+
+// NetworkExists reports whether there is a network named name
+func (c *Client) NetworkExists(ctx context.Context, name string) (bool, error) {
+	networks, err := c.cli.NetworkList(ctx, network.ListOptions{Filters: NewNameFilter(name)})
+	if err != nil {
+		return false, err
+	}
+	for _, n := range networks {
+		if n.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ConnectNetwork connects a container to a network, where other containers
+// reach it by aliases as well as its name. A container already connected is
+// left as it is. A missing network or container is not an error, as there
+// is then nothing to connect.
+func (c *Client) ConnectNetwork(ctx context.Context, networkName, containerName string, aliases []string) error {
+	exists, err := c.NetworkExists(ctx, networkName)
+	if err != nil || !exists {
+		return err
+	}
+	found, connected, err := c.connectedTo(ctx, containerName, networkName)
+	if err != nil || !found || connected {
+		return err
+	}
+	return c.cli.NetworkConnect(ctx, networkName, containerName, &network.EndpointSettings{Aliases: aliases})
+}
+
+// DisconnectNetwork disconnects a container from a network it is connected
+// to. A missing container is not an error.
+func (c *Client) DisconnectNetwork(ctx context.Context, networkName, containerName string) error {
+	_, connected, err := c.connectedTo(ctx, containerName, networkName)
+	if err != nil || !connected {
+		return err
+	}
+	return c.cli.NetworkDisconnect(ctx, networkName, containerName, false)
+}
+
+// DisconnectOtherProjects disconnects the containers of other projects, such
+// as those listing the project under networks.attach, from the project's
+// network and returns their names. Compose cannot remove a network that
+// still has containers on it.
+func (c *Client) DisconnectOtherProjects(ctx context.Context, projectName string) ([]string, error) {
+	networkName := ProjectNetworkName(projectName)
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true, Filters: NewNetworkFilter(networkName)})
+	if err != nil {
+		return nil, err
+	}
+
+	var disconnected []string
+	for _, cont := range containers {
+		if cont.Labels[ComposeProjectLabel] == projectName || len(cont.Names) == 0 {
+			continue
+		}
+		if err := c.cli.NetworkDisconnect(ctx, networkName, cont.ID, false); err != nil {
+			return disconnected, err
+		}
+		disconnected = append(disconnected, strings.TrimPrefix(cont.Names[0], "/"))
+	}
+	return disconnected, nil
+}
+
+// connectedTo reports whether the named container exists and whether it is
+// connected to the network
+func (c *Client) connectedTo(ctx context.Context, containerName, networkName string) (found, connected bool, err error) {
+	containers, err := c.cli.ContainerList(ctx, container.ListOptions{All: true, Filters: NewNameFilter(containerName)})
+	if err != nil {
+		return false, false, err
+	}
+	for _, cont := range containers {
+		if !containerNamed(cont, containerName) {
+			continue
+		}
+		if cont.NetworkSettings == nil {
+			return true, false, nil
+		}
+		_, connected = cont.NetworkSettings.Networks[networkName]
+		return true, connected, nil
+	}
+	return false, false, nil
+}
+
+func containerNamed(cont container.Summary, name string) bool {
+	for _, n := range cont.Names {
+		if strings.TrimPrefix(n, "/") == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+//go:build unit
+
+package docker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+// networkDocker fakes a daemon with the given networks and a shared
+// postgres connected to those in connected
+func networkDocker(networks []string, connected ...string) (*testhelpers.MockDockerClient, *[]string) {
+	var calls []string
+	endpoints := make(map[string]*network.EndpointSettings)
+	for _, name := range connected {
+		endpoints[name] = &network.EndpointSettings{}
+	}
+	return &testhelpers.MockDockerClient{
+		NetworkListFunc: func(_ context.Context, _ network.ListOptions) ([]network.Summary, error) {
+			var summaries []network.Summary
+			for _, name := range networks {
+				summaries = append(summaries, network.Summary{Name: name})
+			}
+			return summaries, nil
+		},
+		ContainerListFunc: func(_ context.Context, _ container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{Names: []string{"/otto-stack-postgres-16"}},
+				{Names: []string{"/otto-stack-postgres"}, NetworkSettings: &container.NetworkSettingsSummary{Networks: endpoints}},
+			}, nil
+		},
+		NetworkConnectFunc: func(_ context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+			calls = append(calls, "connect "+networkID+" "+containerID+" "+config.Aliases[0])
+			return nil
+		},
+		NetworkDisconnectFunc: func(_ context.Context, networkID, containerID string, _ bool) error {
+			calls = append(calls, "disconnect "+networkID+" "+containerID)
+			return nil
+		},
+	}, &calls
+}
+
+func TestClient_ConnectNetwork(t *testing.T) {
+	ctx := context.Background()
+
+	mock, calls := networkDocker([]string{"shop-network-old", "shop-network"})
+	client := NewClientWithDependencies(mock, nil, testhelpers.MockLogger())
+	require.NoError(t, client.ConnectNetwork(ctx, ProjectNetworkName("shop"), "otto-stack-postgres", []string{"postgres"}))
+	assert.Equal(t, []string{"connect shop-network otto-stack-postgres postgres"}, *calls)
+
+	// already connected
+	mock, calls = networkDocker([]string{"shop-network"}, "shop-network")
+	client = NewClientWithDependencies(mock, nil, testhelpers.MockLogger())
+	require.NoError(t, client.ConnectNetwork(ctx, "shop-network", "otto-stack-postgres", []string{"postgres"}))
+	assert.Empty(t, *calls)
+
+	// no such network or container
+	mock, calls = networkDocker([]string{"shop-network-old"})
+	client = NewClientWithDependencies(mock, nil, testhelpers.MockLogger())
+	require.NoError(t, client.ConnectNetwork(ctx, "shop-network", "otto-stack-postgres", []string{"postgres"}))
+	require.NoError(t, client.ConnectNetwork(ctx, "shop-network-old", "otto-stack-redis", []string{"redis"}))
+	assert.Empty(t, *calls)
+}
+
+func TestClient_DisconnectNetwork(t *testing.T) {
+	ctx := context.Background()
+
+	mock, calls := networkDocker([]string{"shop-network"}, "shop-network")
+	client := NewClientWithDependencies(mock, nil, testhelpers.MockLogger())
+	require.NoError(t, client.DisconnectNetwork(ctx, "shop-network", "otto-stack-postgres"))
+	require.NoError(t, client.DisconnectNetwork(ctx, "billing-network", "otto-stack-postgres"))
+	require.NoError(t, client.DisconnectNetwork(ctx, "shop-network", "otto-stack-redis"))
+	assert.Equal(t, []string{"disconnect shop-network otto-stack-postgres"}, *calls)
+}
+
+func TestClient_DisconnectOtherProjects(t *testing.T) {
+	var listed container.ListOptions
+	var disconnected []string
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+			listed = options
+			return []container.Summary{
+				{ID: "1", Names: []string{"/shop-api-1"}, Labels: map[string]string{ComposeProjectLabel: "shop"}},
+				{ID: "2", Names: []string{"/billing-worker-1"}, Labels: map[string]string{ComposeProjectLabel: "billing"}},
+			}, nil
+		},
+		NetworkDisconnectFunc: func(_ context.Context, networkID, containerID string, _ bool) error {
+			disconnected = append(disconnected, networkID+" "+containerID)
+			return nil
+		},
+	}
+
+	peers, err := NewClientWithDependencies(mock, nil, nil).DisconnectOtherProjects(context.Background(), "shop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"billing-worker-1"}, peers)
+	assert.Equal(t, []string{ProjectNetworkName("shop") + " 2"}, disconnected, "the project's own containers stay for compose")
+	assert.Equal(t, []string{ProjectNetworkName("shop")}, listed.Filters.Get("network"))
+}
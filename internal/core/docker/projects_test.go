@@ -0,0 +1,63 @@
+//go:build unit
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+func TestClient_RunningContainersByProject(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{State: StateRunning, Labels: map[string]string{ComposeProjectLabel: "api"}},
+				{State: StateRunning, Labels: map[string]string{ComposeProjectLabel: "api"}},
+				{State: StateRunning, Labels: map[string]string{ComposeProjectLabel: "web"}},
+				{State: StateRunning},
+			}, nil
+		},
+	}
+
+	running, err := NewClientWithDependencies(mock, nil, testhelpers.MockLogger()).RunningContainersByProject(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"api": 2, "web": 1}, running)
+}
+
+func TestClient_ProjectVolumeUsage(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		DiskUsageFunc: func(ctx context.Context, options dockertypes.DiskUsageOptions) (dockertypes.DiskUsage, error) {
+			assert.Equal(t, []dockertypes.DiskUsageObject{dockertypes.VolumeObject}, options.Types)
+			return dockertypes.DiskUsage{Volumes: []*volume.Volume{
+				{Labels: map[string]string{ComposeProjectLabel: "api"}, UsageData: &volume.UsageData{Size: 100}},
+				{Labels: map[string]string{ComposeProjectLabel: "api"}, UsageData: &volume.UsageData{Size: 50}},
+				{Labels: map[string]string{ComposeProjectLabel: "web"}, UsageData: &volume.UsageData{Size: -1}},
+				{UsageData: &volume.UsageData{Size: 999}},
+			}}, nil
+		},
+	}
+
+	sizes, err := NewClientWithDependencies(mock, nil, testhelpers.MockLogger()).ProjectVolumeUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"api": 150}, sizes)
+}
+
+func TestClient_ProjectVolumeUsage_Error(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		DiskUsageFunc: func(ctx context.Context, options dockertypes.DiskUsageOptions) (dockertypes.DiskUsage, error) {
+			return dockertypes.DiskUsage{}, errors.New("daemon down")
+		},
+	}
+
+	_, err := NewClientWithDependencies(mock, nil, testhelpers.MockLogger()).ProjectVolumeUsage(context.Background())
+	assert.Error(t, err)
+}
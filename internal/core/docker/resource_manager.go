@@ -49,6 +49,12 @@ func (rm *ResourceManager) Remove(ctx context.Context, resourceType ResourceType
 	}
 }
 
+// CreateNetwork creates a bridge network with the given labels
+func (rm *ResourceManager) CreateNetwork(ctx context.Context, name string, labels map[string]string) error {
+	_, err := rm.client.cli.NetworkCreate(ctx, name, network.CreateOptions{Driver: NetworkDriverBridge, Labels: labels})
+	return err
+}
+
 func (rm *ResourceManager) listContainers(ctx context.Context, filter filters.Args) ([]string, error) {
 	containers, err := rm.client.cli.ContainerList(ctx, container.ListOptions{All: true, Filters: filter})
 	if err != nil {
@@ -139,6 +139,26 @@ func TestResourceManager_RemoveNetworks(t *testing.T) {
 	assert.Contains(t, removed, "net2")
 }
 
+func TestResourceManager_CreateNetwork(t *testing.T) {
+	ctx := context.Background()
+	mock := &testhelpers.MockDockerClient{}
+
+	var gotName string
+	var gotOptions network.CreateOptions
+	mock.NetworkCreateFunc = func(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+		gotName, gotOptions = name, options
+		return network.CreateResponse{ID: "id"}, nil
+	}
+
+	rm := NewResourceManager(NewClientWithDependencies(mock, nil, nil))
+
+	err := rm.CreateNetwork(ctx, "app-network", map[string]string{LabelOttoProject: "app"})
+	assert.NoError(t, err)
+	assert.Equal(t, "app-network", gotName)
+	assert.Equal(t, NetworkDriverBridge, gotOptions.Driver)
+	assert.Equal(t, "app", gotOptions.Labels[LabelOttoProject])
+}
+
 func TestResourceManager_RemoveImages(t *testing.T) {
 	ctx := context.Background()
 	mock := &testhelpers.MockDockerClient{}
@@ -0,0 +1,200 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// StatsCollectTimeout bounds a single one-shot stats request so one slow
+// container cannot stall status for the whole project.
+const StatsCollectTimeout = 5 * time.Second
+
+// Block I/O operation names reported by the cgroup v1 and v2 blkio stats
+const (
+	blkioOpRead  = "read"
+	blkioOpWrite = "write"
+)
+
+// Memory stat keys subtracted from usage to match `docker stats`
+const (
+	memStatInactiveFileV1 = "total_inactive_file"
+	memStatInactiveFileV2 = "inactive_file"
+)
+
+// ContainerStats holds a resource usage sample for one container
+type ContainerStats struct {
+	CPUPercent    float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryUsage   uint64  `json:"memory_usage" yaml:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit" yaml:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent" yaml:"memory_percent"`
+	NetworkRx     uint64  `json:"network_rx" yaml:"network_rx"`
+	NetworkTx     uint64  `json:"network_tx" yaml:"network_tx"`
+	BlockRead     uint64  `json:"block_read" yaml:"block_read"`
+	BlockWrite    uint64  `json:"block_write" yaml:"block_write"`
+	PIDs          uint64  `json:"pids" yaml:"pids"`
+}
+
+// ContainerStatsSample is one reading from StreamContainerStats
+type ContainerStatsSample struct {
+	Container string
+	Stats     ContainerStats
+	Err       error
+}
+
+// NewContainerStats computes usage figures from a raw Docker stats response,
+// using the same formulas as `docker stats`.
+func NewContainerStats(resp container.StatsResponse) ContainerStats {
+	stats := ContainerStats{
+		CPUPercent:  cpuPercent(resp),
+		MemoryUsage: memoryUsage(resp.MemoryStats),
+		MemoryLimit: resp.MemoryStats.Limit,
+		PIDs:        resp.PidsStats.Current,
+	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+	for _, nw := range resp.Networks {
+		stats.NetworkRx += nw.RxBytes
+		stats.NetworkTx += nw.TxBytes
+	}
+	for _, entry := range resp.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case blkioOpRead:
+			stats.BlockRead += entry.Value
+		case blkioOpWrite:
+			stats.BlockWrite += entry.Value
+		}
+	}
+	return stats
+}
+
+// cpuPercent returns CPU usage relative to one core, so a container saturating
+// two cores reports 200%.
+func cpuPercent(resp container.StatsResponse) float64 {
+	cpuDelta := float64(resp.CPUStats.CPUUsage.TotalUsage) - float64(resp.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(resp.CPUStats.SystemUsage) - float64(resp.PreCPUStats.SystemUsage)
+	if cpuDelta <= 0 || systemDelta <= 0 {
+		return 0
+	}
+
+	onlineCPUs := float64(resp.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(resp.CPUStats.CPUUsage.PercpuUsage))
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+// memoryUsage excludes the page cache that the kernel can reclaim at any time
+func memoryUsage(mem container.MemoryStats) uint64 {
+	inactive, ok := mem.Stats[memStatInactiveFileV1]
+	if !ok {
+		inactive = mem.Stats[memStatInactiveFileV2]
+	}
+	if inactive < mem.Usage {
+		return mem.Usage - inactive
+	}
+	return mem.Usage
+}
+
+// GetContainerStats takes a one-shot stats sample for a container. Docker fills
+// in the previous CPU reading itself, so the CPU percentage is meaningful.
+func (c *Client) GetContainerStats(ctx context.Context, name string) (ContainerStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, StatsCollectTimeout)
+	defer cancel()
+
+	reader, err := c.cli.ContainerStats(ctx, name, false)
+	if err != nil {
+		return ContainerStats{}, err
+	}
+	defer func() { _ = reader.Body.Close() }()
+
+	var resp container.StatsResponse
+	if err := json.NewDecoder(reader.Body).Decode(&resp); err != nil {
+		return ContainerStats{}, err
+	}
+	return NewContainerStats(resp), nil
+}
+
+// CollectStats samples the given containers in parallel. Containers whose stats
+// cannot be read are reported in the error map rather than failing the call.
+func (c *Client) CollectStats(ctx context.Context, names []string) (map[string]ContainerStats, map[string]error) {
+	result := make(map[string]ContainerStats, len(names))
+	failed := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			stats, err := c.GetContainerStats(ctx, name)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[name] = err
+				return
+			}
+			result[name] = stats
+		}(name)
+	}
+	wg.Wait()
+	return result, failed
+}
+
+// StreamContainerStats streams stats for each container until ctx is cancelled.
+// The channel is closed once every per-container stream has ended; a container
+// that stops or cannot be read yields one sample with Err set.
+func (c *Client) StreamContainerStats(ctx context.Context, names []string) <-chan ContainerStatsSample {
+	out := make(chan ContainerStatsSample)
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			c.streamOne(ctx, name, out)
+		}(name)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+func (c *Client) streamOne(ctx context.Context, name string, out chan<- ContainerStatsSample) {
+	send := func(sample ContainerStatsSample) bool {
+		select {
+		case out <- sample:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	reader, err := c.cli.ContainerStats(ctx, name, true)
+	if err != nil {
+		send(ContainerStatsSample{Container: name, Err: err})
+		return
+	}
+	defer func() { _ = reader.Body.Close() }()
+
+	decoder := json.NewDecoder(reader.Body)
+	for {
+		var resp container.StatsResponse
+		if err := decoder.Decode(&resp); err != nil {
+			if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+				send(ContainerStatsSample{Container: name, Err: err})
+			}
+			return
+		}
+		if !send(ContainerStatsSample{Container: name, Stats: NewContainerStats(resp)}) {
+			return
+		}
+	}
+}
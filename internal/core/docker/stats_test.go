@@ -0,0 +1,175 @@
+//go:build unit
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleStatsResponse() container.StatsResponse {
+	var resp container.StatsResponse
+	resp.CPUStats.CPUUsage.TotalUsage = 400
+	resp.CPUStats.SystemUsage = 2000
+	resp.CPUStats.OnlineCPUs = 2
+	resp.PreCPUStats.CPUUsage.TotalUsage = 200
+	resp.PreCPUStats.SystemUsage = 1000
+	resp.MemoryStats = container.MemoryStats{
+		Usage: 300,
+		Limit: 1000,
+		Stats: map[string]uint64{memStatInactiveFileV2: 100},
+	}
+	resp.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 10, TxBytes: 20},
+		"eth1": {RxBytes: 1, TxBytes: 2},
+	}
+	resp.BlkioStats.IoServiceBytesRecursive = []container.BlkioStatEntry{
+		{Op: "Read", Value: 5},
+		{Op: "write", Value: 7},
+		{Op: "sync", Value: 99},
+	}
+	resp.PidsStats.Current = 4
+	return resp
+}
+
+func statsReader(t *testing.T, responses ...container.StatsResponse) container.StatsResponseReader {
+	t.Helper()
+	var sb strings.Builder
+	encoder := json.NewEncoder(&sb)
+	for _, resp := range responses {
+		require.NoError(t, encoder.Encode(resp))
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(strings.NewReader(sb.String()))}
+}
+
+func TestNewContainerStats(t *testing.T) {
+	stats := NewContainerStats(sampleStatsResponse())
+
+	assert.InDelta(t, 40.0, stats.CPUPercent, 0.001)
+	assert.Equal(t, uint64(200), stats.MemoryUsage)
+	assert.Equal(t, uint64(1000), stats.MemoryLimit)
+	assert.InDelta(t, 20.0, stats.MemoryPercent, 0.001)
+	assert.Equal(t, uint64(11), stats.NetworkRx)
+	assert.Equal(t, uint64(22), stats.NetworkTx)
+	assert.Equal(t, uint64(5), stats.BlockRead)
+	assert.Equal(t, uint64(7), stats.BlockWrite)
+	assert.Equal(t, uint64(4), stats.PIDs)
+}
+
+func TestNewContainerStats_NoPreviousSample(t *testing.T) {
+	resp := sampleStatsResponse()
+	resp.PreCPUStats = container.CPUStats{}
+	resp.CPUStats.SystemUsage = 0
+
+	stats := NewContainerStats(resp)
+	assert.Zero(t, stats.CPUPercent)
+}
+
+func TestClient_CollectStats(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerStatsFunc: func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+			assert.False(t, stream)
+			if containerID == "broken" {
+				return container.StatsResponseReader{}, errors.New("no such container")
+			}
+			return statsReader(t, sampleStatsResponse()), nil
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	collected, failed := client.CollectStats(context.Background(), []string{"ok", "broken"})
+	require.Contains(t, collected, "ok")
+	assert.Equal(t, uint64(200), collected["ok"].MemoryUsage)
+	assert.NotContains(t, collected, "broken")
+	assert.Error(t, failed["broken"])
+}
+
+func TestClient_StreamContainerStats(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerStatsFunc: func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+			assert.True(t, stream)
+			return statsReader(t, sampleStatsResponse(), sampleStatsResponse()), nil
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	var samples []ContainerStatsSample
+	for sample := range client.StreamContainerStats(context.Background(), []string{"app"}) {
+		samples = append(samples, sample)
+	}
+	require.Len(t, samples, 2)
+	for _, sample := range samples {
+		assert.NoError(t, sample.Err)
+		assert.Equal(t, "app", sample.Container)
+		assert.Equal(t, uint64(4), sample.Stats.PIDs)
+	}
+}
+
+func TestClient_GetServiceStatusWithStats(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "web1", Names: []string{"/app-web-1"}, State: StateRunning, Labels: map[string]string{ComposeServiceLabel: "web"}},
+				{ID: "db1", Names: []string{"/app-db-1"}, State: "exited", Labels: map[string]string{ComposeServiceLabel: "db"}},
+			}, nil
+		},
+		ContainerInspectFunc: func(ctx context.Context, containerID string) (container.InspectResponse, error) {
+			resp := testhelpers.MockContainerJSON(containerID, "/"+containerID, "test:latest", "app", containerID == "web1")
+			resp.RestartCount = 3
+			resp.NetworkSettings = &container.NetworkSettings{}
+			return resp, nil
+		},
+		ContainerStatsFunc: func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+			assert.Equal(t, "web1", containerID, "stats should only be collected for running containers")
+			return statsReader(t, sampleStatsResponse()), nil
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	statuses, err := client.GetServiceStatusWithStats(context.Background(), "app", []string{"web", "db"})
+	require.NoError(t, err)
+
+	byName := make(map[string]ContainerStatus)
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+	require.NotNil(t, byName["web"].Stats)
+	assert.Equal(t, uint64(200), byName["web"].Stats.MemoryUsage)
+	assert.Equal(t, 3, byName["web"].RestartCount)
+	assert.Nil(t, byName["db"].Stats)
+}
+
+func TestClient_GetServiceStatus_SkipsStats(t *testing.T) {
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "web1", Names: []string{"/app-web-1"}, State: StateRunning, Labels: map[string]string{ComposeServiceLabel: "web"}},
+			}, nil
+		},
+		ContainerInspectFunc: func(ctx context.Context, containerID string) (container.InspectResponse, error) {
+			resp := testhelpers.MockContainerJSON(containerID, "/"+containerID, "test:latest", "app", true)
+			resp.NetworkSettings = &container.NetworkSettings{}
+			return resp, nil
+		},
+		ContainerStatsFunc: func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+			t.Error("stats are only sampled when asked for")
+			return container.StatsResponseReader{}, assert.AnError
+		},
+	}
+	client := NewClientWithDependencies(mockDocker, nil, testhelpers.MockLogger())
+
+	statuses, err := client.GetServiceStatus(context.Background(), "app", []string{"web"})
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, StateRunning, statuses[0].State)
+	assert.Nil(t, statuses[0].Stats)
+}
@@ -109,8 +109,6 @@ type DockerServiceStatus struct {
 	State     DockerServiceState `json:"state"`
 	Health    DockerHealthStatus `json:"health"`
 	Uptime    time.Duration      `json:"uptime"`
-	CPUUsage  float64            `json:"cpu_usage"`
-	Memory    uint64             `json:"memory"`
 	StartedAt *time.Time         `json:"started_at,omitempty"`
 	Ports     []string           `json:"ports,omitempty"`
 	Image     string             `json:"image,omitempty"`
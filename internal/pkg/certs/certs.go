@@ -0,0 +1,418 @@
+// Package certs keeps a per-machine certificate authority in
+// ~/.otto-stack/ca and issues the certificates services and projects serve
+// TLS with. Clients trust them by trusting its CA certificate.
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+const (
+	caValidity = 10 * 365 * 24 * time.Hour
+	// certValidity is the longest validity Apple platforms accept for a
+	// certificate from a CA in the user's trust store
+	certValidity = 825 * 24 * time.Hour
+	// renewBefore is how long before it expires a certificate is reissued
+	renewBefore = 30 * 24 * time.Hour
+	// backdate allows for clocks, such as a container's, running behind
+	backdate = time.Hour
+	// createRetries and createDelay bound the wait for another otto-stack
+	// to finish creating the authority
+	createRetries = 50
+	createDelay   = 100 * time.Millisecond
+
+	serialBits   = 128
+	pemCert      = "CERTIFICATE"
+	pemKey       = "PRIVATE KEY"
+	organization = core.AppName
+)
+
+// Authority is the local certificate authority
+type Authority struct {
+	dir  string
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// Certificate is a certificate the authority issued, with its key and a copy
+// of the CA certificate in Dir
+type Certificate struct {
+	Dir      string
+	Hosts    []string
+	NotAfter time.Time
+}
+
+// CertFile is the path of the certificate
+func (c Certificate) CertFile() string {
+	return filepath.Join(c.Dir, core.TLSCertFile)
+}
+
+// KeyFile is the path of the certificate's private key
+func (c Certificate) KeyFile() string {
+	return filepath.Join(c.Dir, core.TLSKeyFile)
+}
+
+// CAFile is the path of the copy of the CA certificate
+func (c Certificate) CAFile() string {
+	return filepath.Join(c.Dir, core.CACertFile)
+}
+
+// DefaultDir is where the authority is kept, ~/.otto-stack/ca
+func DefaultDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, core.OttoStackDir, core.CADir), nil
+}
+
+// IssuedDir is the directory the authority in caDir keeps the certificate
+// named name in
+func IssuedDir(caDir, name string) string {
+	return filepath.Join(caDir, core.CAIssuedDir, strings.ReplaceAll(name, "*", "_"))
+}
+
+// Open loads the authority in dir, normally ~/.otto-stack/ca, creating it
+// the first time. created reports whether it did.
+func Open(dir string) (authority *Authority, created bool, err error) {
+	authority, err = load(dir)
+	if err == nil {
+		return authority, false, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, false, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsCaFailed, dir), err)
+	}
+
+	authority, err = create(dir)
+	if errors.Is(err, fs.ErrExist) {
+		// Another otto-stack created it first
+		authority, err = awaitCreated(dir)
+		created = false
+	} else {
+		created = err == nil
+	}
+	if err != nil {
+		return nil, false, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsCaFailed, dir), err)
+	}
+	return authority, created, nil
+}
+
+// CertFile is the path of the CA certificate clients trust
+func (a *Authority) CertFile() string {
+	return filepath.Join(a.dir, core.CACertFile)
+}
+
+// NotAfter is when the CA certificate expires
+func (a *Authority) NotAfter() time.Time {
+	return a.cert.NotAfter
+}
+
+// IssuedDir is the directory the certificate named name is kept in
+func (a *Authority) IssuedDir(name string) string {
+	return IssuedDir(a.dir, name)
+}
+
+// Ensure returns the certificate in dir, issuing a new one for hosts when
+// there is none, it does not cover every host, it expires within 30 days or
+// another CA signed it. issued reports whether it did.
+func (a *Authority) Ensure(dir string, hosts []string) (cert Certificate, issued bool, err error) {
+	if current, ok := a.current(dir, hosts); ok {
+		return current, false, nil
+	}
+	cert, err = a.issue(dir, hosts)
+	if err != nil {
+		return Certificate{}, false, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsIssueFailed, strings.Join(hosts, ", ")), err)
+	}
+	return cert, true, nil
+}
+
+// List returns the certificates the authority issued, by directory name
+func (a *Authority) List() ([]Certificate, error) {
+	root := filepath.Join(a.dir, core.CAIssuedDir)
+	entries, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsCaFailed, a.dir), err)
+	}
+
+	var issued []Certificate
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		cert, err := readCert(filepath.Join(dir, core.TLSCertFile))
+		if err != nil {
+			continue
+		}
+		issued = append(issued, Certificate{Dir: dir, Hosts: certHosts(cert), NotAfter: cert.NotAfter})
+	}
+	return issued, nil
+}
+
+// ValidHost reports whether host can be put in a certificate: an IP address,
+// or a hostname whose first label may be a wildcard
+func ValidHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	labels := strings.Split(strings.TrimPrefix(host, "*."), ".")
+	for _, label := range labels {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// current returns the certificate in dir if it can still be served for hosts
+func (a *Authority) current(dir string, hosts []string) (Certificate, bool) {
+	cert, err := readCert(filepath.Join(dir, core.TLSCertFile))
+	if err != nil {
+		return Certificate{}, false
+	}
+	if _, err := os.Stat(filepath.Join(dir, core.TLSKeyFile)); err != nil {
+		return Certificate{}, false
+	}
+	if cert.CheckSignatureFrom(a.cert) != nil || time.Until(cert.NotAfter) < renewBefore {
+		return Certificate{}, false
+	}
+	for _, host := range hosts {
+		if cert.VerifyHostname(host) != nil {
+			return Certificate{}, false
+		}
+	}
+	return Certificate{Dir: dir, Hosts: certHosts(cert), NotAfter: cert.NotAfter}, true
+}
+
+// issue writes a new key and certificate for hosts to dir, with a copy of
+// the CA certificate
+func (a *Authority) issue(dir string, hosts []string) (Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Certificate{}, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return Certificate{}, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{organization}, CommonName: hosts[0]},
+		NotBefore:    now.Add(-backdate),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		return Certificate{}, err
+	}
+	if err := os.MkdirAll(dir, core.PermReadWriteExec); err != nil {
+		return Certificate{}, err
+	}
+	if err := writeKey(filepath.Join(dir, core.TLSKeyFile), key, false); err != nil {
+		return Certificate{}, err
+	}
+	if err := writePEM(filepath.Join(dir, core.TLSCertFile), pemCert, der); err != nil {
+		return Certificate{}, err
+	}
+	if err := writePEM(filepath.Join(dir, core.CACertFile), pemCert, a.cert.Raw); err != nil {
+		return Certificate{}, err
+	}
+	return Certificate{Dir: dir, Hosts: slices.Clone(hosts), NotAfter: template.NotAfter}, nil
+}
+
+// awaitCreated loads the authority another otto-stack is creating in dir,
+// waiting for it to write the certificate
+func awaitCreated(dir string) (*Authority, error) {
+	for range createRetries {
+		authority, err := load(dir)
+		if !errors.Is(err, fs.ErrNotExist) {
+			return authority, err
+		}
+		time.Sleep(createDelay)
+	}
+	return load(dir)
+}
+
+// create makes a new authority in dir. Its key is written first and only if
+// it does not exist, so two otto-stacks cannot replace each other's, and its
+// certificate last, so the authority loads once both are complete.
+func create(dir string) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	name := core.AppName + " local CA"
+	if host, err := os.Hostname(); err == nil {
+		name += " (" + host + ")"
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{organization}, CommonName: name},
+		NotBefore:             now.Add(-backdate),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, core.PermReadWriteExec); err != nil {
+		return nil, err
+	}
+	if err := writeKey(filepath.Join(dir, core.CAKeyFile), key, true); err != nil {
+		return nil, err
+	}
+	if err := writePEM(filepath.Join(dir, core.CACertFile), pemCert, der); err != nil {
+		return nil, err
+	}
+	return &Authority{dir: dir, cert: cert, key: key}, nil
+}
+
+// load reads the authority in dir
+func load(dir string) (*Authority, error) {
+	cert, err := readCert(filepath.Join(dir, core.CACertFile))
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, core.CAKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New(messages.ErrorsCertsInvalidPem)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, errors.New(messages.ErrorsCertsInvalidPem)
+	}
+	return &Authority{dir: dir, cert: cert, key: key}, nil
+}
+
+func readCert(path string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemCert {
+		return nil, errors.New(messages.ErrorsCertsInvalidPem)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// writeKey writes a private key readable by its owner only. With exclusive
+// it fails with fs.ErrExist when the file exists.
+func writeKey(path string, key *ecdsa.PrivateKey, exclusive bool) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, pem.EncodeToMemory(&pem.Block{Type: pemKey, Bytes: der}), core.PermPrivate, exclusive)
+}
+
+func writePEM(path, blockType string, der []byte) error {
+	return writeFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), core.PermReadWrite, false)
+}
+
+// writeFile writes data to a temp file next to path that is then moved to
+// path, so readers never see a partial file. With exclusive it is linked
+// rather than renamed and fails with fs.ErrExist when path exists.
+func writeFile(path string, data []byte, perm os.FileMode, exclusive bool) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := f.Name()
+	defer func() { _ = os.Remove(tempPath) }()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tempPath, perm); err != nil {
+		return err
+	}
+	if exclusive {
+		return os.Link(tempPath, path)
+	}
+	return os.Rename(tempPath, path)
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+}
+
+// certHosts lists the names and addresses a certificate is for
+func certHosts(cert *x509.Certificate) []string {
+	hosts := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		hosts = append(hosts, ip.String())
+	}
+	return hosts
+}
@@ -0,0 +1,133 @@
+//go:build unit
+
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestOpen_CreatesThenLoads(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), core.CADir)
+
+	authority, created, err := Open(dir)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.FileExists(t, authority.CertFile())
+
+	info, err := os.Stat(filepath.Join(dir, core.CAKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(core.PermPrivate), info.Mode().Perm())
+
+	again, created, err := Open(dir)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, authority.NotAfter(), again.NotAfter())
+}
+
+func TestOpen_ConcurrentCreatesShareOneAuthority(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), core.CADir)
+
+	const openers = 8
+	authorities := make([]*Authority, openers)
+	errs := make([]error, openers)
+	var wg sync.WaitGroup
+	for i := range openers {
+		wg.Go(func() { authorities[i], _, errs[i] = Open(dir) })
+	}
+	wg.Wait()
+
+	for i := range openers {
+		require.NoError(t, errs[i])
+		assert.Equal(t, authorities[0].cert.Raw, authorities[i].cert.Raw)
+	}
+	loaded, err := load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, authorities[0].cert.Raw, loaded.cert.Raw)
+	assert.True(t, loaded.key.Public().(*ecdsa.PublicKey).Equal(loaded.cert.PublicKey))
+
+	temps, err := filepath.Glob(filepath.Join(dir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, temps)
+}
+
+func TestEnsure_IssuesVerifiableCertificate(t *testing.T) {
+	authority, _, err := Open(t.TempDir())
+	require.NoError(t, err)
+	hosts := []string{"postgres.shop.localhost", "postgres", "localhost", "127.0.0.1"}
+
+	cert, issued, err := authority.Ensure(authority.IssuedDir(hosts[0]), hosts)
+	require.NoError(t, err)
+	assert.True(t, issued)
+	assert.FileExists(t, cert.KeyFile())
+
+	pool := x509.NewCertPool()
+	caPEM, err := os.ReadFile(cert.CAFile())
+	require.NoError(t, err)
+	require.True(t, pool.AppendCertsFromPEM(caPEM))
+	leaf, err := readCert(cert.CertFile())
+	require.NoError(t, err)
+	for _, host := range hosts {
+		_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool})
+		assert.NoError(t, err, host)
+	}
+}
+
+func TestEnsure_KeepsCurrentAndReissues(t *testing.T) {
+	authority, _, err := Open(t.TempDir())
+	require.NoError(t, err)
+	dir := authority.IssuedDir("api.localhost")
+
+	_, _, err = authority.Ensure(dir, []string{"api.localhost"})
+	require.NoError(t, err)
+
+	_, issued, err := authority.Ensure(dir, []string{"api.localhost"})
+	require.NoError(t, err)
+	assert.False(t, issued, "a current certificate is kept")
+
+	cert, issued, err := authority.Ensure(dir, []string{"api.localhost", "::1"})
+	require.NoError(t, err)
+	assert.True(t, issued, "a new host needs a new certificate")
+	assert.Equal(t, []string{"api.localhost", "::1"}, cert.Hosts)
+
+	other, _, err := Open(t.TempDir())
+	require.NoError(t, err)
+	_, issued, err = other.Ensure(dir, []string{"api.localhost"})
+	require.NoError(t, err)
+	assert.True(t, issued, "another CA's certificate is replaced")
+}
+
+func TestList(t *testing.T) {
+	authority, _, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	issued, err := authority.List()
+	require.NoError(t, err)
+	assert.Empty(t, issued)
+
+	_, _, err = authority.Ensure(authority.IssuedDir("*.shop.localhost"), []string{"*.shop.localhost"})
+	require.NoError(t, err)
+	issued, err = authority.List()
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, "_.shop.localhost", filepath.Base(issued[0].Dir))
+	assert.Equal(t, []string{"*.shop.localhost"}, issued[0].Hosts)
+}
+
+func TestValidHost(t *testing.T) {
+	for _, host := range []string{"localhost", "api.shop.localhost", "*.shop.localhost", "127.0.0.1", "::1", "my_host"} {
+		assert.True(t, ValidHost(host), host)
+	}
+	for _, host := range []string{"", "a..b", "-a.localhost", "a b", "*", "a.*.localhost", "https://x"} {
+		assert.False(t, ValidHost(host), host)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/logger"
 	"github.com/spf13/cobra"
 )
@@ -58,6 +59,7 @@ func FormatError(flags Flags, err error) error {
 	if flags.JSON {
 		errorOutput := ErrorOutput{
 			Error:    err.Error(),
+			Output:   pkgerrors.OutputOf(err),
 			ExitCode: core.ExitError,
 		}
 		if encodeErr := json.NewEncoder(os.Stdout).Encode(errorOutput); encodeErr != nil {
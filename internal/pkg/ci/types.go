@@ -9,6 +9,7 @@ type Output struct {
 // ErrorOutput represents error output
 type ErrorOutput struct {
 	Error    string `json:"error"`
+	Output   string `json:"output,omitempty"`
 	ExitCode int    `json:"exit_code"`
 }
 
@@ -29,3 +30,18 @@ type ServicesOutput struct {
 	Services []any `json:"services"`
 	Count    int   `json:"count"`
 }
+
+// ValidateOutput represents configuration validation output
+type ValidateOutput struct {
+	Valid  bool   `json:"valid"`
+	Issues []any  `json:"issues"`
+	Count  int    `json:"count"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ProjectsOutput represents the project index output
+type ProjectsOutput struct {
+	Projects []any `json:"projects"`
+	Pruned   []any `json:"pruned,omitempty"`
+	Count    int   `json:"count"`
+}
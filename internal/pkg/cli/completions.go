@@ -4,6 +4,7 @@ import (
 	"slices"
 	"sort"
 
+	"github.com/otto-nation/otto-stack/internal/core"
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
 	"github.com/otto-nation/otto-stack/internal/pkg/registry"
 	"github.com/otto-nation/otto-stack/internal/pkg/services"
@@ -11,15 +12,25 @@ import (
 )
 
 // serviceNameCommands lists the commands that accept service names as positional args.
-var serviceNameCommands = []string{"up", "down", "restart", "status", "logs", "deps", "conflicts"}
+var serviceNameCommands = []string{"up", "down", "restart", "pause", "unpause", "status", "stats", "events", "logs", "deps", "conflicts", "doctor"}
+
+// serviceNameFlags lists flags, per command, whose value is a service name.
+var serviceNameFlags = map[string][]string{
+	core.CommandLogs: {core.FlagInit},
+}
 
 // RegisterCompletions wires service-name tab completion onto commands that accept
-// service names as positional arguments.
+// service names as positional arguments or flag values.
 func RegisterCompletions(rootCmd *cobra.Command) {
 	for _, sub := range rootCmd.Commands() {
 		if slices.Contains(serviceNameCommands, sub.Name()) {
 			sub.ValidArgsFunction = completeServiceName
 		}
+		for _, flag := range serviceNameFlags[sub.Name()] {
+			_ = sub.RegisterFlagCompletionFunc(flag, func(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return completeCatalogServices(toComplete)
+			})
+		}
 	}
 }
 
@@ -49,6 +49,9 @@ type SharingSpec struct {
 	// Services is the optional whitelist built during init. true = shared globally,
 	// false/absent = project-local. Empty means share all shareable catalog services.
 	Services map[string]bool
+	// Instances maps a service to the suffix of the second shared instance
+	// the project uses instead of the default one
+	Instances map[string]string
 }
 
 // AdvancedSpec contains advanced init-time options
@@ -41,6 +41,16 @@ func (d *Detector) DetectContext() (ExecutionMode, error) {
 		}, nil
 	}
 
+	// A project takes precedence, so a member's own directory stays project-scoped
+	workspace, err := d.findWorkspace()
+	if workspace != nil || err != nil {
+		return &WorkspaceMode{
+			Workspace: workspace,
+			Shared:    sharedInfo,
+			Err:       err,
+		}, nil
+	}
+
 	return &SharedMode{
 		Shared: sharedInfo,
 	}, nil
@@ -97,3 +107,25 @@ func (d *Detector) findProjectRoot() (*ProjectInfo, error) {
 
 	return nil, nil
 }
+
+// findWorkspace walks up the directory tree to find otto-stack.workspace.yaml.
+// It returns an error when the file it finds cannot be loaded.
+func (d *Detector) findWorkspace() (*WorkspaceInfo, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	for dir := cwd; ; dir = filepath.Dir(dir) {
+		file := filepath.Join(dir, core.WorkspaceFileName)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return LoadWorkspace(file)
+		}
+
+		if parent := filepath.Dir(dir); parent == dir {
+			break // Reached root
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,91 @@
+package context
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// WorkspaceInfo describes an otto-stack.workspace.yaml and its member projects
+type WorkspaceInfo struct {
+	Root     string         // Absolute path to the directory holding the workspace file
+	File     string         // Absolute path to otto-stack.workspace.yaml
+	Projects []*ProjectInfo // Member projects in the order the file lists them
+}
+
+// WorkspaceMode for operations across every project in a workspace. Err is
+// why the workspace file could not be loaded, leaving Workspace nil, so that
+// only commands that act on the workspace fail.
+type WorkspaceMode struct {
+	Workspace *WorkspaceInfo
+	Shared    *SharedInfo
+	Err       error
+}
+
+func (w *WorkspaceMode) SharedRoot() string { return w.Shared.Root }
+func (w *WorkspaceMode) isExecutionMode()   {}
+
+// workspaceFile is the on-disk format of otto-stack.workspace.yaml
+type workspaceFile struct {
+	Projects []string `yaml:"projects"`
+}
+
+// LoadWorkspace reads a workspace file. Project paths are relative to the
+// file's directory and each must hold an initialized otto-stack project.
+func LoadWorkspace(file string) (*WorkspaceInfo, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeInvalid, file, messages.ErrorsWorkspaceReadFailed, file)
+	}
+
+	data, err := os.ReadFile(absFile)
+	if err != nil {
+		return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeOperationFail, absFile, messages.ErrorsWorkspaceReadFailed, absFile)
+	}
+
+	var parsed workspaceFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeInvalid, absFile, messages.ErrorsWorkspaceParseFailed, absFile, err)
+	}
+	if len(parsed.Projects) == 0 {
+		return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeInvalid, absFile, messages.ValidationWorkspaceNoProjects, absFile)
+	}
+
+	root := filepath.Dir(absFile)
+	workspace := &WorkspaceInfo{Root: root, File: absFile}
+	seen := make(map[string]bool, len(parsed.Projects))
+	for _, path := range parsed.Projects {
+		dir := path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+
+		configDir := filepath.Join(dir, core.OttoStackDir)
+		if _, err := os.Stat(filepath.Join(configDir, core.ConfigFileName)); err != nil {
+			return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeInvalid, absFile, messages.ValidationWorkspaceProjectNotInitialized, path)
+		}
+		workspace.Projects = append(workspace.Projects, NewProjectInfo(configDir))
+	}
+	return workspace, nil
+}
+
+// RelPath returns a member project's path relative to the workspace root
+func (w *WorkspaceInfo) RelPath(project *ProjectInfo) string {
+	if rel, err := filepath.Rel(w.Root, project.Root); err == nil {
+		return rel
+	}
+	return project.Root
+}
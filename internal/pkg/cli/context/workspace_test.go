@@ -0,0 +1,109 @@
+//go:build unit
+
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func writeWorkspaceProject(t *testing.T, dir string) {
+	t.Helper()
+	configDir := filepath.Join(dir, core.OttoStackDir)
+	require.NoError(t, os.MkdirAll(configDir, core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, core.ConfigFileName), []byte("project:\n  name: app\n"), core.PermReadWrite))
+}
+
+func writeWorkspaceFile(t *testing.T, dir, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, core.WorkspaceFileName)
+	require.NoError(t, os.WriteFile(file, []byte(content), core.PermReadWrite))
+	return file
+}
+
+func TestLoadWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceProject(t, filepath.Join(root, "api"))
+	writeWorkspaceProject(t, filepath.Join(root, "web"))
+	file := writeWorkspaceFile(t, root, "projects:\n  - api\n  - web\n  - ./api\n")
+
+	workspace, err := LoadWorkspace(file)
+	require.NoError(t, err)
+
+	assert.Equal(t, root, workspace.Root)
+	require.Len(t, workspace.Projects, 2, "a project listed twice is included once")
+	assert.Equal(t, filepath.Join(root, "api"), workspace.Projects[0].Root)
+	assert.Equal(t, filepath.Join(root, "web", core.OttoStackDir, core.ConfigFileName), workspace.Projects[1].ConfigFile)
+	assert.Equal(t, "web", workspace.RelPath(workspace.Projects[1]))
+}
+
+func TestLoadWorkspace_Invalid(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceProject(t, filepath.Join(root, "api"))
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"no projects", "projects: []\n", "lists no projects"},
+		{"uninitialized project", "projects:\n  - api\n  - missing\n", "'missing' is not an otto-stack project"},
+		{"unknown key", "project:\n  - api\n", "Failed to parse workspace file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadWorkspace(writeWorkspaceFile(t, root, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestDetector_DetectContext_Workspace(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	writeWorkspaceProject(t, filepath.Join(root, "api"))
+	writeWorkspaceFile(t, root, "projects:\n  - api\n")
+	require.NoError(t, os.Mkdir(filepath.Join(root, "docs"), core.PermReadWriteExec))
+	t.Setenv("HOME", t.TempDir())
+
+	detector, err := NewDetector()
+	require.NoError(t, err)
+
+	t.Chdir(filepath.Join(root, "docs"))
+	mode, err := detector.DetectContext()
+	require.NoError(t, err)
+	workspace, ok := mode.(*WorkspaceMode)
+	require.True(t, ok, "a directory under the workspace file is in workspace mode")
+	assert.Equal(t, root, workspace.Workspace.Root)
+	assert.NotEmpty(t, workspace.SharedRoot())
+
+	t.Chdir(filepath.Join(root, "api"))
+	mode, err = detector.DetectContext()
+	require.NoError(t, err)
+	assert.IsType(t, &ProjectMode{}, mode, "a member project takes precedence over its workspace")
+}
+
+func TestDetector_DetectContext_InvalidWorkspace(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFile(t, root, "projects:\n  - missing\n")
+	t.Setenv("HOME", t.TempDir())
+
+	detector, err := NewDetector()
+	require.NoError(t, err)
+
+	t.Chdir(root)
+	mode, err := detector.DetectContext()
+	require.NoError(t, err, "commands that do not act on the workspace still run")
+	workspace, ok := mode.(*WorkspaceMode)
+	require.True(t, ok)
+	assert.Nil(t, workspace.Workspace)
+	assert.Error(t, workspace.Err)
+	assert.NotEmpty(t, workspace.SharedRoot())
+}
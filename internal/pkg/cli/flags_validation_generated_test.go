@@ -38,7 +38,11 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 		"doctor": {
 			handlerPath: "internal/pkg/cli/handlers/project/doctor.go",
 			flags: []string{
+				"bundle",
+				"bundle-lines",
+				"fix",
 				"format",
+				"yes",
 			},
 		},
 		"down": {
@@ -52,6 +56,15 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 				"volumes",
 			},
 		},
+		"events": {
+			handlerPath: "internal/pkg/cli/handlers/operations/events.go",
+			flags: []string{
+				"format",
+				"project",
+				"restart-threshold",
+				"restart-window",
+			},
+		},
 		"init": {
 			handlerPath: "internal/pkg/cli/handlers/project/init.go",
 			flags: []string{
@@ -67,9 +80,36 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 			handlerPath: "internal/pkg/cli/handlers/operations/logs.go",
 			flags: []string{
 				"follow",
+				"grep",
+				"init",
+				"json-pretty",
+				"level",
+				"previous",
+				"record",
 				"since",
 				"tail",
 				"timestamps",
+				"until",
+			},
+		},
+		"pause": {
+			handlerPath: "internal/pkg/cli/handlers/lifecycle/pause.go",
+			flags: []string{
+				"global",
+				"project",
+			},
+		},
+		"projects": {
+			handlerPath: "internal/pkg/cli/handlers/project/projects.go",
+			flags: []string{
+				"format",
+				"prune",
+			},
+		},
+		"proxy": {
+			handlerPath: "internal/pkg/cli/handlers/utility/proxy.go",
+			flags: []string{
+				"port",
 			},
 		},
 		"restart": {
@@ -81,6 +121,12 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 				"timeout",
 			},
 		},
+		"schema": {
+			handlerPath: "internal/pkg/cli/handlers/project/schema.go",
+			flags: []string{
+				"dir",
+			},
+		},
 		"services": {
 			handlerPath: "internal/pkg/cli/handlers/project/services.go",
 			flags: []string{
@@ -88,13 +134,39 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 				"format",
 			},
 		},
+		"shared": {
+			handlerPath: "internal/pkg/cli/handlers/lifecycle/shared.go",
+			flags: []string{
+				"format",
+				"idle",
+				"project",
+				"remove",
+				"to-project",
+			},
+		},
+		"stats": {
+			handlerPath: "internal/pkg/cli/handlers/operations/stats.go",
+			flags: []string{
+				"format",
+				"no-stream",
+			},
+		},
 		"status": {
 			handlerPath: "internal/pkg/cli/handlers/operations/status.go",
 			flags: []string{
 				"all",
 				"format",
+				"interval",
 				"project",
 				"shared",
+				"watch",
+			},
+		},
+		"unpause": {
+			handlerPath: "internal/pkg/cli/handlers/lifecycle/unpause.go",
+			flags: []string{
+				"global",
+				"project",
 			},
 		},
 		"up": {
@@ -112,6 +184,7 @@ func TestAllFlagsAreImplemented(t *testing.T) {
 		"validate": {
 			handlerPath: "internal/pkg/cli/handlers/project/validate.go",
 			flags: []string{
+				"format",
 				"strict",
 			},
 		},
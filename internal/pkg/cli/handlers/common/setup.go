@@ -3,7 +3,11 @@ package common
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/core/docker"
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
@@ -87,3 +91,63 @@ func VerifyServicesInRegistry(serviceNames []string, reg *registry.Registry) err
 	}
 	return nil
 }
+
+// OttoStackHome returns ~/.otto-stack, where otto-stack keeps what spans
+// projects
+func OttoStackHome() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, core.OttoStackDir), nil
+}
+
+// ProjectIndex returns the manager for the project index in ~/.otto-stack
+func ProjectIndex() (*registry.ProjectIndexManager, error) {
+	home, err := OttoStackHome()
+	if err != nil {
+		return nil, err
+	}
+	return registry.NewProjectIndexManager(home), nil
+}
+
+// RecordProject notes the project in the working directory in the project
+// index behind the projects command. The index is a convenience, so failing
+// to update it is only a warning.
+func RecordProject(name string, enabledServices []string, out base.Output) {
+	index, err := ProjectIndex()
+	if err != nil {
+		return
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	if err := index.Touch(name, root, enabledServices); err != nil {
+		out.Warning(messages.WarningsProjectIndexUpdateFailed, err)
+	}
+}
+
+// SharedRoot returns the directory of the shared container registry in
+// ~/.otto-stack
+func SharedRoot() (string, error) {
+	home, err := OttoStackHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, core.SharedDir), nil
+}
+
+// TouchShared records that the project used its shared containers, for
+// shared gc. Activity is advisory, so failures are ignored, and a machine
+// without a registry is left alone.
+func TouchShared(projectName string) {
+	sharedRoot, err := SharedRoot()
+	if err != nil {
+		return
+	}
+	if _, err := os.Stat(filepath.Join(sharedRoot, core.SharedRegistryFile)); err != nil {
+		return
+	}
+	_ = registry.NewManager(sharedRoot).Touch(projectName, time.Now())
+}
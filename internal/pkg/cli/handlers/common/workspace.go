@@ -0,0 +1,45 @@
+package common
+
+import (
+	"fmt"
+	"os"
+
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// ForEachWorkspaceProject runs fn for each workspace member in the order the
+// workspace file lists them. Like --project, it changes into the member's
+// directory first so config-relative paths resolve, and it restores the
+// working directory when done. It stops at the first error.
+func ForEachWorkspaceProject(mode *clicontext.WorkspaceMode, fn func(*clicontext.ProjectMode) error) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsContextDetectFailed, err)
+	}
+	defer func() { _ = os.Chdir(cwd) }()
+
+	for _, project := range mode.Workspace.Projects {
+		if err := os.Chdir(project.Root); err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsProjectDirChangeFailed, project.Root, err), err)
+		}
+		if err := fn(&clicontext.ProjectMode{Project: project, Shared: mode.Shared}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateWorkspace reports why the workspace could not be loaded, and
+// rejects service names across a workspace, where a service one member
+// defines may be unknown to the next
+func ValidateWorkspace(mode *clicontext.WorkspaceMode, args []string) error {
+	if mode.Err != nil {
+		return mode.Err
+	}
+	if len(args) > 0 {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ValidationWorkspaceServicesUnsupported, nil)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+//go:build unit
+
+package common
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+)
+
+func TestForEachWorkspaceProject(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	var projects []*clicontext.ProjectInfo
+	for _, name := range []string{"api", "web"} {
+		require.NoError(t, os.Mkdir(filepath.Join(root, name), 0o755))
+		projects = append(projects, clicontext.NewProjectInfo(filepath.Join(root, name, ".otto-stack")))
+	}
+	mode := &clicontext.WorkspaceMode{
+		Workspace: &clicontext.WorkspaceInfo{Root: root, Projects: projects},
+		Shared:    &clicontext.SharedInfo{Root: t.TempDir()},
+	}
+	t.Chdir(root)
+
+	var visited []string
+	err = ForEachWorkspaceProject(mode, func(project *clicontext.ProjectMode) error {
+		cwd, _ := os.Getwd()
+		visited = append(visited, cwd)
+		assert.Equal(t, mode.Shared, project.Shared)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{projects[0].Root, projects[1].Root}, visited)
+
+	cwd, _ := os.Getwd()
+	assert.Equal(t, root, cwd, "the working directory is restored")
+
+	stop := errors.New("stop")
+	calls := 0
+	err = ForEachWorkspaceProject(mode, func(*clicontext.ProjectMode) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestValidateWorkspace(t *testing.T) {
+	mode := &clicontext.WorkspaceMode{Workspace: &clicontext.WorkspaceInfo{}}
+	assert.NoError(t, ValidateWorkspace(mode, nil))
+	assert.Error(t, ValidateWorkspace(mode, []string{"postgres"}))
+
+	loadErr := errors.New("member not initialized")
+	assert.ErrorIs(t, ValidateWorkspace(&clicontext.WorkspaceMode{Err: loadErr}, nil), loadErr)
+}
@@ -2,10 +2,13 @@ package lifecycle
 
 import (
 	"context"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/AlecAivazis/survey/v2"
@@ -17,17 +20,25 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/ci"
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/project"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
 	"github.com/otto-nation/otto-stack/internal/pkg/display"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/provision"
 	"github.com/otto-nation/otto-stack/internal/pkg/registry"
 	"github.com/otto-nation/otto-stack/internal/pkg/services"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 )
 
 // DownHandler handles the down command
-type DownHandler struct{}
+type DownHandler struct {
+	// workspaceShared maps each shared service found during a workspace down
+	// to the members using it. They are left running until every member is
+	// down, then stopped once if nothing outside the workspace still uses them.
+	workspaceShared map[string][]string
+}
 
 // NewDownHandler creates a new down handler
 func NewDownHandler() *DownHandler {
@@ -58,6 +69,8 @@ func (h *DownHandler) Handle(ctx context.Context, cmd *cobra.Command, args []str
 			return h.handleGlobalContext(ctx, cmd, args, base, mode.Shared)
 		case *clicontext.SharedMode:
 			return h.handleGlobalContext(ctx, cmd, args, base, mode.Shared)
+		case *clicontext.WorkspaceMode:
+			return h.handleGlobalContext(ctx, cmd, args, base, mode.Shared)
 		}
 	}
 
@@ -74,11 +87,113 @@ func (h *DownHandler) Handle(ctx context.Context, cmd *cobra.Command, args []str
 		return h.handleProjectContext(ctx, cmd, args, base, mode)
 	case *clicontext.SharedMode:
 		return h.handleGlobalContext(ctx, cmd, args, base, mode.Shared)
+	case *clicontext.WorkspaceMode:
+		if err := h.handleWorkspaceContext(ctx, cmd, args, base, mode); err != nil {
+			return err
+		}
+		if showAll {
+			return h.handleGlobalContext(ctx, cmd, args, base, mode.Shared)
+		}
+		return nil
 	default:
 		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
 	}
 }
 
+// handleWorkspaceContext stops every project in the workspace in turn, then
+// offers to stop the shared containers only workspace members were using
+func (h *DownHandler) handleWorkspaceContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, mode *clicontext.WorkspaceMode) error {
+	if err := common.ValidateWorkspace(mode, args); err != nil {
+		return err
+	}
+	base.Output.Header(messages.WorkspaceHeader, mode.Workspace.Root, len(mode.Workspace.Projects))
+
+	h.workspaceShared = make(map[string][]string)
+	defer func() { h.workspaceShared = nil }()
+
+	err := common.ForEachWorkspaceProject(mode, func(project *clicontext.ProjectMode) error {
+		base.Output.Header(messages.WorkspaceProjectHeader, filepath.Base(project.Project.Root), mode.Workspace.RelPath(project.Project))
+		return h.handleProjectContext(ctx, cmd, args, base, project)
+	})
+	if err != nil {
+		return err
+	}
+
+	// --all goes on to stop every shared container
+	if stopAll, _ := cmd.Flags().GetBool(docker.FlagAll); stopAll {
+		return nil
+	}
+	return h.stopWorkspaceShared(ctx, base, mode.Shared, ci.GetFlags(cmd).NonInteractive)
+}
+
+// deferWorkspaceShared takes the shared services out of a member's stop list
+// and records that the member uses them
+func (h *DownHandler) deferWorkspaceShared(serviceConfigs []types.ServiceConfig, projectName, sharedRoot string) ([]types.ServiceConfig, error) {
+	reg := registry.NewManager(sharedRoot)
+	if _, err := reg.Load(); err != nil {
+		return nil, pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	sharedServices := h.findSharedServices(serviceConfigs, reg)
+	for _, svc := range sharedServices {
+		h.workspaceShared[svc] = append(h.workspaceShared[svc], projectName)
+	}
+	return h.filterOutShared(sharedServices, serviceConfigs), nil
+}
+
+// unusedWorkspaceShared returns the recorded shared services that no project
+// outside the workspace uses
+func (h *DownHandler) unusedWorkspaceShared(reg *registry.Manager) []string {
+	var unused []string
+	for _, svc := range slices.Sorted(maps.Keys(h.workspaceShared)) {
+		container, err := reg.Get(svc)
+		if err != nil || container == nil {
+			continue
+		}
+		members := h.workspaceShared[svc]
+		if !slices.ContainsFunc(container.Projects, func(ref registry.ProjectRef) bool { return !slices.Contains(members, ref.Name) }) {
+			unused = append(unused, svc)
+		}
+	}
+	return unused
+}
+
+// stopWorkspaceShared stops the shared containers whose every user is a
+// workspace member, after confirmation. Containers a project outside the
+// workspace still uses keep running and keep their registrations.
+func (h *DownHandler) stopWorkspaceShared(ctx context.Context, base *base.BaseCommand, sharedInfo *clicontext.SharedInfo, nonInteractive bool) error {
+	if len(h.workspaceShared) == 0 {
+		return nil
+	}
+
+	reg := registry.NewManager(sharedInfo.Root)
+	unused := h.unusedWorkspaceShared(reg)
+	if len(unused) == 0 {
+		return nil
+	}
+
+	base.Output.Warning(messages.WorkspaceSharedUnused, strings.Join(unused, ", "))
+	if nonInteractive {
+		base.Output.Info(messages.SharedSkippingNonInteractive, len(unused))
+		return nil
+	}
+	if !h.promptStopShared(base) {
+		base.Output.Info(messages.SharedSkipping)
+		return nil
+	}
+
+	h.stopSharedContainersViaCompose(ctx, sharedInfo.Root, unused, base)
+	for _, svc := range unused {
+		for _, project := range h.workspaceShared[svc] {
+			if err := reg.Unregister(svc, project); err != nil {
+				base.Output.Warning(messages.WarningsRegistryUnregisterFailed, svc, err)
+			}
+		}
+	}
+	base.Output.Success(messages.SharedStopped)
+	return nil
+}
+
 func (h *DownHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, execCtx *clicontext.ProjectMode) error {
 	base.Output.Header(messages.LifecycleStopping)
 
@@ -92,11 +207,19 @@ func (h *DownHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 	if err != nil {
 		return err
 	}
+	// Second shared instances are registered and run under their own name
+	serviceConfigs = project.UseSharedInstances(serviceConfigs, setup.Config.Sharing)
 
 	stopAll, _ := cmd.Flags().GetBool(docker.FlagAll)
 	ciFlags := ci.GetFlags(cmd)
 	// When --all is set, include shared containers without prompting — the user's intent is explicit.
-	if !stopAll {
+	// A workspace member leaves them for the workspace to stop once every member is down.
+	if h.workspaceShared != nil {
+		serviceConfigs, err = h.deferWorkspaceShared(serviceConfigs, setup.Config.Project.Name, execCtx.Shared.Root)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsServiceFilterSharedFailed, err)
+		}
+	} else if !stopAll {
 		serviceConfigs, err = h.filterSharedIfNeeded(serviceConfigs, execCtx.Shared.Root, base, ciFlags.NonInteractive)
 		if err != nil {
 			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsServiceFilterSharedFailed, err)
@@ -108,6 +231,15 @@ func (h *DownHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 		return nil
 	}
 
+	if h.workspaceShared == nil {
+		h.offerDropDatabases(ctx, serviceConfigs, setup, execCtx.Shared.Root, base, ciFlags.NonInteractive)
+	}
+
+	networker := provision.NewNetworkAttacher(setup.DockerClient)
+	if len(args) == 0 {
+		releaseProjectNetwork(ctx, setup.DockerClient, networker, execCtx.Shared.Root, setup.Config.Project.Name, base)
+	}
+
 	service, err := h.stopServices(ctx, cmd, setup, serviceConfigs)
 	if err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentServices, messages.ErrorsStackStopFailed, err)
@@ -116,6 +248,13 @@ func (h *DownHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 	base.Output.Success(messages.SuccessServicesStopped)
 	base.Output.Muted(messages.InfoProjectInfo, setup.Config.Project.Name)
 
+	// The whole stack is down, so the recorder has nothing left to follow
+	if len(args) == 0 {
+		if err := logrecorder.Stop(logrecorder.Dir()); err != nil {
+			base.Output.Warning(messages.WarningsLogRecorderStopFailed, err)
+		}
+	}
+
 	filteredNames := filterStatusQueryNames(serviceConfigs)
 	if statuses, statusErr := service.Status(ctx, services.StatusRequest{
 		Project:  setup.Config.Project.Name,
@@ -126,7 +265,27 @@ func (h *DownHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 	}
 
 	// Unregister shared containers after stopping
-	return h.unregisterSharedContainersForProject(serviceConfigs, setup.Config.Project.Name, execCtx.Shared.Root, base)
+	return h.unregisterSharedContainersForProject(serviceConfigs, setup.Config.Project.Name, execCtx.Shared.Root, networker, base)
+}
+
+// releaseProjectNetwork disconnects the containers of other projects from the
+// project's network: shared containers, and those of projects attached to it
+// through networks.attach. Any still connected would keep the network from
+// being removed with the rest of the stack.
+func releaseProjectNetwork(ctx context.Context, client *docker.Client, networker registry.Networker, sharedRoot, projectName string, base *base.BaseCommand) {
+	reg := registry.NewManager(sharedRoot)
+	reg.SetNetworker(networker)
+	if err := reg.DetachProject(ctx, projectName); err != nil {
+		base.Output.Warning(messages.WarningsNetworkDetachFailed, err)
+	}
+
+	peers, err := client.DisconnectOtherProjects(ctx, projectName)
+	if err != nil {
+		base.Output.Warning(messages.WarningsNetworkPeersDetachFailed, err)
+	}
+	if len(peers) > 0 {
+		base.Output.Warning(messages.WarningsNetworkPeersDetached, strings.Join(peers, ", "))
+	}
 }
 
 func (h *DownHandler) filterSharedIfNeeded(serviceConfigs []types.ServiceConfig, sharedRoot string, base *base.BaseCommand, nonInteractive bool) ([]types.ServiceConfig, error) {
@@ -303,11 +462,17 @@ func (h *DownHandler) promptStopAll(containers map[string]*registry.ContainerInf
 }
 
 func (h *DownHandler) unregisterSharedContainers(servicesToStop []string, sharedInfo *clicontext.SharedInfo, base *base.BaseCommand) error {
-	return h.unregisterSharedContainersForProject(h.serviceNamesToConfigs(servicesToStop), "global", sharedInfo.Root, base)
+	return h.unregisterSharedContainersForProject(h.serviceNamesToConfigs(servicesToStop), "global", sharedInfo.Root, nil, base)
 }
 
-func (h *DownHandler) unregisterSharedContainersForProject(serviceConfigs []types.ServiceConfig, projectName string, sharedRoot string, base *base.BaseCommand) error {
+// unregisterSharedContainersForProject drops the project's registrations on
+// the shared containers. With a networker, each container also leaves the
+// project's network.
+func (h *DownHandler) unregisterSharedContainersForProject(serviceConfigs []types.ServiceConfig, projectName string, sharedRoot string, networker registry.Networker, base *base.BaseCommand) error {
 	reg := registry.NewManager(sharedRoot)
+	if networker != nil {
+		reg.SetNetworker(networker)
+	}
 
 	for _, svc := range serviceConfigs {
 		if err := reg.Unregister(svc.Name, projectName); err != nil {
@@ -318,6 +483,54 @@ func (h *DownHandler) unregisterSharedContainersForProject(serviceConfigs []type
 	return nil
 }
 
+// offerDropDatabases offers to drop the databases provisioned for the project
+// on the shared containers it is about to stop using. This runs before they
+// stop, as dropping needs them running. Declined or non-interactive, the
+// database and its credentials are kept for the next up.
+func (h *DownHandler) offerDropDatabases(ctx context.Context, serviceConfigs []types.ServiceConfig, setup *common.CoreSetup, sharedRoot string, base *base.BaseCommand, nonInteractive bool) {
+	projectName := setup.Config.Project.Name
+	reg := registry.NewManager(sharedRoot)
+	creds, err := reg.ProjectCredentials(projectName)
+	if err != nil || len(creds) == 0 {
+		return
+	}
+	containers, err := reg.List()
+	if err != nil {
+		return
+	}
+	reg.SetProvisioner(provision.NewDatabaseProvisioner(setup.DockerClient))
+
+	for _, svc := range serviceConfigs {
+		c, ok := creds[svc.Name]
+		// A service the project moved to run itself keeps its credentials
+		container := containers[svc.Name]
+		if !ok || container == nil || !slices.ContainsFunc(container.Projects, func(r registry.ProjectRef) bool { return r.Name == projectName }) {
+			continue
+		}
+		if nonInteractive || !h.promptDropDatabase(c.Database, svc.Name) {
+			base.Output.Info(messages.SharedDatabaseKept, c.Database, svc.Name)
+			continue
+		}
+		if err := reg.DropCredentials(ctx, svc.Name, projectName); err != nil {
+			base.Output.Warning(messages.WarningsSharedDatabaseDropFailed, err)
+			continue
+		}
+		base.Output.Success(messages.SharedDatabaseDropped, c.Database, svc.Name)
+	}
+}
+
+func (h *DownHandler) promptDropDatabase(database, service string) bool {
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf(messages.PromptsDropSharedDatabase, database, service),
+		Default: false,
+	}
+	var confirmed bool
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return false
+	}
+	return confirmed
+}
+
 func (h *DownHandler) serviceNamesToConfigs(serviceNames []string) []types.ServiceConfig {
 	configs := make([]types.ServiceConfig, len(serviceNames))
 	for i, name := range serviceNames {
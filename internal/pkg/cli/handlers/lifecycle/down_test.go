@@ -1,10 +1,18 @@
 package lifecycle
 
 import (
+	"context"
 	"testing"
 
-	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/provision"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
 )
 
 func TestDownHandler_serviceNamesToConfigs(t *testing.T) {
@@ -29,3 +37,24 @@ func TestDownHandler_filterOutShared(t *testing.T) {
 	assert.Equal(t, "postgres", filtered[0].Name)
 	assert.Equal(t, "mysql", filtered[1].Name)
 }
+
+func TestReleaseProjectNetwork_DisconnectsAttachedPeer(t *testing.T) {
+	var disconnected []string
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(_ context.Context, _ container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "api", Names: []string{"/shop-api-1"}, Labels: map[string]string{docker.ComposeProjectLabel: "shop"}},
+				{ID: "worker", Names: []string{"/billing-worker-1"}, Labels: map[string]string{docker.ComposeProjectLabel: "billing"}},
+			}, nil
+		},
+		NetworkDisconnectFunc: func(_ context.Context, networkID, containerID string, _ bool) error {
+			disconnected = append(disconnected, networkID+" "+containerID)
+			return nil
+		},
+	}
+	client := docker.NewClientWithDependencies(mock, nil, nil)
+
+	releaseProjectNetwork(context.Background(), client, provision.NewNetworkAttacher(client), t.TempDir(), "shop", &base.BaseCommand{Output: ui.NewOutput()})
+	assert.Equal(t, []string{docker.ProjectNetworkName("shop") + " worker"}, disconnected,
+		"the attached project's container leaves the network so down can remove it")
+}
@@ -17,3 +17,13 @@ func TestNewRestartHandler(t *testing.T) {
 	handler := NewRestartHandler()
 	testhelpers.AssertValidConstructor(t, handler, nil, "RestartHandler")
 }
+
+func TestNewPauseHandler(t *testing.T) {
+	handler := NewPauseHandler()
+	testhelpers.AssertValidConstructor(t, handler, nil, "PauseHandler")
+}
+
+func TestNewUnpauseHandler(t *testing.T) {
+	handler := NewUnpauseHandler()
+	testhelpers.AssertValidConstructor(t, handler, nil, "UnpauseHandler")
+}
@@ -0,0 +1,297 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/project"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// PauseHandler handles the pause command
+type PauseHandler struct{}
+
+// NewPauseHandler creates a new pause handler
+func NewPauseHandler() *PauseHandler {
+	return &PauseHandler{}
+}
+
+// Handle executes the pause command
+func (h *PauseHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParsePauseFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	return pauseOperation.run(ctx, cmd, args, base, flags.Global, flags.Project)
+}
+
+// ValidateArgs validates the command arguments
+func (h *PauseHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *PauseHandler) GetRequiredFlags() []string {
+	return []string{}
+}
+
+// pauseOp describes one direction of the pause/unpause pair so both handlers
+// share context dispatch, shared-container handling and status rendering.
+type pauseOp struct {
+	header      string
+	dryRun      string
+	success     string
+	serviceDone string
+	nothingDone string
+	failed      string
+	// fromState is the container state the operation applies to; others are skipped.
+	fromState string
+	// warnShared lists the other projects using a shared container before acting on it.
+	warnShared bool
+	project    func(*services.Service, context.Context, services.PauseRequest) ([]string, error)
+	container  func(*docker.Client, context.Context, string) error
+}
+
+var pauseOperation = pauseOp{
+	header:      messages.LifecyclePausing,
+	dryRun:      messages.DryRunWouldPauseServices,
+	success:     messages.LifecyclePauseSuccess,
+	serviceDone: messages.SuccessPausedService,
+	nothingDone: messages.LifecycleNothingToPause,
+	failed:      messages.ErrorsServicePauseFailed,
+	fromState:   docker.StateRunning,
+	warnShared:  true,
+	project:     (*services.Service).Pause,
+	container:   (*docker.Client).PauseContainer,
+}
+
+var unpauseOperation = pauseOp{
+	header:      messages.LifecycleUnpausing,
+	dryRun:      messages.DryRunWouldUnpauseServices,
+	success:     messages.LifecycleUnpauseSuccess,
+	serviceDone: messages.SuccessUnpausedService,
+	nothingDone: messages.LifecycleNothingToUnpause,
+	failed:      messages.ErrorsServiceUnpauseFailed,
+	fromState:   docker.StatePaused,
+	project:     (*services.Service).Unpause,
+	container:   (*docker.Client).UnpauseContainer,
+}
+
+func (op pauseOp) run(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, global bool, projectDir string) error {
+	base.Output.Header("%s", op.header)
+
+	if ci.GetFlags(cmd).DryRun {
+		base.Output.Info("%s", messages.DryRunShowingWhatWouldHappen)
+		base.Output.Info(op.dryRun, fmt.Sprintf("%v", args))
+		return nil
+	}
+
+	if global {
+		return op.handleSharedContext(ctx, args, base, buildSharedMode())
+	}
+
+	if projectDir != "" {
+		mode, err := buildProjectMode(projectDir)
+		if err != nil {
+			return err
+		}
+		return op.handleProjectContext(ctx, args, base, mode)
+	}
+
+	execCtx, err := middleware.ExecContextOrDetect(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch mode := execCtx.(type) {
+	case *clicontext.ProjectMode:
+		return op.handleProjectContext(ctx, args, base, mode)
+	case *clicontext.SharedMode:
+		return op.handleSharedContext(ctx, args, base, mode)
+	case *clicontext.WorkspaceMode:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldProjectPath, messages.ValidationWorkspaceUnsupported, cmd.Name())
+	default:
+		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
+	}
+}
+
+func (op pauseOp) handleProjectContext(ctx context.Context, args []string, base *base.BaseCommand, mode *clicontext.ProjectMode) error {
+	setup, cleanup, err := middleware.CoreSetupOrCreate(ctx, base)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	serviceConfigs, err := common.ResolveServiceConfigs(args, setup)
+	if err != nil {
+		return err
+	}
+
+	reg := registry.NewManager(mode.Shared.Root)
+	containers, err := reg.List()
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	svc, err := common.NewServiceManager(false)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentStack, messages.ErrorsStackCreateFailed, err)
+	}
+
+	projectName := setup.Config.Project.Name
+	localConfigs, sharedConfigs := splitSharedConfigs(serviceConfigs, setup.Config)
+
+	affected, err := op.project(svc, ctx, services.PauseRequest{Project: projectName, ServiceConfigs: localConfigs})
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentServices, op.failed, err)
+	}
+
+	sharedNames := services.ExtractServiceNames(sharedConfigs)
+	sharedAffected, err := op.applyShared(ctx, svc.DockerClient, sharedNames, containers, projectName, base)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentServices, op.failed, err)
+	}
+
+	if !op.report(base, append(affected, sharedAffected...)) {
+		return nil
+	}
+	base.Output.Muted(messages.InfoProjectInfo, projectName)
+
+	if statuses, err := svc.Status(ctx, services.StatusRequest{
+		Project:  projectName,
+		Services: filterStatusQueryNames(localConfigs),
+	}); err == nil {
+		// Silent fallback: if Status() fails, the command already succeeded — skip the table
+		_ = display.RenderStatusTable(base.Output.Writer(), statuses, localConfigs, true, base.Output.GetNoColor())
+	}
+
+	return nil
+}
+
+func (op pauseOp) handleSharedContext(ctx context.Context, args []string, base *base.BaseCommand, mode *clicontext.SharedMode) error {
+	reg := registry.NewManager(mode.Shared.Root)
+	regData, err := reg.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	serviceNames := args
+	if len(serviceNames) == 0 {
+		for name := range regData.Containers {
+			serviceNames = append(serviceNames, name)
+		}
+		sort.Strings(serviceNames)
+	} else if err := common.VerifyServicesInRegistry(serviceNames, regData); err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsServiceNotInRegistry, err)
+	}
+
+	if len(serviceNames) == 0 {
+		base.Output.Info("%s", messages.InfoNoSharedContainers)
+		return nil
+	}
+
+	dockerClient, err := docker.NewClient(logger.GetLogger())
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = dockerClient.Close() }()
+
+	affected, err := op.applyShared(ctx, dockerClient, serviceNames, regData.Containers, "", base)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentServices, op.failed, err)
+	}
+
+	op.report(base, affected)
+	return nil
+}
+
+// applyShared runs the operation against the shared containers of the given services.
+// currentProject is excluded from the list of affected projects in the shared warning.
+func (op pauseOp) applyShared(ctx context.Context, dockerClient *docker.Client, serviceNames []string, containers map[string]*registry.ContainerInfo, currentProject string, base *base.BaseCommand) ([]string, error) {
+	var affected []string
+	for _, name := range serviceNames {
+		containerName := core.SharedContainerPrefix + name
+		if info, ok := containers[name]; ok && info != nil {
+			containerName = info.Name
+		}
+
+		if dockerClient.InspectContainer(ctx, containerName).State != op.fromState {
+			continue
+		}
+
+		if op.warnShared {
+			if others := otherProjects(containers[name], currentProject); len(others) > 0 {
+				base.Output.Warning(messages.WarningsSharedPauseAffectsProjects, name, strings.Join(others, ", "))
+			}
+		}
+
+		if err := op.container(dockerClient, ctx, containerName); err != nil {
+			return affected, err
+		}
+		affected = append(affected, containerName)
+	}
+	return affected, nil
+}
+
+// report prints one line per affected container and returns false when nothing was done.
+func (op pauseOp) report(base *base.BaseCommand, affected []string) bool {
+	if len(affected) == 0 {
+		base.Output.Info("%s", op.nothingDone)
+		return false
+	}
+	for _, name := range affected {
+		base.Output.Success(op.serviceDone, name)
+	}
+	base.Output.Success("%s", op.success)
+	return true
+}
+
+// splitSharedConfigs separates services run by the project compose from those
+// provided by shared containers, using the same rule as `up`.
+func splitSharedConfigs(serviceConfigs []types.ServiceConfig, cfg *config.Config) ([]types.ServiceConfig, []types.ServiceConfig) {
+	if cfg.Sharing == nil {
+		return serviceConfigs, nil
+	}
+
+	local := project.FilterProjectServices(serviceConfigs, cfg.Sharing.Enabled, cfg.Sharing.Services)
+	var shared []types.ServiceConfig
+	for _, svc := range serviceConfigs {
+		if !slices.ContainsFunc(local, func(l types.ServiceConfig) bool { return l.Name == svc.Name }) {
+			shared = append(shared, svc)
+		}
+	}
+	return local, shared
+}
+
+// otherProjects returns the names of registered projects using a shared container, excluding current.
+func otherProjects(info *registry.ContainerInfo, current string) []string {
+	if info == nil {
+		return nil
+	}
+	var names []string
+	for _, ref := range info.Projects {
+		if ref.Name != current {
+			names = append(names, ref.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,73 @@
+//go:build unit
+
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func TestPauseHandler_ValidateArgs(t *testing.T) {
+	assert.NoError(t, NewPauseHandler().ValidateArgs([]string{}))
+	assert.NoError(t, NewUnpauseHandler().ValidateArgs([]string{"redis"}))
+}
+
+func TestPauseHandler_GetRequiredFlags(t *testing.T) {
+	assert.Empty(t, NewPauseHandler().GetRequiredFlags())
+	assert.Empty(t, NewUnpauseHandler().GetRequiredFlags())
+}
+
+func TestSplitSharedConfigs(t *testing.T) {
+	configs := []types.ServiceConfig{
+		{Name: "postgres", Shareable: true},
+		{Name: "redis", Shareable: true},
+		{Name: "app"},
+	}
+
+	t.Run("no sharing config keeps everything local", func(t *testing.T) {
+		local, shared := splitSharedConfigs(configs, &config.Config{})
+		assert.Len(t, local, 3)
+		assert.Empty(t, shared)
+	})
+
+	t.Run("sharing enabled moves shareable services out", func(t *testing.T) {
+		cfg := &config.Config{Sharing: &config.SharingConfig{Enabled: true}}
+		local, shared := splitSharedConfigs(configs, cfg)
+		assert.Equal(t, []string{"app"}, serviceNames(local))
+		assert.Equal(t, []string{"postgres", "redis"}, serviceNames(shared))
+	})
+
+	t.Run("per-service map opts services out of sharing", func(t *testing.T) {
+		cfg := &config.Config{Sharing: &config.SharingConfig{Enabled: true, Services: map[string]bool{"postgres": true}}}
+		local, shared := splitSharedConfigs(configs, cfg)
+		assert.Equal(t, []string{"redis", "app"}, serviceNames(local))
+		assert.Equal(t, []string{"postgres"}, serviceNames(shared))
+	})
+}
+
+func TestOtherProjects(t *testing.T) {
+	info := &registry.ContainerInfo{
+		Name: "otto-stack-redis",
+		Projects: []registry.ProjectRef{
+			{Name: "alpha"},
+			{Name: "beta"},
+		},
+	}
+
+	assert.Equal(t, []string{"beta"}, otherProjects(info, "alpha"))
+	assert.Equal(t, []string{"alpha", "beta"}, otherProjects(info, ""))
+	assert.Nil(t, otherProjects(nil, "alpha"))
+}
+
+func serviceNames(configs []types.ServiceConfig) []string {
+	names := make([]string, len(configs))
+	for i, c := range configs {
+		names[i] = c.Name
+	}
+	return names
+}
@@ -13,6 +13,7 @@ import (
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	"github.com/otto-nation/otto-stack/internal/pkg/display"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
@@ -62,6 +63,8 @@ func (h *RestartHandler) Handle(ctx context.Context, cmd *cobra.Command, args []
 		return h.handleProjectContext(ctx, cmd, args, base)
 	case *clicontext.SharedMode:
 		return h.handleSharedContext(ctx, cmd, args, base, mode)
+	case *clicontext.WorkspaceMode:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldProjectPath, messages.ValidationWorkspaceUnsupported, cmd.Name())
 	default:
 		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
 	}
@@ -185,6 +188,7 @@ func (h *RestartHandler) restartServices(ctx context.Context, setup *common.Core
 		Project:        setup.Config.Project.Name,
 		ServiceConfigs: serviceConfigs,
 		NoDeps:         flags.NoDeps,
+		AttachProjects: config.AttachedProjects(setup.Config),
 	}
 	if err := stackService.Start(ctx, startRequest); err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentStack, messages.ErrorsStackStartFailed, err)
@@ -0,0 +1,263 @@
+package lifecycle
+
+import (
+	"context"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/project"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// Actions accepted by shared
+const (
+	SharedActionList     = "list"
+	SharedActionInspect  = "inspect"
+	SharedActionAdopt    = "adopt"
+	SharedActionRelease  = "release"
+	SharedActionMove     = "move"
+	SharedActionRegistry = "registry"
+	SharedActionHistory  = "history"
+	SharedActionGC       = "gc"
+
+	// Subactions of shared registry
+	SharedRegistryExport = "export"
+	SharedRegistryImport = "import"
+
+	sharedFormatTable = "table"
+	sharedFormatJSON  = "json"
+)
+
+// sharedActionUsage is how each action is called, for argument errors
+var sharedActionUsage = map[string]string{
+	SharedActionList:     "list",
+	SharedActionInspect:  "inspect <service>",
+	SharedActionAdopt:    "adopt <container> --project <name>",
+	SharedActionRelease:  "release <service> --project <name>",
+	SharedActionMove:     "move <service> --to-project <name>",
+	SharedActionRegistry: "registry export [file] | registry import <file>",
+	SharedActionHistory:  "history [service] [--project <name>]",
+	SharedActionGC:       "gc [--idle duration] [--remove]",
+}
+
+// SharedHandler handles the shared command
+type SharedHandler struct{}
+
+// NewSharedHandler creates a new shared handler
+func NewSharedHandler() *SharedHandler {
+	return &SharedHandler{}
+}
+
+// Handle executes the shared command
+func (h *SharedHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	if err := h.ValidateArgs(args); err != nil {
+		return err
+	}
+	flags, err := core.ParseSharedFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	if err := h.validateFlags(args[0], flags); err != nil {
+		return err
+	}
+	sharedRoot, err := common.SharedRoot()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryGetFailed, err)
+	}
+
+	ciFlags := ci.GetFlags(cmd)
+	switch args[0] {
+	case SharedActionList:
+		return h.handleList(ctx, flags, sharedRoot, base)
+	case SharedActionInspect:
+		return h.handleInspect(ctx, args[1], flags, sharedRoot, base)
+	case SharedActionAdopt:
+		return h.handleAdopt(ctx, args[1], flags, sharedRoot, base)
+	case SharedActionRelease:
+		return h.handleRelease(args[1], flags, sharedRoot, base)
+	case SharedActionMove:
+		return h.handleMove(ctx, args[1], flags, ciFlags, sharedRoot, base)
+	case SharedActionRegistry:
+		return h.handleRegistry(args[1:], sharedRoot, base)
+	case SharedActionHistory:
+		return h.handleHistory(args[1:], flags, sharedRoot, base)
+	default:
+		return h.handleGC(ctx, flags, ciFlags, sharedRoot, base)
+	}
+}
+
+// validateFlags checks the flags an action needs
+func (h *SharedHandler) validateFlags(action string, flags *core.SharedFlags) error {
+	switch flags.Format {
+	case "", sharedFormatTable, sharedFormatJSON:
+	default:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationSharedFormatInvalid, flags.Format)
+	}
+
+	required := map[string]struct{ name, value string }{
+		SharedActionAdopt:   {core.FlagProject, flags.Project},
+		SharedActionRelease: {core.FlagProject, flags.Project},
+		SharedActionMove:    {core.FlagToProject, flags.ToProject},
+	}
+	if flag, ok := required[action]; ok && flag.value == "" {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationSharedFlagRequired, action, flag.name)
+	}
+	return nil
+}
+
+// handleGC stops, or with --remove removes, the shared containers no live
+// project has used within the idle window
+func (h *SharedHandler) handleGC(ctx context.Context, flags *core.SharedFlags, ciFlags ci.Flags, sharedRoot string, base *base.BaseCommand) error {
+	window, err := time.ParseDuration(flags.Idle)
+	if err != nil || window <= 0 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationSharedIdleInvalid, flags.Idle)
+	}
+
+	reg := registry.NewManager(sharedRoot)
+	now := time.Now()
+	idle, err := reg.FindIdle(now.Add(-window))
+	if err != nil {
+		return err
+	}
+	if len(idle) == 0 {
+		base.Output.Info(messages.SharedGcNothingIdle, flags.Idle)
+		return nil
+	}
+
+	base.Output.Header(messages.SharedGcHeader, flags.Idle)
+	services := slices.Sorted(maps.Keys(idle))
+	for _, service := range services {
+		describeIdle(service, idle[service], now, base)
+	}
+
+	if ciFlags.DryRun {
+		if flags.Remove {
+			base.Output.Info(messages.SharedGcWouldRemove, len(services))
+		} else {
+			base.Output.Info(messages.SharedGcWouldStop, len(services))
+		}
+		return nil
+	}
+
+	services = h.composeServices(services, sharedRoot, base)
+	if len(services) == 0 {
+		return nil
+	}
+	if err := h.stopIdle(ctx, services, sharedRoot, flags.Remove); err != nil {
+		return err
+	}
+
+	if !flags.Remove {
+		base.Output.Success(messages.SharedGcStopped, len(services))
+		return nil
+	}
+	for _, service := range services {
+		if err := reg.Forget(service); err != nil {
+			base.Output.Warning(messages.WarningsRegistryUnregisterFailed, service, err)
+		}
+	}
+	base.Output.Success(messages.SharedGcRemoved, len(services))
+	return nil
+}
+
+// describeIdle prints when a live project last used an idle container
+func describeIdle(service string, container *registry.ContainerInfo, now time.Time, base *base.BaseCommand) {
+	lastActive := container.LastActive()
+	if lastActive.IsZero() {
+		base.Output.Info(messages.SharedGcUnusedItem, service)
+		return
+	}
+	projects := make([]string, len(container.Projects))
+	for i, ref := range container.Projects {
+		projects[i] = ref.Name
+	}
+	base.Output.Info(messages.SharedGcIdleItem, service, units.HumanDuration(now.Sub(lastActive)), strings.Join(projects, ", "))
+}
+
+// composeServices keeps the services the shared compose file defines, which
+// are the ones compose can stop
+func (h *SharedHandler) composeServices(services []string, sharedRoot string, base *base.BaseCommand) []string {
+	defined, err := project.SharedComposeServices(sharedRoot)
+	if err != nil || defined == nil {
+		base.Output.Warning("%s", messages.SharedComposeFileNotFound)
+		return nil
+	}
+
+	var known []string
+	for _, service := range services {
+		if !defined[service] {
+			base.Output.Warning(messages.SharedGcNotInCompose, service)
+			continue
+		}
+		known = append(known, service)
+	}
+	return known
+}
+
+func (h *SharedHandler) stopIdle(ctx context.Context, services []string, sharedRoot string, remove bool) error {
+	composeManager, err := docker.NewManager()
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerManagerCreateFailed, err)
+	}
+	composePath := filepath.Join(sharedRoot, core.GeneratedDir, docker.DockerComposeFileName)
+	proj, err := composeManager.LoadProject(ctx, []string{composePath}, "shared")
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		err = composeManager.Down(ctx, proj, docker.DownOptions{Services: services}.ToSDK())
+	} else {
+		err = composeManager.Stop(ctx, proj.Name, docker.StopOptions{Services: services}.ToSDK())
+	}
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsSharedGcFailed, err)
+	}
+	return nil
+}
+
+// ValidateArgs validates the command arguments
+func (h *SharedHandler) ValidateArgs(args []string) error {
+	if len(args) == 0 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ValidationSharedActionInvalid, "")
+	}
+	usage, ok := sharedActionUsage[args[0]]
+	if !ok {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ValidationSharedActionInvalid, strings.Join(args, " "))
+	}
+
+	var valid bool
+	switch args[0] {
+	case SharedActionList, SharedActionGC:
+		valid = len(args) == 1
+	case SharedActionHistory:
+		valid = len(args) <= 2
+	case SharedActionRegistry:
+		valid = (len(args) == 2 || len(args) == 3) && args[1] == SharedRegistryExport ||
+			len(args) == 3 && args[1] == SharedRegistryImport
+	default:
+		valid = len(args) == 2
+	}
+	if !valid {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ValidationSharedActionUsage, usage)
+	}
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *SharedHandler) GetRequiredFlags() []string {
+	return []string{}
+}
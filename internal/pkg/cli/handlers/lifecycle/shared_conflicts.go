@@ -0,0 +1,173 @@
+package lifecycle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/project"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// sharedConflictChoice is how a project goes on when a shared container runs
+// with a configuration other than the one it asks for
+type sharedConflictChoice int
+
+const (
+	useRunningShared sharedConflictChoice = iota
+	startSharedInstance
+	cancelSharedConflict
+)
+
+// prepareSharedServices switches the shared services the project runs as a
+// second instance to that instance, and checks each against the container
+// registered under its name. A container running with a different
+// configuration is resolved by sharing.on_conflict, or by asking when
+// interactive. It returns the services to start and what to record for each
+// in the registry.
+func (h *UpHandler) prepareSharedServices(sharedConfigs []types.ServiceConfig, cfg *config.Config, sharedRoot string, nonInteractive bool, base *base.BaseCommand) ([]types.ServiceConfig, map[string]*registry.ContainerSpec, error) {
+	if len(sharedConfigs) == 0 {
+		return nil, nil, nil
+	}
+
+	reg := registry.NewManager(sharedRoot)
+	prepared := make([]types.ServiceConfig, 0, len(sharedConfigs))
+	specs := make(map[string]*registry.ContainerSpec, len(sharedConfigs))
+	for _, catalog := range sharedConfigs {
+		svc, ports := catalog, map[string]string(nil)
+		instance := cfg.Sharing.Instances[catalog.Name]
+		if instance != "" {
+			var err error
+			if svc, ports, err = project.SharedInstance(catalog, instance, reg); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		fingerprint, err := project.SharedFingerprint(svc)
+		if err != nil {
+			return nil, nil, err
+		}
+		diffs, err := reg.Compare(svc.Name, fingerprint)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(diffs) > 0 {
+			running, err := reg.Get(svc.Name)
+			if err != nil {
+				return nil, nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+			}
+			h.showSharedConflict(svc.Name, running, diffs, base)
+
+			// A service already running as a second instance is not offered a third
+			suggested := ""
+			if instance == "" {
+				suggested = project.InstanceSuffix(running.Image, fingerprint.Image, cfg.Project.Name)
+			}
+			choice, err := h.resolveSharedConflict(svc.Name, suggested, cfg.Sharing.OnConflict, nonInteractive)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			switch choice {
+			case cancelSharedConflict:
+				return nil, nil, pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsSharedConflictCancelled, svc.Name)
+			case startSharedInstance:
+				if svc, ports, err = project.SharedInstance(catalog, suggested, reg); err != nil {
+					return nil, nil, err
+				}
+				if fingerprint, err = project.SharedFingerprint(svc); err != nil {
+					return nil, nil, err
+				}
+				h.recordSharedInstance(cfg, catalog.Name, suggested, base)
+				base.Output.Info(messages.SharedInstanceSelected, core.SharedContainerPrefix+svc.Name)
+			default:
+				base.Output.Warning(messages.SharedUsingRunning, core.SharedContainerPrefix+svc.Name)
+			}
+		}
+
+		specs[svc.Name] = &registry.ContainerSpec{Service: catalog.Name, Fingerprint: fingerprint, Ports: ports}
+		prepared = append(prepared, svc)
+	}
+	return prepared, specs, nil
+}
+
+// showSharedConflict lists the settings on which the running container and
+// the project disagree
+func (h *UpHandler) showSharedConflict(service string, running *registry.ContainerInfo, diffs []registry.ConfigDifference, base *base.BaseCommand) {
+	var users []string
+	if running != nil {
+		for _, ref := range running.Projects {
+			users = append(users, ref.Name)
+		}
+	}
+	base.Output.Warning(messages.WarningsSharedConfigConflict, service, strings.Join(users, ", "))
+	for _, diff := range diffs {
+		base.Output.Info(messages.SharedConfigDifference, diff.Key, conflictValue(diff.Registered), conflictValue(diff.Requested))
+	}
+}
+
+func conflictValue(value string) string {
+	if value == "" {
+		return messages.SharedConfigValueUnset
+	}
+	return value
+}
+
+// resolveSharedConflict decides how to go on. Without a prompt the policy
+// decides: warn keeps the running container and fail stops. When asked, fail
+// only rules out using the running container as it is. suggested is the
+// suffix of the second instance to offer, if any.
+func (h *UpHandler) resolveSharedConflict(service, suggested, policy string, nonInteractive bool) (sharedConflictChoice, error) {
+	fail := policy == core.SharingOnConflictFail
+	if nonInteractive {
+		if fail {
+			return cancelSharedConflict, pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsSharedConfigConflict, service)
+		}
+		return useRunningShared, nil
+	}
+
+	var options []string
+	choices := make(map[string]sharedConflictChoice)
+	addOption := func(option string, choice sharedConflictChoice) {
+		options = append(options, option)
+		choices[option] = choice
+	}
+	if !fail {
+		addOption(fmt.Sprintf(messages.SharedConflictOptionExisting, core.SharedContainerPrefix+service), useRunningShared)
+	}
+	if suggested != "" {
+		instanceName := core.SharedContainerPrefix + project.SharedInstanceName(service, suggested)
+		addOption(fmt.Sprintf(messages.SharedConflictOptionInstance, instanceName), startSharedInstance)
+	}
+	addOption(messages.SharedConflictOptionCancel, cancelSharedConflict)
+
+	prompt := &survey.Select{
+		Message: fmt.Sprintf(messages.PromptsSharedConfigConflict, service),
+		Options: options,
+	}
+	var selected string
+	if err := survey.AskOne(prompt, &selected); err != nil {
+		return cancelSharedConflict, err
+	}
+	return choices[selected], nil
+}
+
+// recordSharedInstance remembers in the project config that the project uses
+// a second instance, so later runs, down and the env file find it
+func (h *UpHandler) recordSharedInstance(cfg *config.Config, service, instance string, base *base.BaseCommand) {
+	if cfg.Sharing.Instances == nil {
+		cfg.Sharing.Instances = make(map[string]string)
+	}
+	cfg.Sharing.Instances[service] = instance
+	if err := config.SetSharingInstance(service, instance); err != nil {
+		base.Output.Warning(messages.WarningsSharedInstanceRecordFailed, err)
+	}
+}
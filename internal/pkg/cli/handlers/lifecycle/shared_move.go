@@ -0,0 +1,212 @@
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+const (
+	// moveStopTimeout is how long the shared container gets to shut down
+	// cleanly before its data is copied, in seconds
+	moveStopTimeout = 30
+
+	// moveCopyCommand copies a volume mounted at /from into one at /to,
+	// keeping ownership and permissions
+	moveCopyCommand = "cp -a /from/. /to/"
+
+	// moveContainerPrefix names the short-lived container that copies data
+	moveContainerPrefix = core.SharedContainerPrefix + "move-"
+)
+
+// volumeCopy is one volume of a shared container and where move copies it
+type volumeCopy struct {
+	source string
+	mount  string
+	target string
+}
+
+// handleMove turns a shared service into a project-local one for a project:
+// the container's volumes are copied into volumes of the project's own, the
+// project's config runs the service itself on them, and the project is
+// unregistered from the shared container
+func (h *SharedHandler) handleMove(ctx context.Context, service string, flags *core.SharedFlags, ciFlags ci.Flags, sharedRoot string, base *base.BaseCommand) error {
+	reg := registry.NewManager(sharedRoot)
+	containers, err := reg.List()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	container := containers[service]
+	if container == nil {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ErrorsSharedNotRegistered, service)
+	}
+	idx := slices.IndexFunc(container.Projects, func(r registry.ProjectRef) bool { return r.Name == flags.ToProject })
+	if idx < 0 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldFlags, messages.ErrorsSharedProjectNotRegistered, flags.ToProject, service)
+	}
+	ref := container.Projects[idx]
+	if ref.ConfigDir == "" {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldFlags, messages.ErrorsSharedProjectDirUnknown, ref.Name)
+	}
+	catalog := container.CatalogService(service)
+
+	client, err := docker.NewClient(nil)
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = client.Close() }()
+	inspected, err := client.InspectDetails(ctx, container.Name)
+	if err != nil {
+		return err
+	}
+
+	copies := planVolumeCopies(ref.Name, catalog, inspected.Mounts)
+	base.Output.Header(messages.SharedMoveHeader, service, ref.Name)
+	for _, c := range copies {
+		base.Output.Info(messages.SharedMoveVolumeItem, c.source, c.mount, c.target)
+	}
+	if len(copies) == 0 {
+		base.Output.Info(messages.SharedMoveNoVolumes, container.Name)
+	}
+	if ciFlags.DryRun {
+		base.Output.Info(messages.SharedMoveWould, container.Name, ref.ConfigDir)
+		return nil
+	}
+
+	var others []string
+	for _, other := range container.Projects {
+		if other.Name != ref.Name {
+			others = append(others, other.Name)
+		}
+	}
+	if len(others) > 0 && !ciFlags.NonInteractive && !confirmMove(container.Name, others) {
+		base.Output.Info("%s", messages.SharedCancelled)
+		return nil
+	}
+
+	if err := h.copyVolumes(ctx, client, inspected, copies, len(others) > 0, base); err != nil {
+		return err
+	}
+
+	volumes := make(map[string]string, len(copies))
+	for _, c := range copies {
+		volumes[c.mount] = c.target
+	}
+	if err := config.UnshareService(ref.ConfigDir, catalog, stillShared(containers, service, ref.Name), volumes); err != nil {
+		return err
+	}
+	if err := reg.MoveOut(service, ref.Name); err != nil {
+		return err
+	}
+
+	base.Output.Success(messages.SharedMoved, ref.Name, catalog, ref.ConfigDir)
+	base.Output.Info(messages.SharedMoveNext, filepath.Dir(ref.ConfigDir))
+	return nil
+}
+
+// copyVolumes stops the container so its data is at rest, copies each
+// volume with a short-lived container running its image, and starts it again
+// when it was running and other projects still use it, or the copy failed
+func (h *SharedHandler) copyVolumes(ctx context.Context, client *docker.Client, inspected *docker.ContainerDetails, copies []volumeCopy, othersRemain bool, base *base.BaseCommand) (err error) {
+	if len(copies) == 0 {
+		return nil
+	}
+	if inspected.State == docker.StateRunning {
+		base.Output.Info(messages.SharedMoveStopping, inspected.Name)
+		if err := client.StopContainer(ctx, inspected.Name, moveStopTimeout); err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil || othersRemain {
+				if startErr := client.StartContainer(context.WithoutCancel(ctx), inspected.Name); startErr != nil && err == nil {
+					err = startErr
+				}
+			}
+		}()
+	}
+
+	for _, c := range copies {
+		copyErr := client.RunInitContainer(ctx, moveContainerPrefix+c.target, docker.InitContainerConfig{
+			Image:   inspected.Image,
+			Command: []string{"sh", "-c", moveCopyCommand},
+			Volumes: []string{c.source + ":/from" + docker.VolumeReadOnlySuffix, c.target + ":/to"},
+		})
+		if copyErr != nil {
+			return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsSharedMoveFailed, inspected.Name), copyErr)
+		}
+	}
+	return nil
+}
+
+// planVolumeCopies names a volume of the project's own for each Docker volume
+// the shared container mounts: <project>_<service>_<last path element>, such
+// as shop_postgres_data. Host directories stay where they are.
+func planVolumeCopies(projectName, service string, mounts []docker.ContainerMount) []volumeCopy {
+	var copies []volumeCopy
+	taken := make(map[string]bool)
+	for _, m := range mounts {
+		if !m.IsVolume() || m.Name == "" {
+			continue
+		}
+		base := volumeNamePart(projectName) + "_" + volumeNamePart(service) + "_" + volumeNamePart(path.Base(m.Destination))
+		target := base
+		for i := 2; taken[target]; i++ {
+			target = fmt.Sprintf("%s_%d", base, i)
+		}
+		taken[target] = true
+		copies = append(copies, volumeCopy{source: m.Name, mount: m.Destination, target: target})
+	}
+	return copies
+}
+
+// volumeNamePart keeps the characters Docker accepts in volume names
+func volumeNamePart(s string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// stillShared lists the catalog services a project keeps using from shared
+// containers other than service
+func stillShared(containers map[string]*registry.ContainerInfo, service, projectName string) []string {
+	var names []string
+	for key, info := range containers {
+		if key == service || !slices.ContainsFunc(info.Projects, func(r registry.ProjectRef) bool { return r.Name == projectName }) {
+			continue
+		}
+		if name := info.CatalogService(key); !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func confirmMove(containerName string, others []string) bool {
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf(messages.PromptsSharedMoveInterrupts, containerName, strings.Join(others, ", ")),
+		Default: false,
+	}
+	var confirmed bool
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return false
+	}
+	return confirmed
+}
@@ -0,0 +1,271 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/provision"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// stdoutFile names standard output as the file for registry export
+const stdoutFile = "-"
+
+// handleList shows every registered shared container. Docker only adds the
+// state and ports, so the list is shown without them when it is unavailable.
+func (h *SharedHandler) handleList(ctx context.Context, flags *core.SharedFlags, sharedRoot string, base *base.BaseCommand) error {
+	containers, err := registry.NewManager(sharedRoot).List()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	client := optionalDockerClient(len(containers) > 0)
+	if client != nil {
+		defer func() { _ = client.Close() }()
+	}
+	rows := make([]display.SharedRow, 0, len(containers))
+	for _, service := range slices.Sorted(maps.Keys(containers)) {
+		rows = append(rows, sharedRow(ctx, client, service, containers[service]))
+	}
+
+	if flags.Format == sharedFormatJSON {
+		return json.NewEncoder(base.Output.Writer()).Encode(rows)
+	}
+	if len(rows) == 0 {
+		base.Output.Info(messages.InfoNoSharedContainers)
+		return nil
+	}
+	display.RenderSharedTable(base.Output.Writer(), rows, time.Now(), base.Output.GetNoColor())
+	return nil
+}
+
+// handleInspect shows one shared container in detail: its recorded
+// configuration, its volumes and what each project was given on it
+func (h *SharedHandler) handleInspect(ctx context.Context, service string, flags *core.SharedFlags, sharedRoot string, base *base.BaseCommand) error {
+	reg, err := registry.NewManager(sharedRoot).Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	container := reg.Containers[service]
+	if container == nil {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ErrorsSharedNotRegistered, service)
+	}
+
+	client := optionalDockerClient(true)
+	if client != nil {
+		defer func() { _ = client.Close() }()
+	}
+	details := display.SharedDetails{
+		SharedRow:      sharedRow(ctx, client, service, container),
+		CatalogService: container.CatalogService(service),
+		Image:          container.Image,
+		Config:         container.Config,
+		Volumes:        []display.SharedVolume{},
+		Usage:          make([]display.SharedProjectUsage, 0, len(container.Projects)),
+	}
+	if client != nil {
+		if inspected, err := client.InspectDetails(ctx, container.Name); err == nil {
+			if details.Image == "" {
+				details.Image = inspected.Image
+			}
+			for _, m := range inspected.Mounts {
+				source := m.Name
+				if source == "" {
+					source = m.Source
+				}
+				details.Volumes = append(details.Volumes, display.SharedVolume{Source: source, Destination: m.Destination, Type: m.Type})
+			}
+		}
+	}
+	for _, ref := range container.Projects {
+		usage := display.SharedProjectUsage{Project: ref.Name, ConfigDir: ref.ConfigDir, LastUsed: container.Activity[ref.Name]}
+		if creds := reg.Credentials[service][ref.Name]; creds != nil {
+			usage.Database, usage.User = creds.Database, creds.User
+		}
+		if ns := container.Namespaces[ref.Name]; ns != nil {
+			usage.Namespace = provision.DescribeNamespace(ns)
+		}
+		details.Usage = append(details.Usage, usage)
+	}
+
+	if flags.Format == sharedFormatJSON {
+		return json.NewEncoder(base.Output.Writer()).Encode(details)
+	}
+	display.RenderSharedDetails(base.Output.Writer(), details, time.Now())
+	return nil
+}
+
+// handleAdopt registers an existing container. The service comes from its
+// otto-stack label or its otto-stack-<service> name.
+func (h *SharedHandler) handleAdopt(ctx context.Context, containerName string, flags *core.SharedFlags, sharedRoot string, base *base.BaseCommand) error {
+	client, err := docker.NewClient(nil)
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	inspected, err := client.InspectDetails(ctx, containerName)
+	if err != nil {
+		return err
+	}
+	service := inspected.Labels[docker.LabelOttoService]
+	if service == "" {
+		service = strings.TrimPrefix(inspected.Name, core.SharedContainerPrefix)
+	}
+	if service == "" || service == inspected.Name {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ErrorsSharedAdoptNoService, inspected.Name)
+	}
+
+	projects := []registry.ProjectRef{{Name: flags.Project}}
+	if err := registry.NewManager(sharedRoot).Adopt(service, inspected.Name, inspected.Image, projects); err != nil {
+		return err
+	}
+	base.Output.Success(messages.SharedAdopted, inspected.Name, service, flags.Project)
+	return nil
+}
+
+// handleRelease drops a project's reference to a shared container
+func (h *SharedHandler) handleRelease(service string, flags *core.SharedFlags, sharedRoot string, base *base.BaseCommand) error {
+	reg := registry.NewManager(sharedRoot)
+	container, err := reg.Get(service)
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	if container == nil {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ErrorsSharedNotRegistered, service)
+	}
+	if !slices.ContainsFunc(container.Projects, func(r registry.ProjectRef) bool { return r.Name == flags.Project }) {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldFlags, messages.ErrorsSharedProjectNotRegistered, flags.Project, service)
+	}
+
+	if err := reg.Unregister(service, flags.Project); err != nil {
+		return err
+	}
+	// The project's containers no longer reach it by name
+	if client := optionalDockerClient(true); client != nil {
+		defer func() { _ = client.Close() }()
+		if err := provision.NewNetworkAttacher(client).Detach(context.Background(), container.Name, flags.Project); err != nil {
+			base.Output.Warning(messages.WarningsNetworkDetachFailed, err)
+		}
+	}
+	base.Output.Success(messages.SharedReleased, service, flags.Project)
+	if len(container.Projects) == 1 {
+		base.Output.Info(messages.SharedReleaseDropped, container.Name)
+	}
+	return nil
+}
+
+// handleRegistry exports the registry to a file or stdout, or merges an
+// exported one in
+func (h *SharedHandler) handleRegistry(args []string, sharedRoot string, base *base.BaseCommand) error {
+	reg := registry.NewManager(sharedRoot)
+	file := stdoutFile
+	if len(args) > 1 {
+		file = args[1]
+	}
+
+	if args[0] == SharedRegistryImport {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeNotFound, fmt.Sprintf(messages.ErrorsRegistryFileFailed, file), err)
+		}
+		count, err := reg.Import(data)
+		if err != nil {
+			return err
+		}
+		base.Output.Success(messages.SharedRegistryImported, count, file)
+		return nil
+	}
+
+	data, err := reg.Export()
+	if err != nil {
+		return err
+	}
+	if file == stdoutFile {
+		_, err = base.Output.Writer().Write(data)
+		return err
+	}
+	// The export holds database passwords, so only the owner may read it
+	if err := os.WriteFile(file, data, core.PermPrivate); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsRegistryFileFailed, file), err)
+	}
+	base.Output.Success(messages.SharedRegistryExported, file)
+	return nil
+}
+
+// handleHistory lists the recorded registrations, for one service when
+// named and one project with --project
+func (h *SharedHandler) handleHistory(args []string, flags *core.SharedFlags, sharedRoot string, base *base.BaseCommand) error {
+	filter := registry.HistoryFilter{Project: flags.Project}
+	if len(args) > 0 {
+		filter.Service = args[0]
+	}
+	events, err := registry.NewManager(sharedRoot).History(filter)
+	if err != nil {
+		return err
+	}
+
+	if flags.Format == sharedFormatJSON {
+		return json.NewEncoder(base.Output.Writer()).Encode(events)
+	}
+	if len(events) == 0 {
+		base.Output.Info("%s", messages.SharedHistoryEmpty)
+		return nil
+	}
+	rows := make([]display.SharedHistoryRow, len(events))
+	for i, event := range events {
+		rows[i] = display.SharedHistoryRow(event)
+	}
+	display.RenderSharedHistory(base.Output.Writer(), rows)
+	return nil
+}
+
+// sharedRow describes a registered container for list and inspect. A nil
+// client leaves its state unknown.
+func sharedRow(ctx context.Context, client *docker.Client, service string, container *registry.ContainerInfo) display.SharedRow {
+	row := display.SharedRow{
+		Service:    service,
+		Container:  container.Name,
+		State:      display.NotApplicable,
+		Projects:   make([]string, len(container.Projects)),
+		ConfigHash: container.ConfigHash,
+		Ports:      []string{},
+		LastUsed:   container.LastActive(),
+	}
+	for i, ref := range container.Projects {
+		row.Projects[i] = ref.Name
+	}
+	if client != nil {
+		status := client.InspectContainer(ctx, container.Name)
+		row.State = status.State
+		if status.Ports != nil {
+			row.Ports = status.Ports
+		}
+	}
+	return row
+}
+
+// optionalDockerClient connects to Docker when needed, returning nil when it
+// is unavailable
+func optionalDockerClient(needed bool) *docker.Client {
+	if !needed {
+		return nil
+	}
+	client, err := docker.NewClient(nil)
+	if err != nil {
+		return nil
+	}
+	return client
+}
@@ -0,0 +1,187 @@
+//go:build unit
+
+package lifecycle
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+)
+
+func TestSharedHandler_ValidateArgs(t *testing.T) {
+	handler := NewSharedHandler()
+
+	for _, args := range [][]string{
+		{SharedActionGC},
+		{SharedActionList},
+		{SharedActionInspect, "postgres"},
+		{SharedActionAdopt, "otto-stack-postgres"},
+		{SharedActionRelease, "postgres"},
+		{SharedActionMove, "postgres"},
+		{SharedActionRegistry, SharedRegistryExport},
+		{SharedActionRegistry, SharedRegistryExport, "backup.yaml"},
+		{SharedActionRegistry, SharedRegistryImport, "backup.yaml"},
+		{SharedActionHistory},
+		{SharedActionHistory, "postgres"},
+	} {
+		assert.NoError(t, handler.ValidateArgs(args), args)
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"prune"},
+		{SharedActionGC, "extra"},
+		{SharedActionInspect},
+		{SharedActionMove, "postgres", "redis"},
+		{SharedActionRegistry},
+		{SharedActionRegistry, SharedRegistryImport},
+		{SharedActionRegistry, "sync", "backup.yaml"},
+		{SharedActionHistory, "postgres", "redis"},
+	} {
+		assert.Error(t, handler.ValidateArgs(args), args)
+	}
+}
+
+func TestSharedHandler_validateFlags(t *testing.T) {
+	handler := NewSharedHandler()
+
+	assert.NoError(t, handler.validateFlags(SharedActionList, &core.SharedFlags{Format: "json"}))
+	assert.Error(t, handler.validateFlags(SharedActionList, &core.SharedFlags{Format: "yaml"}))
+	assert.Error(t, handler.validateFlags(SharedActionRelease, &core.SharedFlags{}))
+	assert.NoError(t, handler.validateFlags(SharedActionRelease, &core.SharedFlags{Project: "shop"}))
+	assert.Error(t, handler.validateFlags(SharedActionMove, &core.SharedFlags{Project: "shop"}))
+	assert.NoError(t, handler.validateFlags(SharedActionMove, &core.SharedFlags{ToProject: "shop"}))
+}
+
+func TestSharedHandler_handleRelease(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	sharedRoot := t.TempDir()
+
+	reg := registry.NewManager(sharedRoot)
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "shop", ConfigDir: t.TempDir()}))
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "billing", ConfigDir: t.TempDir()}))
+
+	assert.Error(t, handler.handleRelease("redis", &core.SharedFlags{Project: "shop"}, sharedRoot, base))
+	assert.Error(t, handler.handleRelease("postgres", &core.SharedFlags{Project: "unknown"}, sharedRoot, base))
+	require.NoError(t, handler.handleRelease("postgres", &core.SharedFlags{Project: "shop"}, sharedRoot, base))
+
+	info, err := reg.Get("postgres")
+	require.NoError(t, err)
+	require.Len(t, info.Projects, 1)
+	assert.Equal(t, "billing", info.Projects[0].Name)
+}
+
+func TestSharedHandler_handleRegistry_ExportImport(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	source, target := t.TempDir(), t.TempDir()
+	file := filepath.Join(t.TempDir(), "registry.yaml")
+
+	require.NoError(t, registry.NewManager(source).Register("redis", "otto-stack-redis", registry.ProjectRef{Name: "shop"}))
+	require.NoError(t, handler.handleRegistry([]string{SharedRegistryExport, file}, source, base))
+
+	stat, err := os.Stat(file)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(core.PermPrivate), stat.Mode().Perm())
+
+	require.NoError(t, handler.handleRegistry([]string{SharedRegistryImport, file}, target, base))
+	info, err := registry.NewManager(target).Get("redis")
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "otto-stack-redis", info.Name)
+
+	assert.Error(t, handler.handleRegistry([]string{SharedRegistryImport, filepath.Join(t.TempDir(), "missing.yaml")}, target, base))
+}
+
+func TestSharedHandler_handleHistory(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	sharedRoot := t.TempDir()
+
+	require.NoError(t, handler.handleHistory(nil, &core.SharedFlags{}, sharedRoot, base))
+
+	require.NoError(t, registry.NewManager(sharedRoot).Register("redis", "otto-stack-redis", registry.ProjectRef{Name: "shop"}))
+	require.NoError(t, handler.handleHistory([]string{"redis"}, &core.SharedFlags{Project: "shop"}, sharedRoot, base))
+	require.NoError(t, handler.handleHistory(nil, &core.SharedFlags{Format: sharedFormatJSON}, sharedRoot, base))
+}
+
+func TestPlanVolumeCopies(t *testing.T) {
+	copies := planVolumeCopies("shop", "postgres", []docker.ContainerMount{
+		{Type: docker.MountTypeVolume, Name: "shared_pgdata", Destination: "/var/lib/postgresql/data"},
+		{Type: "bind", Source: "/home/me/init", Destination: "/docker-entrypoint-initdb.d"},
+		{Type: docker.MountTypeVolume, Name: "anon123", Destination: "/backup/data"},
+	})
+
+	assert.Equal(t, []volumeCopy{
+		{source: "shared_pgdata", mount: "/var/lib/postgresql/data", target: "shop_postgres_data"},
+		{source: "anon123", mount: "/backup/data", target: "shop_postgres_data_2"},
+	}, copies)
+}
+
+func TestStillShared(t *testing.T) {
+	shop := registry.ProjectRef{Name: "shop"}
+	containers := map[string]*registry.ContainerInfo{
+		"postgres":   {Projects: []registry.ProjectRef{shop}},
+		"redis":      {Projects: []registry.ProjectRef{shop}},
+		"mysql-8":    {Service: "mysql", Projects: []registry.ProjectRef{shop}},
+		"localstack": {Projects: []registry.ProjectRef{{Name: "billing"}}},
+	}
+
+	assert.Equal(t, []string{"mysql", "redis"}, stillShared(containers, "postgres", "shop"))
+}
+
+func TestSharedHandler_GetRequiredFlags(t *testing.T) {
+	assert.Empty(t, NewSharedHandler().GetRequiredFlags())
+}
+
+func TestSharedHandler_handleGC_InvalidIdle(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+
+	for _, idle := range []string{"3d", "0s", ""} {
+		err := handler.handleGC(context.Background(), &core.SharedFlags{Idle: idle}, ci.Flags{}, t.TempDir(), base)
+		assert.Error(t, err, idle)
+	}
+}
+
+func TestSharedHandler_handleGC_DryRunLeavesRegistry(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	sharedRoot := t.TempDir()
+
+	reg := registry.NewManager(sharedRoot)
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "shop", ConfigDir: t.TempDir()}))
+	require.NoError(t, reg.Touch("shop", time.Now().Add(-100*time.Hour)))
+
+	err := handler.handleGC(context.Background(), &core.SharedFlags{Idle: "72h", Remove: true}, ci.Flags{DryRun: true}, sharedRoot, base)
+	require.NoError(t, err)
+
+	info, err := reg.Get("postgres")
+	require.NoError(t, err)
+	assert.NotNil(t, info)
+}
+
+func TestSharedHandler_handleGC_NothingIdle(t *testing.T) {
+	handler := NewSharedHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	sharedRoot := t.TempDir()
+
+	reg := registry.NewManager(sharedRoot)
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "shop", ConfigDir: t.TempDir()}))
+
+	err := handler.handleGC(context.Background(), &core.SharedFlags{Idle: "72h"}, ci.Flags{}, sharedRoot, base)
+	assert.NoError(t, err)
+}
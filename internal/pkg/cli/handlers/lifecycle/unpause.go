@@ -0,0 +1,39 @@
+package lifecycle
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// UnpauseHandler handles the unpause command
+type UnpauseHandler struct{}
+
+// NewUnpauseHandler creates a new unpause handler
+func NewUnpauseHandler() *UnpauseHandler {
+	return &UnpauseHandler{}
+}
+
+// Handle executes the unpause command
+func (h *UnpauseHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParseUnpauseFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	return unpauseOperation.run(ctx, cmd, args, base, flags.Global, flags.Project)
+}
+
+// ValidateArgs validates the command arguments
+func (h *UnpauseHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *UnpauseHandler) GetRequiredFlags() []string {
+	return []string{}
+}
@@ -3,8 +3,10 @@ package lifecycle
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -23,7 +25,9 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	"github.com/otto-nation/otto-stack/internal/pkg/display"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/provision"
 	"github.com/otto-nation/otto-stack/internal/pkg/registry"
 	"github.com/otto-nation/otto-stack/internal/pkg/services"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
@@ -31,7 +35,11 @@ import (
 )
 
 // UpHandler handles the up command
-type UpHandler struct{}
+type UpHandler struct {
+	// sharedStarted records the shared services already started during a
+	// workspace up, so members that share them do not start them again
+	sharedStarted map[string]bool
+}
 
 // NewUpHandler creates a new up handler
 func NewUpHandler() *UpHandler {
@@ -62,11 +70,30 @@ func (h *UpHandler) Handle(ctx context.Context, cmd *cobra.Command, args []strin
 		return h.handleProjectContext(ctx, cmd, args, base, mode)
 	case *clicontext.SharedMode:
 		return h.handleGlobalContext(ctx, cmd, args, base, mode)
+	case *clicontext.WorkspaceMode:
+		return h.handleWorkspaceContext(ctx, cmd, args, base, mode)
 	default:
 		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
 	}
 }
 
+// handleWorkspaceContext starts every project in the workspace in turn. Shared
+// services are started once and registered against each member using them.
+func (h *UpHandler) handleWorkspaceContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, mode *clicontext.WorkspaceMode) error {
+	if err := common.ValidateWorkspace(mode, args); err != nil {
+		return err
+	}
+	base.Output.Header(messages.WorkspaceHeader, mode.Workspace.Root, len(mode.Workspace.Projects))
+
+	h.sharedStarted = make(map[string]bool)
+	defer func() { h.sharedStarted = nil }()
+
+	return common.ForEachWorkspaceProject(mode, func(project *clicontext.ProjectMode) error {
+		base.Output.Header(messages.WorkspaceProjectHeader, filepath.Base(project.Project.Root), mode.Workspace.RelPath(project.Project))
+		return h.handleProjectContext(ctx, cmd, args, base, project)
+	})
+}
+
 func (h *UpHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, execCtx *clicontext.ProjectMode) error {
 	base.Output.Header("%s", messages.LifecycleStarting)
 
@@ -75,6 +102,11 @@ func (h *UpHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command
 		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailed, err)
 	}
 
+	// Catch typos such as "shareing:" that decoding would silently ignore
+	if issues, err := config.ValidateFiles(); err == nil && len(issues) > 0 {
+		return config.IssuesError(issues)
+	}
+
 	setup, cleanup, err := middleware.CoreSetupOrCreate(ctx, base)
 	if err != nil {
 		return err
@@ -86,14 +118,35 @@ func (h *UpHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command
 		return err
 	}
 
+	// Refuse to start with service config files their schemas reject
+	issues, err := services.ValidateServiceConfigFiles(serviceConfigs)
+	if err != nil {
+		return err
+	}
+	if len(issues) > 0 {
+		return config.IssuesError(issues)
+	}
+
+	serviceConfigs, err = h.applyPortsAndTLS(serviceConfigs, setup.Config, base)
+	if err != nil {
+		return err
+	}
+
 	// Separate shared services from project-local services. Shared services run
 	// under their own compose project (otto-stack-<name>); including them in the
 	// project compose would cause container-name conflicts and ownership fights.
 	sharedConfigs := h.filterSharedServices(serviceConfigs, setup.Config)
+	sharedConfigs, sharedSpecs, err := h.prepareSharedServices(sharedConfigs, setup.Config, execCtx.Shared.Root, ci.GetFlags(cmd).NonInteractive, base)
+	if err != nil {
+		return err
+	}
 	if err := h.ensureSharedContainersRunning(ctx, sharedConfigs, execCtx.Shared.Root, base); err != nil {
 		return err
 	}
 	projectServiceConfigs := h.filterProjectServiceConfigs(serviceConfigs, setup.Config)
+	if err := h.checkAttachedNetworks(ctx, setup); err != nil {
+		return err
+	}
 
 	service, err := common.NewServiceManager(false)
 	if err != nil {
@@ -127,21 +180,36 @@ func (h *UpHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command
 		PullLatestImages:  pullLatest,
 		CleanupOnRecreate: cleanupOnRecreate,
 		Timeout:           timeout,
+		AttachProjects:    config.AttachedProjects(setup.Config),
 	}
 
-	if err = service.Start(ctx, startRequest); err != nil {
+	recorder := h.prepareLogRecorder(setup.Config, base)
+	if recorder != nil {
+		startRequest.InitLog = recorder.InitWriter
+	}
+	err = service.Start(ctx, startRequest)
+	if recorder != nil {
+		_ = recorder.Close()
+	}
+	if err != nil {
 		return err
 	}
+	h.startLogRecorder(setup.Config, base)
+	common.RecordProject(setup.Config.Project.Name, setup.Config.Stack.Enabled, base.Output)
 
 	// Register shared containers only after a successful start to keep the registry consistent.
 	if len(sharedConfigs) > 0 {
 		configDir, _ := filepath.Abs(core.OttoStackDir)
 		project := registry.ProjectRef{Name: setup.Config.Project.Name, ConfigDir: configDir}
-		if err := h.registerSharedContainersForProject(sharedConfigs, project, execCtx.Shared.Root, base); err != nil {
+		provisioner := provision.NewDatabaseProvisioner(setup.DockerClient)
+		networker := provision.NewNetworkAttacher(setup.DockerClient)
+		if err := h.registerSharedContainersForProject(sharedConfigs, sharedSpecs, project, execCtx.Shared.Root, provisioner, networker, base); err != nil {
 			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsServiceRegisterSharedFailed, err)
 		}
 		base.Output.Info(messages.SharedProjectRegisteredShared, len(sharedConfigs))
+		h.writeProvisionedEnv(setup.Config, execCtx.Shared.Root, base)
 	}
+	h.suggestSharedGC(setup.Config, execCtx.Shared.Root, base)
 
 	base.Output.Success(messages.SuccessServicesStarted)
 	base.Output.Muted(messages.InfoProjectInfo, setup.Config.Project.Name)
@@ -158,6 +226,38 @@ func (h *UpHandler) handleProjectContext(ctx context.Context, cmd *cobra.Command
 	return nil
 }
 
+// prepareLogRecorder returns a recorder for init script output when logs.record is
+// set. When no recorder is running this is a new run, so the last run's logs are
+// kept aside for logs --previous first.
+func (h *UpHandler) prepareLogRecorder(cfg *config.Config, base *base.BaseCommand) *logrecorder.Recorder {
+	if !logrecorder.Enabled(cfg) {
+		return nil
+	}
+	dir := logrecorder.Dir()
+	if !logrecorder.Running(dir) {
+		if err := logrecorder.ArchiveRun(dir); err != nil {
+			base.Output.Warning(messages.WarningsLogArchiveFailed, err)
+		}
+	}
+	return logrecorder.New(dir, cfg.Project.Name, logrecorder.OptionsFromConfig(cfg.Logs))
+}
+
+// startLogRecorder starts the background recorder unless one is already running
+func (h *UpHandler) startLogRecorder(cfg *config.Config, base *base.BaseCommand) {
+	if !logrecorder.Enabled(cfg) {
+		return
+	}
+	dir := logrecorder.Dir()
+	if logrecorder.Running(dir) {
+		return
+	}
+	if err := logrecorder.Spawn(core.CommandLogs, "--"+core.FlagRecord); err != nil {
+		base.Output.Warning(messages.WarningsLogRecorderFailed, err)
+		return
+	}
+	base.Output.Muted(messages.LifecycleLogRecording, dir)
+}
+
 func (h *UpHandler) handleGlobalContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, execCtx *clicontext.SharedMode) error {
 	base.Output.Header(messages.SharedStarting)
 
@@ -360,11 +460,23 @@ func (h *UpHandler) loadServiceConfigs(serviceNames []string) ([]types.ServiceCo
 
 func (h *UpHandler) registerSharedContainers(serviceConfigs []types.ServiceConfig, execCtx *clicontext.SharedMode, base *base.BaseCommand) error {
 	project := registry.ProjectRef{Name: "global", ConfigDir: execCtx.Shared.Root}
-	return h.registerSharedContainersForProject(serviceConfigs, project, execCtx.Shared.Root, base)
+	return h.registerSharedContainersForProject(serviceConfigs, nil, project, execCtx.Shared.Root, nil, nil, base)
 }
 
-func (h *UpHandler) registerSharedContainersForProject(serviceConfigs []types.ServiceConfig, project registry.ProjectRef, sharedRoot string, base *base.BaseCommand) error {
+// registerSharedContainersForProject records the project as a user of each
+// shared container, along with the configuration it asked for. Services
+// without a spec are fingerprinted here. With a provisioner, registering also
+// gives the project its own database on shared postgres and mysql. With a
+// networker, it connects each container to the project's network.
+func (h *UpHandler) registerSharedContainersForProject(serviceConfigs []types.ServiceConfig, specs map[string]*registry.ContainerSpec, ref registry.ProjectRef, sharedRoot string, provisioner registry.Provisioner, networker registry.Networker, base *base.BaseCommand) error {
 	reg := registry.NewManager(sharedRoot)
+	if provisioner != nil {
+		reg.SetProvisioner(provisioner)
+		reg.SetNamespacer(provision.Namespacer{})
+	}
+	if networker != nil {
+		reg.SetNetworker(networker)
+	}
 
 	// Auto-heal: purge any non-shareable entries from previous bugs
 	if shareableMap, err := h.buildShareableMap(); err == nil {
@@ -377,8 +489,14 @@ func (h *UpHandler) registerSharedContainersForProject(serviceConfigs []types.Se
 		if !svc.Shareable {
 			continue // defense in depth: validateShareableServices should catch this first
 		}
+		spec := specs[svc.Name]
+		if spec == nil {
+			if fingerprint, err := project.SharedFingerprint(svc); err == nil {
+				spec = &registry.ContainerSpec{Service: svc.Name, Fingerprint: fingerprint}
+			}
+		}
 		containerName := core.SharedContainerPrefix + svc.Name
-		if err := reg.Register(svc.Name, containerName, project); err != nil {
+		if err := reg.RegisterWithSpec(svc.Name, containerName, ref, spec); err != nil {
 			base.Output.Warning(messages.WarningsRegistryRegisterFailed, svc.Name, err)
 		}
 	}
@@ -386,6 +504,129 @@ func (h *UpHandler) registerSharedContainersForProject(serviceConfigs []types.Se
 	return nil
 }
 
+// checkAttachedNetworks makes sure the networks of the projects in
+// networks.attach exist, as compose will not create them
+func (h *UpHandler) checkAttachedNetworks(ctx context.Context, setup *common.CoreSetup) error {
+	for _, other := range config.AttachedProjects(setup.Config) {
+		name := docker.ProjectNetworkName(other)
+		exists, err := setup.DockerClient.NetworkExists(ctx, name)
+		if err != nil {
+			return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerListNetworksFailed, err)
+		}
+		if !exists {
+			return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldNetworksAttach, messages.ValidationNetworksAttachMissing, name, other)
+		}
+	}
+	return nil
+}
+
+// writeProvisionedEnv rewrites the project's env file so DATABASE_URL and
+// friends point at the database and namespaces the project was given on
+// shared containers
+func (h *UpHandler) writeProvisionedEnv(cfg *config.Config, sharedRoot string, base *base.BaseCommand) {
+	reg := registry.NewManager(sharedRoot)
+	creds, err := reg.ProjectCredentials(cfg.Project.Name)
+	if err != nil {
+		return
+	}
+	namespaces, err := reg.ProjectNamespaces(cfg.Project.Name)
+	if err != nil || len(creds)+len(namespaces) == 0 {
+		return
+	}
+	for _, service := range slices.Sorted(maps.Keys(creds)) {
+		base.Output.Muted(messages.SharedProvisioned, creds[service].Database, creds[service].User, service)
+	}
+	for _, service := range slices.Sorted(maps.Keys(namespaces)) {
+		base.Output.Muted(messages.SharedNamespaced, service, provision.DescribeNamespace(namespaces[service]))
+	}
+
+	serviceConfigs, err := services.ResolveUpServices(cfg.Stack.Enabled, cfg)
+	if err == nil {
+		err = project.NewProjectManager().RegenerateEnvFile(cfg, serviceConfigs)
+	}
+	if err != nil {
+		base.Output.Warning(messages.WarningsProvisionedEnvFailed, err)
+		return
+	}
+	base.Output.Muted(messages.SharedProvisionedEnvWritten, core.EnvGeneratedFilePath)
+}
+
+// applyPortsAndTLS gives the project-local services the host ports
+// ports.offset and ports.auto call for, picking and recording free ones
+// first, and the TLS certificates their service config files ask for,
+// issuing them first, and rewrites .env.generated to match
+func (h *UpHandler) applyPortsAndTLS(serviceConfigs []types.ServiceConfig, cfg *config.Config, base *base.BaseCommand) ([]types.ServiceConfig, error) {
+	allocated, err := h.allocatePorts(serviceConfigs, cfg, base)
+	if err != nil {
+		return nil, err
+	}
+	issued, err := h.issueTLS(serviceConfigs, cfg, base)
+	if err != nil {
+		return nil, err
+	}
+	if !allocated && !issued {
+		return serviceConfigs, nil
+	}
+	if err := project.NewProjectManager().RegenerateEnvFile(cfg, serviceConfigs); err != nil {
+		base.Output.Warning(messages.WarningsPortsEnvFailed, err)
+	}
+	return project.ApplyPorts(project.ApplyTLS(serviceConfigs, cfg), cfg), nil
+}
+
+// allocatePorts picks and records host ports when the project configures
+// them, reporting whether it does
+func (h *UpHandler) allocatePorts(serviceConfigs []types.ServiceConfig, cfg *config.Config, base *base.BaseCommand) (bool, error) {
+	if cfg.Ports == nil {
+		return false, nil
+	}
+	assignments, err := project.AllocatePorts(serviceConfigs, cfg)
+	if err != nil {
+		return false, err
+	}
+	for _, a := range assignments {
+		if a.Moved() {
+			base.Output.Info(messages.InfoPortsAssigned, a.Service, a.Wanted, a.Key, a.Port)
+		}
+	}
+	return true, nil
+}
+
+// issueTLS issues the certificates the project-local services serve TLS
+// with, reporting whether any serve it
+func (h *UpHandler) issueTLS(serviceConfigs []types.ServiceConfig, cfg *config.Config, base *base.BaseCommand) (bool, error) {
+	issued, shared, err := project.IssueTLS(serviceConfigs, cfg)
+	if err != nil {
+		return false, err
+	}
+	for _, name := range shared {
+		base.Output.Warning(messages.WarningsTlsShared, name, name)
+	}
+	for _, cert := range issued {
+		if cert.Issued {
+			base.Output.Info(messages.InfoTlsIssued, cert.Service, cert.Dir)
+		}
+	}
+	return len(issued) > 0, nil
+}
+
+// suggestSharedGC points out shared containers no project has used within
+// sharing.idle_check, when it is set
+func (h *UpHandler) suggestSharedGC(cfg *config.Config, sharedRoot string, base *base.BaseCommand) {
+	if cfg.Sharing == nil || cfg.Sharing.IdleCheck == "" {
+		return
+	}
+	window, err := time.ParseDuration(cfg.Sharing.IdleCheck)
+	if err != nil || window <= 0 {
+		return
+	}
+	idle, err := registry.NewManager(sharedRoot).FindIdle(time.Now().Add(-window))
+	if err != nil || len(idle) == 0 {
+		return
+	}
+	names := slices.Sorted(maps.Keys(idle))
+	base.Output.Muted(messages.SharedIdleSuggest, cfg.Sharing.IdleCheck, strings.Join(names, ", "), cfg.Sharing.IdleCheck)
+}
+
 func (h *UpHandler) validateShareableServices(serviceConfigs []types.ServiceConfig) error {
 	for _, svc := range serviceConfigs {
 		if !svc.Shareable {
@@ -425,8 +666,10 @@ func (h *UpHandler) filterProjectServiceConfigs(serviceConfigs []types.ServiceCo
 
 // ensureSharedContainersRunning starts any shared containers that are not yet running.
 // The compose file is generated only when it does not already exist; subsequent calls
-// reuse the existing file to avoid noisy output on every `otto-stack up`.
+// reuse the existing file to avoid noisy output on every `otto-stack up`, adding only
+// services it lacks, such as a second instance.
 func (h *UpHandler) ensureSharedContainersRunning(ctx context.Context, sharedConfigs []types.ServiceConfig, sharedRoot string, base *base.BaseCommand) error {
+	sharedConfigs = h.skipStartedShared(sharedConfigs, base)
 	if len(sharedConfigs) == 0 {
 		return nil
 	}
@@ -442,9 +685,59 @@ func (h *UpHandler) ensureSharedContainersRunning(ctx context.Context, sharedCon
 		if err := project.GenerateSharedFiles(sharedConfigs, sharedRoot, base); err != nil {
 			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.WarningsComposeGenerateSharedFailed, err)
 		}
+	} else if missing := missingSharedServices(sharedConfigs, sharedRoot); len(missing) > 0 {
+		if err := project.AddSharedServices(missing, sharedRoot); err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.WarningsComposeGenerateSharedFailed, err)
+		}
+	}
+
+	if err := h.startSharedContainers(ctx, composePath); err != nil {
+		return err
+	}
+	if h.sharedStarted != nil {
+		for _, svc := range sharedConfigs {
+			h.sharedStarted[svc.Name] = true
+		}
 	}
+	return nil
+}
 
-	return h.startSharedContainers(ctx, composePath)
+// missingSharedServices returns the services the shared compose file does
+// not define
+func missingSharedServices(sharedConfigs []types.ServiceConfig, sharedRoot string) []types.ServiceConfig {
+	defined, err := project.SharedComposeServices(sharedRoot)
+	if err != nil {
+		return nil
+	}
+	var missing []types.ServiceConfig
+	for _, svc := range sharedConfigs {
+		if !defined[svc.Name] {
+			missing = append(missing, svc)
+		}
+	}
+	return missing
+}
+
+// skipStartedShared drops the shared services an earlier workspace member
+// already started
+func (h *UpHandler) skipStartedShared(sharedConfigs []types.ServiceConfig, base *base.BaseCommand) []types.ServiceConfig {
+	if len(h.sharedStarted) == 0 {
+		return sharedConfigs
+	}
+
+	var pending []types.ServiceConfig
+	var started []string
+	for _, svc := range sharedConfigs {
+		if h.sharedStarted[svc.Name] {
+			started = append(started, svc.Name)
+			continue
+		}
+		pending = append(pending, svc)
+	}
+	if len(started) > 0 {
+		base.Output.Muted(messages.WorkspaceSharedAlreadyStarted, strings.Join(started, ", "))
+	}
+	return pending
 }
 
 func (h *UpHandler) filterSharedServices(serviceConfigs []types.ServiceConfig, cfg *config.Config) []types.ServiceConfig {
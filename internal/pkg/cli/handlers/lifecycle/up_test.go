@@ -12,6 +12,7 @@ import (
 
 	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 	"github.com/otto-nation/otto-stack/internal/pkg/ui"
 	"github.com/otto-nation/otto-stack/test/testhelpers"
@@ -222,3 +223,32 @@ func TestFilterStatusQueryNames(t *testing.T) {
 // TODO: Add tests for middleware chain execution
 // TODO: Add E2E tests for full lifecycle up workflow
 // TODO: Consider extracting common test utilities to reduce duplication across handler tests
+
+func TestUpHandler_resolveSharedConflict_NonInteractive(t *testing.T) {
+	handler := NewUpHandler()
+
+	choice, err := handler.resolveSharedConflict("postgres", "16", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, useRunningShared, choice)
+
+	choice, err = handler.resolveSharedConflict("postgres", "16", core.SharingOnConflictWarn, true)
+	require.NoError(t, err)
+	assert.Equal(t, useRunningShared, choice)
+
+	_, err = handler.resolveSharedConflict("postgres", "16", core.SharingOnConflictFail, true)
+	assert.Error(t, err)
+}
+
+func TestUpHandler_prepareSharedServices_Unregistered(t *testing.T) {
+	handler := NewUpHandler()
+	base := &base.BaseCommand{Output: ui.NewOutput()}
+	cfg := &config.Config{Sharing: &config.SharingConfig{Enabled: true}}
+	shared := []types.ServiceConfig{{Name: "postgres", Shareable: true}}
+
+	prepared, specs, err := handler.prepareSharedServices(shared, cfg, t.TempDir(), true, base)
+	require.NoError(t, err)
+	assert.Equal(t, shared, prepared)
+	require.Contains(t, specs, "postgres")
+	assert.Equal(t, "postgres", specs["postgres"].Service)
+	assert.Empty(t, specs["postgres"].Ports)
+}
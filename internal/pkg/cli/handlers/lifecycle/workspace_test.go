@@ -0,0 +1,55 @@
+//go:build unit
+
+package lifecycle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+)
+
+func TestUpHandler_skipStartedShared(t *testing.T) {
+	b := &base.BaseCommand{Output: ui.NewOutput()}
+	configs := []types.ServiceConfig{{Name: "postgres"}, {Name: "redis"}}
+
+	h := &UpHandler{}
+	assert.Equal(t, configs, h.skipStartedShared(configs, b), "outside a workspace nothing is skipped")
+
+	h.sharedStarted = map[string]bool{"postgres": true}
+	assert.Equal(t, []types.ServiceConfig{{Name: "redis"}}, h.skipStartedShared(configs, b))
+}
+
+func TestDownHandler_WorkspaceShared(t *testing.T) {
+	sharedRoot := t.TempDir()
+	reg := registry.NewManager(sharedRoot)
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "api"}))
+	require.NoError(t, reg.Register("postgres", "otto-stack-postgres", registry.ProjectRef{Name: "web"}))
+	require.NoError(t, reg.Register("redis", "otto-stack-redis", registry.ProjectRef{Name: "api"}))
+	require.NoError(t, reg.Register("redis", "otto-stack-redis", registry.ProjectRef{Name: "elsewhere"}))
+
+	h := &DownHandler{workspaceShared: make(map[string][]string)}
+	configs := []types.ServiceConfig{{Name: "app"}, {Name: "postgres"}, {Name: "redis"}}
+
+	remaining, err := h.deferWorkspaceShared(configs, "api", sharedRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []types.ServiceConfig{{Name: "app"}}, remaining, "shared services wait for the whole workspace")
+	_, err = h.deferWorkspaceShared(configs[:2], "web", sharedRoot)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"postgres": {"api", "web"}, "redis": {"api"}}, h.workspaceShared)
+	assert.Equal(t, []string{"postgres"}, h.unusedWorkspaceShared(reg), "redis is still used outside the workspace")
+
+	// Non-interactive runs leave shared containers and their registrations alone
+	b := &base.BaseCommand{Output: ui.NewOutput()}
+	require.NoError(t, h.stopWorkspaceShared(context.Background(), b, &clicontext.SharedInfo{Root: sharedRoot}, true))
+	containers, err := reg.List()
+	require.NoError(t, err)
+	assert.Len(t, containers, 2)
+}
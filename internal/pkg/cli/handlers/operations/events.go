@@ -0,0 +1,174 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Output formats accepted by events
+const (
+	eventsFormatText = "text"
+	eventsFormatJSON = "json"
+)
+
+// actionRestartLoop marks restart loop records in JSON output
+const actionRestartLoop = "restart_loop"
+
+// EventsHandler handles the events command
+type EventsHandler struct {
+	logger *slog.Logger
+}
+
+// NewEventsHandler creates a new events handler
+func NewEventsHandler() *EventsHandler {
+	return &EventsHandler{
+		logger: logger.GetLogger(),
+	}
+}
+
+// eventRecord is one line of newline-delimited JSON output
+type eventRecord struct {
+	docker.ContainerEvent
+	ExitCode string `json:"exit_code,omitempty"`
+	Health   string `json:"health,omitempty"`
+	Deaths   int    `json:"deaths,omitempty"`
+}
+
+// Handle executes the events command
+func (h *EventsHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParseEventsFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	if ci.GetFlags(cmd).JSON {
+		flags.Format = eventsFormatJSON
+	}
+	if err := validateEventsFlags(flags); err != nil {
+		return err
+	}
+
+	dockerClient, err := docker.NewClient(h.logger)
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = dockerClient.Close() }()
+
+	if flags.Format == eventsFormatText {
+		base.Output.Header("%s", messages.LifecycleEvents)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events, errs := dockerClient.StreamContainerEvents(ctx, docker.NewManagedFilter(flags.Project))
+	return h.consume(ctx, base, events, errs, args, flags)
+}
+
+// ValidateArgs validates the command arguments
+func (h *EventsHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *EventsHandler) GetRequiredFlags() []string {
+	return []string{}
+}
+
+// validateEventsFlags rejects unsupported formats and detection settings that could never trigger
+func validateEventsFlags(flags *core.EventsFlags) error {
+	switch flags.Format {
+	case "", eventsFormatText, eventsFormatJSON:
+	default:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationEventsFormatInvalid, flags.Format)
+	}
+	if flags.RestartWindow < 1 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationRestartWindowInvalid, flags.RestartWindow)
+	}
+	if flags.RestartThreshold < 2 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationRestartThresholdInvalid, flags.RestartThreshold)
+	}
+	return nil
+}
+
+// consume prints events until the stream ends or ctx is cancelled. Exec events from
+// health checks are dropped; services limits output to those compose services.
+func (h *EventsHandler) consume(ctx context.Context, base *base.BaseCommand, events <-chan docker.ContainerEvent, errs <-chan error, services []string, flags *core.EventsFlags) error {
+	window := time.Duration(flags.RestartWindow) * time.Second
+	detector := docker.NewRestartLoopDetector(window, flags.RestartThreshold)
+	encoder := json.NewEncoder(base.Output.Writer())
+	noColor := base.Output.GetNoColor()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerEventsFailed, err)
+		case event, ok := <-events:
+			if !ok {
+				// The stream closes before its error is read; surface it if there is one
+				select {
+				case err := <-errs:
+					return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerEventsFailed, err)
+				default:
+					return nil
+				}
+			}
+			if !event.IsStateChange() || (len(services) > 0 && !slices.Contains(services, event.Service)) {
+				continue
+			}
+
+			deaths := detector.Observe(event)
+			if flags.Format == eventsFormatJSON {
+				if err := h.writeJSON(encoder, event, deaths); err != nil {
+					return err
+				}
+				continue
+			}
+
+			_, _ = fmt.Fprintln(base.Output.Writer(), display.FormatContainerEvent(event, noColor))
+			if deaths > 0 {
+				base.Output.Warning(messages.WarningsRestartLoopDetected, event.Container, deaths, window)
+			}
+		}
+	}
+}
+
+// writeJSON emits the event and, when it completed a restart loop, a restart_loop record
+func (h *EventsHandler) writeJSON(encoder *json.Encoder, event docker.ContainerEvent, deaths int) error {
+	if err := encoder.Encode(eventRecord{
+		ContainerEvent: event,
+		ExitCode:       event.ExitCode(),
+		Health:         event.HealthStatus(),
+	}); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	if deaths == 0 {
+		return nil
+	}
+
+	loop := event
+	loop.Action = actionRestartLoop
+	if err := encoder.Encode(eventRecord{ContainerEvent: loop, Deaths: deaths}); err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+//go:build unit
+
+package operations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bufferOutput captures command output for assertions
+type bufferOutput struct {
+	buf      bytes.Buffer
+	warnings []string
+}
+
+func (o *bufferOutput) Success(msg string, args ...any) {}
+func (o *bufferOutput) Error(msg string, args ...any)   {}
+func (o *bufferOutput) Warning(msg string, args ...any) {
+	o.warnings = append(o.warnings, fmt.Sprintf(msg, args...))
+}
+func (o *bufferOutput) Info(msg string, args ...any)   {}
+func (o *bufferOutput) Header(msg string, args ...any) {}
+func (o *bufferOutput) Muted(msg string, args ...any)  {}
+func (o *bufferOutput) Writer() io.Writer              { return &o.buf }
+func (o *bufferOutput) GetNoColor() bool               { return true }
+
+func crashLoopEvents(service string, deaths int) <-chan docker.ContainerEvent {
+	ch := make(chan docker.ContainerEvent, deaths*2+1)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := range deaths {
+		at := start.Add(time.Duration(i) * 5 * time.Second)
+		container := "app-" + service + "-1"
+		ch <- docker.ContainerEvent{Time: at, Action: "die", Container: container, Service: service, Project: "app",
+			Attributes: map[string]string{docker.EventAttrExitCode: "137"}}
+		ch <- docker.ContainerEvent{Time: at.Add(time.Second), Action: "start", Container: container, Service: service, Project: "app"}
+	}
+	ch <- docker.ContainerEvent{Action: "exec_start: pg_isready", Container: "app-db-1", Service: "db"}
+	close(ch)
+	return ch
+}
+
+func TestNewEventsHandler(t *testing.T) {
+	handler := NewEventsHandler()
+
+	assert.NotNil(t, handler.logger, "Logger should be initialized")
+	assert.NoError(t, handler.ValidateArgs(nil))
+	assert.Empty(t, handler.GetRequiredFlags())
+}
+
+func TestValidateEventsFlags(t *testing.T) {
+	valid := core.EventsFlags{Format: eventsFormatText, RestartWindow: 60, RestartThreshold: 3}
+	assert.NoError(t, validateEventsFlags(&valid))
+
+	badFormat := valid
+	badFormat.Format = "yaml"
+	assert.Error(t, validateEventsFlags(&badFormat))
+
+	badWindow := valid
+	badWindow.RestartWindow = 0
+	assert.Error(t, validateEventsFlags(&badWindow))
+
+	badThreshold := valid
+	badThreshold.RestartThreshold = 1
+	assert.Error(t, validateEventsFlags(&badThreshold))
+}
+
+func TestEventsHandler_Consume_Text(t *testing.T) {
+	out := &bufferOutput{}
+	flags := &core.EventsFlags{Format: eventsFormatText, RestartWindow: 60, RestartThreshold: 3}
+
+	err := NewEventsHandler().consume(context.Background(), &base.BaseCommand{Output: out}, crashLoopEvents("kafka-broker", 3), make(chan error), nil, flags)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out.buf.String()), "\n")
+	assert.Len(t, lines, 6, "exec events should be dropped")
+	assert.Contains(t, lines[0], "app/kafka-broker")
+	assert.Contains(t, lines[0], "die (exit 137)")
+	require.Len(t, out.warnings, 1)
+	assert.Contains(t, out.warnings[0], "app-kafka-broker-1 died 3 times")
+}
+
+func TestEventsHandler_Consume_JSONWithServiceFilter(t *testing.T) {
+	out := &bufferOutput{}
+	flags := &core.EventsFlags{Format: eventsFormatJSON, RestartWindow: 60, RestartThreshold: 2}
+
+	err := NewEventsHandler().consume(context.Background(), &base.BaseCommand{Output: out}, crashLoopEvents("kafka-broker", 2), make(chan error), []string{"kafka-broker"}, flags)
+	require.NoError(t, err)
+
+	var records []map[string]any
+	decoder := json.NewDecoder(&out.buf)
+	for decoder.More() {
+		var record map[string]any
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 5)
+	assert.Equal(t, "137", records[0]["exit_code"])
+	assert.Equal(t, actionRestartLoop, records[3]["action"])
+	assert.EqualValues(t, 2, records[3]["deaths"])
+	assert.Empty(t, out.warnings)
+
+	out.buf.Reset()
+	err = NewEventsHandler().consume(context.Background(), &base.BaseCommand{Output: out}, crashLoopEvents("kafka-broker", 1), make(chan error), []string{"postgres"}, flags)
+	require.NoError(t, err)
+	assert.Empty(t, out.buf.String())
+}
@@ -2,6 +2,12 @@ package operations
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -11,10 +17,13 @@ import (
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
 	"github.com/otto-nation/otto-stack/internal/pkg/registry"
 	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
 )
 
 // LogsHandler handles the logs command
@@ -37,6 +46,8 @@ func (h *LogsHandler) Handle(ctx context.Context, cmd *cobra.Command, args []str
 		return h.handleProjectContext(ctx, cmd, args, base)
 	case *clicontext.SharedMode:
 		return h.handleSharedContext(ctx, cmd, args, base, mode)
+	case *clicontext.WorkspaceMode:
+		return h.handleWorkspaceContext(ctx, cmd, args, base, mode)
 	default:
 		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
 	}
@@ -53,6 +64,7 @@ func (h *LogsHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 	if err != nil {
 		return err
 	}
+	common.TouchShared(setup.Config.Project.Name)
 
 	stackService, err := common.NewServiceManager(false)
 	if err != nil {
@@ -64,9 +76,24 @@ func (h *LogsHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
 	}
 
-	tail := flags.Tail
-	if tail == "" {
-		tail = core.DefaultLogTailLines
+	if flags.Record {
+		return h.record(ctx, base, setup.Config, serviceConfigs, stackService)
+	}
+
+	filter, err := newLogFilter(flags)
+	if err != nil {
+		return err
+	}
+
+	if flags.Init != "" {
+		return h.showInit(base, flags.Init, filter)
+	}
+
+	if flags.Previous {
+		if flags.Follow {
+			return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationPreviousConflictsFollow, nil)
+		}
+		return h.showPrevious(base, args, flags, filter)
 	}
 
 	logReq := services.LogRequest{
@@ -74,10 +101,12 @@ func (h *LogsHandler) handleProjectContext(ctx context.Context, cmd *cobra.Comma
 		ServiceConfigs: serviceConfigs,
 		Follow:         flags.Follow,
 		Timestamps:     flags.Timestamps,
-		Tail:           tail,
+		Tail:           logTail(flags),
 		Since:          flags.Since,
+		Until:          flags.Until,
 		NoColor:        base.Output.GetNoColor(),
 		Writer:         base.Output.Writer(),
+		Filter:         filter,
 	}
 
 	return stackService.Logs(ctx, logReq)
@@ -106,23 +135,233 @@ func (h *LogsHandler) handleSharedContext(ctx context.Context, cmd *cobra.Comman
 		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
 	}
 
-	tail := flags.Tail
-	if tail == "" {
-		tail = core.DefaultLogTailLines
+	filter, err := newLogFilter(flags)
+	if err != nil {
+		return err
 	}
 
 	options := docker.LogOptions{
 		Services:   args,
 		Follow:     flags.Follow,
 		Timestamps: flags.Timestamps,
-		Tail:       tail,
+		Tail:       logTail(flags),
 		Since:      flags.Since,
+		Until:      flags.Until,
 	}
 
-	consumer := docker.NewServiceLogConsumer(base.Output.Writer(), base.Output.GetNoColor(), len(args))
+	consumer := docker.NewServiceLogConsumer(base.Output.Writer(), base.Output.GetNoColor(), len(args)).WithFilter(filter)
 	return composeManager.Logs(ctx, core.SharedDir, consumer, options.ToSDK())
 }
 
+// handleWorkspaceContext shows the logs of every workspace project through one
+// consumer, so lines carry their container name and are told apart by project.
+// With --follow the projects are streamed at once rather than in turn.
+func (h *LogsHandler) handleWorkspaceContext(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, mode *clicontext.WorkspaceMode) error {
+	if err := common.ValidateWorkspace(mode, args); err != nil {
+		return err
+	}
+
+	flags, err := core.ParseLogsFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	// These read a single project's recorded files
+	for _, single := range []struct {
+		flag string
+		set  bool
+	}{{core.FlagRecord, flags.Record}, {core.FlagInit, flags.Init != ""}, {core.FlagPrevious, flags.Previous}} {
+		if single.set {
+			return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationWorkspaceFlagUnsupported, single.flag)
+		}
+	}
+
+	filter, err := newLogFilter(flags)
+	if err != nil {
+		return err
+	}
+
+	stackService, err := common.NewServiceManager(false)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentStack, messages.ErrorsStackCreateFailed, err)
+	}
+
+	var requests []services.LogRequest
+	serviceCount := 0
+	err = common.ForEachWorkspaceProject(mode, func(*clicontext.ProjectMode) error {
+		setup, cleanup, err := middleware.CoreSetupOrCreate(ctx, base)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		serviceConfigs, err := common.ResolveServiceConfigs(nil, setup)
+		if err != nil {
+			return err
+		}
+		common.TouchShared(setup.Config.Project.Name)
+		serviceCount += len(serviceConfigs)
+		requests = append(requests, services.LogRequest{
+			Project:        setup.Config.Project.Name,
+			ServiceConfigs: serviceConfigs,
+			Follow:         flags.Follow,
+			Timestamps:     flags.Timestamps,
+			Tail:           logTail(flags),
+			Since:          flags.Since,
+			Until:          flags.Until,
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	consumer := docker.NewServiceLogConsumer(base.Output.Writer(), base.Output.GetNoColor(), serviceCount).WithFilter(filter)
+	if !flags.Follow {
+		for _, req := range requests {
+			req.Consumer = consumer
+			if err := stackService.Logs(ctx, req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	errs := make(chan error, len(requests))
+	var wg sync.WaitGroup
+	for _, req := range requests {
+		req.Consumer = consumer
+		wg.Go(func() { errs <- stackService.Logs(ctx, req) })
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record follows every service's output into .otto-stack/logs until the stack
+// stops or the process is terminated. up starts it in the background.
+func (h *LogsHandler) record(ctx context.Context, base *base.BaseCommand, cfg *config.Config, serviceConfigs []types.ServiceConfig, stackService *services.Service) error {
+	dir := logrecorder.Dir()
+	if logrecorder.Running(dir) {
+		base.Output.Info("%s", messages.LifecycleLogRecorderRunning)
+		return nil
+	}
+	if err := logrecorder.WritePID(dir); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsLogReadFailed, err)
+	}
+	defer func() { _ = logrecorder.RemovePID(dir) }()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	recorder := logrecorder.New(dir, cfg.Project.Name, logrecorder.OptionsFromConfig(cfg.Logs))
+	defer func() { _ = recorder.Close() }()
+
+	err := stackService.Logs(ctx, services.LogRequest{
+		Project:        cfg.Project.Name,
+		ServiceConfigs: serviceConfigs,
+		Follow:         true,
+		Timestamps:     true,
+		Tail:           logTailAll,
+		Consumer:       recorder,
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// showPrevious prints the logs recorded during the previous run, one service after another
+func (h *LogsHandler) showPrevious(base *base.BaseCommand, args []string, flags *core.LogsFlags, filter *docker.LogFilter) error {
+	dir := logrecorder.Dir()
+	names := args
+	if len(names) == 0 {
+		var err error
+		if names, err = logrecorder.PreviousServices(dir); err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsLogReadFailed, err)
+		}
+	}
+
+	consumer := docker.NewServiceLogConsumer(base.Output.Writer(), base.Output.GetNoColor(), len(names)).WithFilter(filter)
+	printed := false
+	for _, name := range names {
+		lines, err := logrecorder.ReadPrevious(dir, name)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsLogReadFailed, err)
+		}
+		for _, line := range tailLines(lines, logTail(flags)) {
+			message := line.Message
+			if flags.Timestamps && line.Time != "" {
+				message = line.Time + " " + message
+			}
+			consumer.Log(name, message)
+			printed = true
+		}
+	}
+	if !printed {
+		base.Output.Info("%s", messages.LifecycleNoPreviousLogs)
+	}
+	return nil
+}
+
+// showInit prints the captured output of each of a service's init scripts from its last run
+func (h *LogsHandler) showInit(base *base.BaseCommand, service string, filter *docker.LogFilter) error {
+	paths, err := logrecorder.InitLogs(logrecorder.Dir(), service)
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsLogReadFailed, err)
+	}
+	if len(paths) == 0 {
+		base.Output.Info(messages.LifecycleNoInitLogs, service)
+		return nil
+	}
+
+	consumer := docker.NewServiceLogConsumer(base.Output.Writer(), base.Output.GetNoColor(), 1).WithFilter(filter)
+	for i, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsLogReadFailed, err)
+		}
+		base.Output.Muted(messages.LifecycleInitScriptHeader, i+1, path)
+		for line := range strings.Lines(string(data)) {
+			consumer.Log(service, strings.TrimRight(line, "\n"))
+		}
+	}
+	return nil
+}
+
+// tailLines applies --tail to recorded lines; "all" or an invalid count keeps every line
+func tailLines(lines []logrecorder.Line, tail string) []logrecorder.Line {
+	n, err := strconv.Atoi(tail)
+	if err != nil || n < 0 || n >= len(lines) {
+		return lines
+	}
+	return lines[len(lines)-n:]
+}
+
+// logTailAll asks Docker for a container's whole log
+const logTailAll = "all"
+
+// logTail returns the --tail value, defaulting to core.DefaultLogTailLines
+func logTail(flags *core.LogsFlags) string {
+	if flags.Tail == "" {
+		return core.DefaultLogTailLines
+	}
+	return flags.Tail
+}
+
+// newLogFilter builds the line filter for --grep, --level and --json-pretty
+func newLogFilter(flags *core.LogsFlags) (*docker.LogFilter, error) {
+	filter, err := docker.NewLogFilter(flags.Grep, flags.Level, flags.JSONPretty)
+	if err != nil {
+		return nil, pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationLogFilterInvalid, err)
+	}
+	return filter, nil
+}
+
 // ValidateArgs validates the command arguments
 func (h *LogsHandler) ValidateArgs(args []string) error {
 	return nil
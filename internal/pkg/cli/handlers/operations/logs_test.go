@@ -5,6 +5,8 @@ package operations
 import (
 	"testing"
 
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -19,3 +21,29 @@ func TestLogsHandler_ValidateArgs_WithArgs(t *testing.T) {
 	assert.NoError(t, handler.ValidateArgs(nil))
 	assert.NoError(t, handler.ValidateArgs([]string{"redis", "postgres"}))
 }
+
+func TestLogTail(t *testing.T) {
+	assert.Equal(t, core.DefaultLogTailLines, logTail(&core.LogsFlags{}))
+	assert.Equal(t, "all", logTail(&core.LogsFlags{Tail: "all"}))
+}
+
+func TestNewLogFilter(t *testing.T) {
+	filter, err := newLogFilter(&core.LogsFlags{})
+	assert.NoError(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = newLogFilter(&core.LogsFlags{Grep: "refused", Level: "warn+", JSONPretty: true})
+	assert.NoError(t, err)
+	assert.NotNil(t, filter)
+
+	_, err = newLogFilter(&core.LogsFlags{Level: "chatty"})
+	assert.Error(t, err)
+}
+
+func TestTailLines(t *testing.T) {
+	lines := []logrecorder.Line{{Message: "a"}, {Message: "b"}, {Message: "c"}}
+	assert.Equal(t, lines[1:], tailLines(lines, "2"))
+	assert.Equal(t, lines, tailLines(lines, "10"))
+	assert.Equal(t, lines, tailLines(lines, "all"))
+	assert.Empty(t, tailLines(lines, "0"))
+}
@@ -0,0 +1,291 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// statsRedrawInterval is how often the streaming table is redrawn
+const statsRedrawInterval = time.Second
+
+// Output formats accepted by stats
+const (
+	statsFormatTable = "table"
+	statsFormatJSON  = "json"
+)
+
+// StatsHandler handles the stats command
+type StatsHandler struct {
+	logger *slog.Logger
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler() *StatsHandler {
+	return &StatsHandler{
+		logger: logger.GetLogger(),
+	}
+}
+
+// statsTarget is a running container sampled by the stats command
+type statsTarget struct {
+	Service   string
+	Container string
+}
+
+// statsSample is one line of newline-delimited JSON output
+type statsSample struct {
+	Time      time.Time             `json:"time"`
+	Service   string                `json:"service"`
+	Container string                `json:"container"`
+	Stats     docker.ContainerStats `json:"stats"`
+}
+
+// Handle executes the stats command
+func (h *StatsHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParseStatsFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	if ci.GetFlags(cmd).JSON {
+		flags.Format = statsFormatJSON
+	}
+	if err := validateStatsFlags(flags); err != nil {
+		return err
+	}
+
+	execCtx, err := middleware.ExecContextOrDetect(ctx)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := docker.NewClient(h.logger)
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = dockerClient.Close() }()
+
+	var targets []statsTarget
+	switch mode := execCtx.(type) {
+	case *clicontext.ProjectMode:
+		targets, err = h.projectTargets(ctx, args, base, dockerClient)
+	case *clicontext.SharedMode:
+		targets, err = h.sharedTargets(ctx, args, dockerClient, mode)
+	case *clicontext.WorkspaceMode:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldProjectPath, messages.ValidationWorkspaceUnsupported, cmd.Name())
+	default:
+		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		base.Output.Info("%s", messages.LifecycleNothingToSample)
+		return nil
+	}
+
+	if flags.NoStream {
+		return h.sampleOnce(ctx, base, dockerClient, targets, flags.Format)
+	}
+	return h.stream(ctx, base, dockerClient, targets, flags.Format)
+}
+
+// ValidateArgs validates the command arguments
+func (h *StatsHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *StatsHandler) GetRequiredFlags() []string {
+	return []string{}
+}
+
+// validateStatsFlags rejects output formats stats cannot stream
+func validateStatsFlags(flags *core.StatsFlags) error {
+	switch flags.Format {
+	case "", statsFormatTable, statsFormatJSON:
+		return nil
+	default:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationStatsFormatInvalid, flags.Format)
+	}
+}
+
+// projectTargets returns the running containers of the project's services. Services
+// with no project container fall back to their shared container when it is running.
+func (h *StatsHandler) projectTargets(ctx context.Context, args []string, base *base.BaseCommand, dockerClient *docker.Client) ([]statsTarget, error) {
+	setup, cleanup, err := middleware.CoreSetupOrCreate(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	serviceConfigs, err := common.ResolveServiceConfigs(args, setup)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := dockerClient.ListContainers(ctx, setup.Config.Project.Name)
+	if err != nil {
+		return nil, err
+	}
+	byService := make(map[string]docker.ContainerInfo, len(containers))
+	for _, c := range containers {
+		byService[c.Service] = c
+	}
+
+	var targets []statsTarget
+	for _, name := range filterInitContainers(serviceConfigs) {
+		if c, ok := byService[name]; ok {
+			if c.State == docker.StateRunning {
+				targets = append(targets, statsTarget{Service: name, Container: c.Name})
+			}
+			continue
+		}
+		sharedName := core.SharedContainerPrefix + name
+		if dockerClient.InspectContainer(ctx, sharedName).State == docker.StateRunning {
+			targets = append(targets, statsTarget{Service: name, Container: sharedName})
+		}
+	}
+	return targets, nil
+}
+
+// sharedTargets returns the running shared containers, limited to args when given
+func (h *StatsHandler) sharedTargets(ctx context.Context, args []string, dockerClient *docker.Client, mode *clicontext.SharedMode) ([]statsTarget, error) {
+	reg := registry.NewManager(mode.Shared.Root)
+	regData, err := reg.Load()
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	if len(args) > 0 {
+		if err := common.VerifyServicesInRegistry(args, regData); err != nil {
+			return nil, pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsServiceNotInRegistry, err)
+		}
+	}
+
+	var targets []statsTarget
+	for service, info := range regData.Containers {
+		if info == nil || (len(args) > 0 && !slices.Contains(args, service)) {
+			continue
+		}
+		if dockerClient.InspectContainer(ctx, info.Name).State == docker.StateRunning {
+			targets = append(targets, statsTarget{Service: service, Container: info.Name})
+		}
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Service < targets[j].Service })
+	return targets, nil
+}
+
+// sampleOnce prints a single sample of every target
+func (h *StatsHandler) sampleOnce(ctx context.Context, base *base.BaseCommand, dockerClient *docker.Client, targets []statsTarget, format string) error {
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.Container
+	}
+	collected, failed := dockerClient.CollectStats(ctx, names)
+
+	rows := make([]display.StatsRow, 0, len(targets))
+	for _, t := range targets {
+		stats, ok := collected[t.Container]
+		if !ok {
+			base.Output.Warning(messages.WarningsStatsUnavailable, t.Container, failed[t.Container])
+			continue
+		}
+		rows = append(rows, display.StatsRow{Service: t.Service, Container: t.Container, Stats: stats})
+	}
+
+	if format == statsFormatJSON {
+		encoder := json.NewEncoder(base.Output.Writer())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	}
+	display.RenderStatsTable(base.Output.Writer(), rows, time.Now(), false)
+	return nil
+}
+
+// stream samples every target continuously until interrupted. Table output is
+// redrawn every statsRedrawInterval; JSON output emits each sample as it arrives.
+func (h *StatsHandler) stream(ctx context.Context, base *base.BaseCommand, dockerClient *docker.Client, targets []statsTarget, format string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serviceOf := make(map[string]string, len(targets))
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		serviceOf[t.Container] = t.Service
+		names[i] = t.Container
+	}
+
+	samples := dockerClient.StreamContainerStats(ctx, names)
+	encoder := json.NewEncoder(base.Output.Writer())
+	latest := make(map[string]docker.ContainerStats, len(targets))
+	ticker := time.NewTicker(statsRedrawInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if format == statsFormatTable {
+				h.redraw(base, targets, latest)
+			}
+		case sample, ok := <-samples:
+			if !ok {
+				return nil
+			}
+			if sample.Err != nil {
+				base.Output.Warning(messages.WarningsStatsUnavailable, sample.Container, sample.Err)
+				continue
+			}
+			latest[sample.Container] = sample.Stats
+			if format == statsFormatJSON {
+				if err := encoder.Encode(statsSample{
+					Time:      time.Now(),
+					Service:   serviceOf[sample.Container],
+					Container: sample.Container,
+					Stats:     sample.Stats,
+				}); err != nil {
+					return fmt.Errorf("write stats sample: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// redraw renders the latest sample of each target that has reported one
+func (h *StatsHandler) redraw(base *base.BaseCommand, targets []statsTarget, latest map[string]docker.ContainerStats) {
+	rows := make([]display.StatsRow, 0, len(targets))
+	for _, t := range targets {
+		if stats, ok := latest[t.Container]; ok {
+			rows = append(rows, display.StatsRow{Service: t.Service, Container: t.Container, Stats: stats})
+		}
+	}
+	noColor := base.Output.GetNoColor()
+	if noColor {
+		_, _ = fmt.Fprintln(base.Output.Writer())
+	}
+	display.RenderStatsTable(base.Output.Writer(), rows, time.Now(), !noColor)
+}
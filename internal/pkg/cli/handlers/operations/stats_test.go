@@ -0,0 +1,26 @@
+//go:build unit
+
+package operations
+
+import (
+	"testing"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStatsHandler(t *testing.T) {
+	handler := NewStatsHandler()
+
+	assert.NotNil(t, handler)
+	assert.NotNil(t, handler.logger, "Logger should be initialized")
+	assert.NoError(t, handler.ValidateArgs([]string{testhelpers.TestServiceName}))
+	assert.Empty(t, handler.GetRequiredFlags())
+}
+
+func TestValidateStatsFlags(t *testing.T) {
+	assert.NoError(t, validateStatsFlags(&core.StatsFlags{Format: statsFormatTable}))
+	assert.NoError(t, validateStatsFlags(&core.StatsFlags{Format: statsFormatJSON, NoStream: true}))
+	assert.Error(t, validateStatsFlags(&core.StatsFlags{Format: "yaml"}))
+}
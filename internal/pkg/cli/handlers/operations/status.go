@@ -53,6 +53,8 @@ func (h *StatusHandler) Handle(ctx context.Context, cmd *cobra.Command, args []s
 			return h.handleProjectSharedStatus(ctx, cmd, args, base, mode, projectName)
 		case *clicontext.SharedMode:
 			return h.handleProjectSharedStatus(ctx, cmd, args, base, mode, projectName)
+		case *clicontext.WorkspaceMode:
+			return h.handleProjectSharedStatus(ctx, cmd, args, base, mode, projectName)
 		}
 	}
 
@@ -64,6 +66,11 @@ func (h *StatusHandler) Handle(ctx context.Context, cmd *cobra.Command, args []s
 		return h.handleProjectStatus(ctx, cmd, args, base)
 	case *clicontext.SharedMode:
 		return h.handleSharedStatus(ctx, cmd, args, base, mode)
+	case *clicontext.WorkspaceMode:
+		if showAll || showShared {
+			return h.handleSharedStatus(ctx, cmd, args, base, mode)
+		}
+		return h.handleWorkspaceStatus(ctx, cmd, args, base, mode)
 	default:
 		return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeInternal, messages.ErrorsContextUnknownMode, execCtx)
 	}
@@ -76,6 +83,12 @@ func (h *StatusHandler) handleProjectStatus(ctx context.Context, cmd *cobra.Comm
 		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
 	}
 
+	if statusFlags.Watch {
+		if err := validateWatchFlags(statusFlags, ciFlags); err != nil {
+			return err
+		}
+	}
+
 	if !ciFlags.Quiet {
 		base.Output.Header(messages.LifecycleStatus)
 	}
@@ -90,6 +103,11 @@ func (h *StatusHandler) handleProjectStatus(ctx context.Context, cmd *cobra.Comm
 	if err != nil {
 		return err
 	}
+	common.TouchShared(setup.Config.Project.Name)
+
+	if statusFlags.Watch {
+		return h.watchProjectStatus(ctx, base, setup.Config.Project.Name, serviceConfigs, statusFlags)
+	}
 
 	statuses, err := h.getServiceStatuses(ctx, setup.Config.Project.Name, serviceConfigs, &ciFlags)
 	if err != nil {
@@ -112,6 +130,15 @@ func (h *StatusHandler) handleProjectStatus(ctx context.Context, cmd *cobra.Comm
 func (h *StatusHandler) handleSharedStatus(ctx context.Context, cmd *cobra.Command, _ []string, base *base.BaseCommand, mode clicontext.ExecutionMode) error {
 	ciFlags := ci.GetFlags(cmd)
 	showAll, _ := cmd.Flags().GetBool(docker.FlagAll)
+	statusFlags, err := core.ParseStatusFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	if statusFlags.Watch {
+		if err := validateWatchFlags(statusFlags, ciFlags); err != nil {
+			return err
+		}
+	}
 
 	if !ciFlags.Quiet {
 		if showAll {
@@ -121,13 +148,7 @@ func (h *StatusHandler) handleSharedStatus(ctx context.Context, cmd *cobra.Comma
 		}
 	}
 
-	var sharedRoot string
-	switch m := mode.(type) {
-	case *clicontext.ProjectMode:
-		sharedRoot = m.Shared.Root
-	case *clicontext.SharedMode:
-		sharedRoot = m.Shared.Root
-	}
+	sharedRoot := mode.SharedRoot()
 
 	dockerClient, err := docker.NewClient(h.logger)
 	if err != nil {
@@ -149,6 +170,10 @@ func (h *StatusHandler) handleSharedStatus(ctx context.Context, cmd *cobra.Comma
 		base.Output.Info(messages.InfoReconciledRegistry, len(result.Removed))
 	}
 
+	if statusFlags.Watch {
+		return h.watchStatus(ctx, base, dockerClient, docker.NewSharedFilter(), watchInterval(statusFlags), h.sharedSnapshot(reg, dockerClient))
+	}
+
 	sharedContainers, err := reg.List()
 	if err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsStatusListSharedFailed, err)
@@ -200,13 +225,7 @@ func (h *StatusHandler) handleProjectSharedStatusWithRequest(req statusRequest)
 		req.base.Output.Header(messages.InfoSharedContainersForProject, req.projectName)
 	}
 
-	var sharedRoot string
-	switch m := req.mode.(type) {
-	case *clicontext.ProjectMode:
-		sharedRoot = m.Shared.Root
-	case *clicontext.SharedMode:
-		sharedRoot = m.Shared.Root
-	}
+	sharedRoot := req.mode.SharedRoot()
 
 	reg := registry.NewManager(sharedRoot)
 	if _, err := reg.Load(); err != nil {
@@ -251,6 +270,105 @@ func (h *StatusHandler) handleProjectSharedStatusWithRequest(req statusRequest)
 	return nil
 }
 
+// handleWorkspaceStatus reports the services of every workspace project grouped
+// by project. Shared containers are listed once, however many members use them.
+func (h *StatusHandler) handleWorkspaceStatus(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand, mode *clicontext.WorkspaceMode) error {
+	ciFlags := ci.GetFlags(cmd)
+	statusFlags, err := core.ParseStatusFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	if statusFlags.Watch {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationWorkspaceFlagUnsupported, core.FlagWatch)
+	}
+	if err := common.ValidateWorkspace(mode, args); err != nil {
+		return err
+	}
+
+	if !ciFlags.Quiet {
+		base.Output.Header(messages.WorkspaceHeader, mode.Workspace.Root, len(mode.Workspace.Projects))
+	}
+
+	reg := registry.NewManager(mode.Shared.Root)
+	if _, err := reg.Load(); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	containers, err := reg.List()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsStatusListSharedFailed, err)
+	}
+
+	response := display.WorkspaceStatusResponse{Workspace: mode.Workspace.Root}
+	serviceConfigs := make([][]types.ServiceConfig, 0, len(mode.Workspace.Projects))
+	sharedUsed := make(map[string]*registry.ContainerInfo)
+	err = common.ForEachWorkspaceProject(mode, func(project *clicontext.ProjectMode) error {
+		setup, cleanup, err := middleware.CoreSetupOrCreate(ctx, base)
+		if err != nil {
+			return ci.FormatError(ciFlags, err)
+		}
+		defer cleanup()
+
+		configs, err := h.resolveServices(nil, setup, &ciFlags)
+		if err != nil {
+			return err
+		}
+		common.TouchShared(setup.Config.Project.Name)
+
+		// Shared services run outside the project, so they are reported once below
+		var local []types.ServiceConfig
+		for _, svc := range configs {
+			if container, ok := containers[svc.Name]; ok && h.containsProject(container.Projects, setup.Config.Project.Name) {
+				sharedUsed[svc.Name] = container
+				continue
+			}
+			local = append(local, svc)
+		}
+
+		statuses, err := h.getServiceStatuses(ctx, setup.Config.Project.Name, local, &ciFlags)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsStatusGetStatusesFailed, err)
+		}
+		response.Projects = append(response.Projects, display.WorkspaceProjectStatus{
+			Project:  setup.Config.Project.Name,
+			Path:     mode.Workspace.RelPath(project.Project),
+			Services: statuses,
+			Count:    len(statuses),
+		})
+		serviceConfigs = append(serviceConfigs, local)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(sharedUsed) > 0 {
+		dockerClient, err := docker.NewClient(h.logger)
+		if err != nil {
+			return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+		}
+		defer func() { _ = dockerClient.Close() }()
+		response.SharedContainers = h.buildSharedStatuses(ctx, sharedUsed, dockerClient)
+	}
+
+	if ciFlags.JSON || statusFlags.Format == "json" {
+		ci.OutputResult(ciFlags, response, core.ExitSuccess)
+		return nil
+	}
+	if statusFlags.Format == "yaml" {
+		return yaml.NewEncoder(base.Output.Writer()).Encode(response)
+	}
+
+	for i, project := range response.Projects {
+		base.Output.Header(messages.WorkspaceProjectHeader, project.Project, project.Path)
+		h.displayStatus(base, cmd, project.Services, serviceConfigs[i])
+	}
+	if len(response.SharedContainers) > 0 {
+		base.Output.Header("%s", messages.WorkspaceSharedHeader)
+		h.displaySharedStatus(base, cmd, response.SharedContainers)
+	}
+	return nil
+}
+
 func (h *StatusHandler) resolveServices(args []string, setup *common.CoreSetup, ciFlags *ci.Flags) ([]types.ServiceConfig, error) {
 	serviceConfigs, err := common.ResolveServiceConfigs(args, setup)
 	if err != nil {
@@ -270,6 +388,7 @@ func (h *StatusHandler) getServiceStatuses(ctx context.Context, projectName stri
 	statuses, err := stackService.Status(ctx, services.StatusRequest{
 		Project:  projectName,
 		Services: filteredServices,
+		Stats:    true,
 	})
 	if err != nil {
 		return nil, ci.FormatError(*ciFlags, pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentStack, messages.ErrorsStackGetStatusFailed, err))
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
 	"github.com/otto-nation/otto-stack/test/testhelpers"
 	"github.com/stretchr/testify/assert"
 )
@@ -65,3 +66,10 @@ func TestDefaultLogTailConstant(t *testing.T) {
 	assert.Equal(t, "100", defaultLogTailLines,
 		"Default log tail lines should match the hardcoded value in commands.go")
 }
+
+func TestValidateWatchFlags(t *testing.T) {
+	assert.NoError(t, validateWatchFlags(&core.StatusFlags{Watch: true, Format: "table", Interval: 2}, ci.Flags{}))
+	assert.Error(t, validateWatchFlags(&core.StatusFlags{Watch: true, Format: "json", Interval: 2}, ci.Flags{}))
+	assert.Error(t, validateWatchFlags(&core.StatusFlags{Watch: true, Format: "table", Interval: 2}, ci.Flags{JSON: true}))
+	assert.Error(t, validateWatchFlags(&core.StatusFlags{Watch: true, Format: "table", Interval: 0}, ci.Flags{}))
+}
@@ -0,0 +1,123 @@
+package operations
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// statusSnapshot returns the statuses to draw for one watch refresh
+type statusSnapshot func(ctx context.Context) ([]display.ServiceStatus, error)
+
+// validateWatchFlags rejects flag combinations that cannot be redrawn in place
+func validateWatchFlags(flags *core.StatusFlags, ciFlags ci.Flags) error {
+	if ciFlags.JSON || (flags.Format != "" && flags.Format != "table") {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationWatchRequiresTable, nil)
+	}
+	if flags.Interval < 1 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationWatchIntervalInvalid, flags.Interval)
+	}
+	return nil
+}
+
+// watchInterval returns the refresh interval requested with --interval
+func watchInterval(flags *core.StatusFlags) time.Duration {
+	return time.Duration(flags.Interval) * time.Second
+}
+
+// watchProjectStatus runs the watch dashboard for the services of a project
+func (h *StatusHandler) watchProjectStatus(ctx context.Context, base *base.BaseCommand, projectName string, serviceConfigs []types.ServiceConfig, flags *core.StatusFlags) error {
+	stackService, err := common.NewServiceManager(false)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentStack, messages.ErrorsStackCreateFailed, err)
+	}
+
+	queryNames := filterInitContainers(serviceConfigs)
+	snapshot := func(ctx context.Context) ([]display.ServiceStatus, error) {
+		statuses, err := stackService.Status(ctx, services.StatusRequest{Project: projectName, Services: queryNames, Stats: true})
+		if err != nil {
+			return nil, err
+		}
+		return display.BuildServiceStatuses(statuses, serviceConfigs), nil
+	}
+
+	return h.watchStatus(ctx, base, stackService.DockerClient, docker.NewProjectFilter(projectName), watchInterval(flags), snapshot)
+}
+
+// watchStatus redraws the status table until interrupted. It refreshes every interval
+// and immediately whenever Docker reports a state or health event for a container
+// matching filter. If the event stream fails, it falls back to polling only.
+func (h *StatusHandler) watchStatus(ctx context.Context, base *base.BaseCommand, dockerClient *docker.Client, filter filters.Args, interval time.Duration, snapshot statusSnapshot) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	events, eventErrs := dockerClient.StreamContainerEvents(ctx, filter)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	watch := display.NewStatusWatch()
+	refresh := func() {
+		statuses, err := snapshot(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				base.Output.Warning("%s: %v", messages.ErrorsStatusGetStatusesFailed, err)
+			}
+			return
+		}
+		now := time.Now()
+		watch.Observe(statuses, now)
+		_ = display.RenderWatchFrame(base.Output.Writer(), statuses, watch, now, interval, base.Output.GetNoColor())
+	}
+
+	refresh()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			refresh()
+		case event, ok := <-events:
+			if !ok {
+				events = nil
+				continue
+			}
+			if event.IsStateChange() {
+				refresh()
+			}
+		case err := <-eventErrs:
+			base.Output.Warning(messages.WarningsEventsStreamFailed, err)
+			events, eventErrs = nil, nil
+		}
+	}
+}
+
+// sharedSnapshot builds a watch snapshot from the registry, re-reading it on every
+// refresh so containers registered or released meanwhile show up.
+func (h *StatusHandler) sharedSnapshot(reg *registry.Manager, dockerClient *docker.Client) statusSnapshot {
+	return func(ctx context.Context) ([]display.ServiceStatus, error) {
+		containers, err := reg.List()
+		if err != nil {
+			return nil, err
+		}
+		shared := h.buildSharedStatuses(ctx, containers, dockerClient)
+		sort.Slice(shared, func(i, j int) bool { return shared[i].Service < shared[j].Service })
+		return display.SharedToServiceStatuses(shared), nil
+	}
+}
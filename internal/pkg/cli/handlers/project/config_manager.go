@@ -73,6 +73,6 @@ func (cm *ConfigManager) generateServiceConfigContent(serviceName string) string
 	data, _ := yaml.Marshal(&config) // Simple struct marshal cannot fail
 
 	// Add comment header
-	header := fmt.Sprintf("# Documentation: %s/services/#%s\n\n", core.DocsURL, serviceName)
+	header := serviceSchemaModeline(serviceName) + fmt.Sprintf("# Documentation: %s/services/#%s\n\n", core.DocsURL, serviceName)
 	return header + string(data)
 }
@@ -98,7 +98,11 @@ func (h *ConflictsHandler) loadEnabledServices() ([]types.ServiceConfig, error)
 		return nil, err
 	}
 
-	return services.ResolveUpServices(cfg.Stack.Enabled, cfg)
+	serviceConfigs, err := services.ResolveUpServices(cfg.Stack.Enabled, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return ApplyPorts(serviceConfigs, cfg), nil
 }
 
 func (h *ConflictsHandler) detectSemanticConflicts(configs []types.ServiceConfig) []semanticConflict {
@@ -181,6 +185,11 @@ func (h *ConflictsHandler) parsePort(portStr string) int {
 
 // isPortInUse returns true if the TCP port is already bound on the host.
 func (h *ConflictsHandler) isPortInUse(port int) bool {
+	return portInUse(port)
+}
+
+// portInUse returns true if the TCP port is already bound on the host.
+func portInUse(port int) bool {
 	addr := fmt.Sprintf(":%d", port)
 	listener, err := net.Listen("tcp", addr)
 	if err != nil {
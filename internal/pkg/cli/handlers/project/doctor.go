@@ -3,9 +3,14 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+
+	"github.com/AlecAivazis/survey/v2"
 
 	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/middleware"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/logger"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
@@ -28,19 +33,57 @@ func (h *DoctorHandler) Handle(ctx context.Context, cmd *cobra.Command, args []s
 		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
 	}
 
+	jsonOutput := flags.Format == "json"
+	assumeYes := flags.Yes || ci.GetFlags(cmd).NonInteractive
+	if flags.Yes && !flags.Fix {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationYesRequiresFix, nil)
+	}
+	if flags.Fix && jsonOutput && !assumeYes {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFixJsonRequiresYes, nil)
+	}
+	if flags.Bundle != "" && flags.BundleLines < 1 {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationBundleLinesInvalid, flags.BundleLines)
+	}
+
 	logger.Info(logger.LogMsgProjectAction, logger.LogFieldAction, core.CommandDoctor, logger.LogFieldProject, "health_check")
 
-	if flags.Format == "json" {
-		results := h.healthCheckManager.collectResults(ctx)
-		allPassed := true
-		for _, r := range results {
-			if !r.Passed {
-				allPassed = false
-				break
+	var sharedRoot string
+	if mode, err := middleware.ExecContextOrDetect(ctx); err == nil {
+		sharedRoot = mode.SharedRoot()
+	}
+	env := newDoctorEnv(args, sharedRoot)
+	defer env.close()
+
+	// Per-service diagnostics need the project's service definitions, so an
+	// unknown service or missing project is reported up front
+	if len(args) > 0 {
+		if _, _, err := env.config(); err != nil {
+			return err
+		}
+	}
+
+	confirm := confirmFix
+	if assumeYes {
+		confirm = func(DoctorCheck) bool { return true }
+	}
+
+	if jsonOutput {
+		results := h.healthCheckManager.RunChecks(ctx, env)
+		if flags.Fix {
+			reports := h.healthCheckManager.ApplyFixes(ctx, env, results, confirm)
+			if len(reports) > 0 {
+				env.refresh()
+				results = h.healthCheckManager.RunChecks(ctx, env)
+				attachFixes(results, reports)
 			}
 		}
 		_ = json.NewEncoder(base.Output.Writer()).Encode(results)
-		if !allPassed {
+		if flags.Bundle != "" {
+			if _, err := writeBundle(ctx, env, flags.Bundle, results, flags.BundleLines); err != nil {
+				return bundleError(flags.Bundle, err)
+			}
+		}
+		if hasErrors(results) {
 			return pkgerrors.ErrSilentExit
 		}
 		return nil
@@ -49,15 +92,98 @@ func (h *DoctorHandler) Handle(ctx context.Context, cmd *cobra.Command, args []s
 	base.Output.Header(messages.DoctorHealthCheckHeader, core.AppName)
 	logger.Info("Starting health checks")
 
-	allGood := h.healthCheckManager.RunAllChecks(ctx, base)
+	results := h.healthCheckManager.RunChecks(ctx, env)
+	h.healthCheckManager.PrintResults(base, results)
+
+	if flags.Fix {
+		results = h.fix(ctx, env, base, results, confirm)
+	}
+
+	if flags.Bundle != "" {
+		if err := h.bundle(ctx, env, base, flags, results); err != nil {
+			return err
+		}
+	}
 
-	if allGood {
-		base.Output.Success(messages.SuccessAllChecksPassed, core.AppName)
-		logger.Info("All health checks passed")
+	if hasErrors(results) {
+		base.Output.Error(messages.DoctorSomeIssues)
+		logger.Error("Health checks failed")
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeInvalid, messages.DoctorHealthCheckFailed, nil)
+	}
+	if warnings := countWarnings(results); warnings > 0 {
+		base.Output.Warning(messages.DoctorWarningsFound, warnings)
 		return nil
 	}
 
-	base.Output.Error(messages.DoctorSomeIssues)
-	logger.Error("Health checks failed")
-	return pkgerrors.NewSystemError(pkgerrors.ErrCodeInvalid, messages.DoctorHealthCheckFailed, nil)
+	base.Output.Success(messages.SuccessAllChecksPassed, core.AppName)
+	logger.Info("All health checks passed")
+	return nil
+}
+
+// fix applies fixes for failed checks, reports what changed and, when
+// anything did, runs the checks again and returns the new results
+func (h *DoctorHandler) fix(ctx context.Context, env *doctorEnv, base *base.BaseCommand, results []CheckResult, confirm func(DoctorCheck) bool) []CheckResult {
+	base.Output.Header(messages.DoctorFixHeader)
+	reports := h.healthCheckManager.ApplyFixes(ctx, env, results, confirm)
+	if len(reports) == 0 {
+		base.Output.Info(messages.DoctorFixNone)
+		return results
+	}
+
+	for _, r := range reports {
+		switch {
+		case r.Declined:
+			base.Output.Muted(messages.DoctorFixDeclined, r.ID)
+		case r.Err != nil:
+			base.Output.Warning(messages.DoctorFixFailed, r.ID, r.Err)
+		}
+	}
+
+	changes := countChanges(reports)
+	if changes == 0 {
+		base.Output.Info(messages.DoctorFixNoChanges)
+		return results
+	}
+	base.Output.Success(messages.DoctorFixChanges, changes)
+	for _, r := range reports {
+		for _, change := range r.Changes {
+			base.Output.Info(messages.DoctorDetail, change)
+		}
+	}
+
+	env.refresh()
+	base.Output.Header(messages.DoctorFixRecheckHeader)
+	results = h.healthCheckManager.RunChecks(ctx, env)
+	h.healthCheckManager.PrintResults(base, results)
+	return results
+}
+
+// bundle writes the support bundle and reports anything it could not collect
+func (h *DoctorHandler) bundle(ctx context.Context, env *doctorEnv, base *base.BaseCommand, flags *core.DoctorFlags, results []CheckResult) error {
+	missing, err := writeBundle(ctx, env, flags.Bundle, results, flags.BundleLines)
+	if err != nil {
+		return bundleError(flags.Bundle, err)
+	}
+	base.Output.Success(messages.DoctorBundleWritten, flags.Bundle)
+	if len(missing) > 0 {
+		base.Output.Warning(messages.DoctorBundleIncomplete, len(missing), BundleManifestFile)
+	}
+	return nil
+}
+
+func bundleError(path string, err error) error {
+	return pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeOperationFail, messages.DoctorBundleFailed, path, err)
+}
+
+// confirmFix asks before applying the fix of check
+func confirmFix(check DoctorCheck) bool {
+	prompt := &survey.Confirm{
+		Message: fmt.Sprintf(messages.DoctorFixPrompt, check.ID, check.FixPrompt),
+		Default: true,
+	}
+	var confirmed bool
+	if err := survey.AskOne(prompt, &confirmed); err != nil {
+		return false
+	}
+	return confirmed
 }
@@ -0,0 +1,399 @@
+package project
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/internal/pkg/version"
+)
+
+// Files written to the support bundle, relative to its top-level directory
+const (
+	BundleManifestFile  = "manifest.json"
+	BundleConfigFile    = "config.yaml"
+	BundleComposeFile   = "generated/" + docker.DockerComposeFileName
+	BundleEnvFile       = "generated/" + core.EnvGeneratedFileName
+	BundleRegistryFile  = "registry/" + core.SharedRegistryFile
+	BundleStatusFile    = "status.json"
+	BundleDoctorFile    = "doctor.json"
+	BundleDockerFile    = "docker.json"
+	BundleBuildInfoFile = "build-info.json"
+	BundleLogsDir       = "logs"
+
+	// RedactedValue replaces secrets in bundle files
+	RedactedValue = "[REDACTED]"
+
+	bundleDirPrefix = "otto-stack-bundle-"
+	// Shorter secret values are too likely to match unrelated log text
+	minRedactedSecretLength = 4
+)
+
+// sensitiveKey matches configuration and environment keys holding secrets
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|access_?key|private_?key|credential)`)
+
+// bundleManifest describes what a support bundle holds and what could not be collected
+type bundleManifest struct {
+	CreatedAt time.Time `json:"created_at"`
+	Project   string    `json:"project,omitempty"`
+	Files     []string  `json:"files"`
+	Errors    []string  `json:"errors,omitempty"`
+}
+
+// bundleDocker is the Docker section of a support bundle
+type bundleDocker struct {
+	ServerVersion  string      `json:"server_version"`
+	ComposeVersion string      `json:"compose_version,omitempty"`
+	Info           system.Info `json:"info"`
+}
+
+// supportBundle collects diagnostics into a gzipped tarball that can be
+// attached to an issue. Collection is best effort: anything that cannot be
+// gathered is listed in the manifest instead of failing the bundle.
+type supportBundle struct {
+	tw       *tar.Writer
+	dir      string
+	manifest bundleManifest
+	// secrets holds redacted values so they can also be scrubbed from logs
+	secrets map[string]bool
+}
+
+// writeBundle writes a support bundle for the project in the working directory
+// to dest and returns the items that could not be collected
+func writeBundle(ctx context.Context, env *doctorEnv, dest string, results []CheckResult, logLines int) (missing []string, err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	gz := gzip.NewWriter(f)
+	now := time.Now().UTC()
+	b := &supportBundle{
+		tw:       tar.NewWriter(gz),
+		dir:      bundleDirPrefix + now.Format("20060102-150405"),
+		manifest: bundleManifest{CreatedAt: now},
+		secrets:  make(map[string]bool),
+	}
+
+	b.collect(ctx, env, results, logLines)
+	if err := b.addJSON(BundleManifestFile, b.manifest); err != nil {
+		return nil, err
+	}
+	if err := b.tw.Close(); err != nil {
+		return nil, err
+	}
+	return b.manifest.Errors, gz.Close()
+}
+
+// collect gathers every section. Configuration goes first so the secrets it
+// holds are known before logs are added.
+func (b *supportBundle) collect(ctx context.Context, env *doctorEnv, results []CheckResult, logLines int) {
+	content, err := b.marshalJSON(version.GetBuildInfo())
+	b.add(BundleBuildInfoFile, content, err)
+	content, err = b.marshalJSON(results)
+	b.add(BundleDoctorFile, content, err)
+
+	cfg, configs, cfgErr := env.config()
+	if cfgErr == nil {
+		b.manifest.Project = cfg.Project.Name
+		content, err = b.resolvedConfig(cfg, configs)
+		b.add(BundleConfigFile, content, err)
+	} else {
+		b.fail(BundleConfigFile, cfgErr)
+	}
+	content, err = b.readFile(docker.DockerComposeFilePath, b.redactYAML)
+	b.add(BundleComposeFile, content, err)
+	content, err = b.readFile(core.EnvGeneratedFilePath, b.redactEnv)
+	b.add(BundleEnvFile, content, err)
+	if env.sharedRoot != "" {
+		content, err = b.readFile(filepath.Join(env.sharedRoot, core.SharedRegistryFile), b.redactYAML)
+		b.add(BundleRegistryFile, content, err)
+	} else {
+		b.fail(BundleRegistryFile, os.ErrNotExist)
+	}
+
+	client, dockerErr := env.docker(ctx)
+	if dockerErr == nil {
+		content, err = b.dockerSection(env)
+		b.add(BundleDockerFile, content, err)
+	} else {
+		b.fail(BundleDockerFile, dockerErr)
+	}
+
+	// Status and logs cover the services the configuration names
+	if cfgErr != nil {
+		b.fail(BundleStatusFile, cfgErr)
+		b.fail(BundleLogsDir, cfgErr)
+		return
+	}
+	if dockerErr == nil {
+		content, err = b.status(ctx, client, cfg.Project.Name, configs)
+		b.add(BundleStatusFile, content, err)
+	} else {
+		b.fail(BundleStatusFile, dockerErr)
+	}
+	for _, svc := range configs {
+		content, err = b.serviceLogs(ctx, env, client, svc.Name, logLines)
+		b.add(path.Join(BundleLogsDir, svc.Name+logrecorder.LogFileExt), content, err)
+	}
+}
+
+// add writes the content of name to the bundle, or records why it is missing
+func (b *supportBundle) add(name string, content []byte, err error) {
+	if err != nil {
+		b.fail(name, err)
+		return
+	}
+	if err := b.write(name, content); err != nil {
+		b.fail(name, err)
+	}
+}
+
+func (b *supportBundle) fail(name string, err error) {
+	b.manifest.Errors = append(b.manifest.Errors, fmt.Sprintf("%s: %v", name, err))
+}
+
+func (b *supportBundle) write(name string, content []byte) error {
+	header := &tar.Header{
+		Name:    path.Join(b.dir, name),
+		Mode:    core.PermReadWrite,
+		Size:    int64(len(content)),
+		ModTime: b.manifest.CreatedAt,
+	}
+	if err := b.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := b.tw.Write(content); err != nil {
+		return err
+	}
+	if name != BundleManifestFile {
+		b.manifest.Files = append(b.manifest.Files, name)
+	}
+	return nil
+}
+
+func (b *supportBundle) addJSON(name string, v any) error {
+	content, err := b.marshalJSON(v)
+	if err != nil {
+		return err
+	}
+	return b.write(name, content)
+}
+
+func (b *supportBundle) marshalJSON(v any) ([]byte, error) {
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// readFile returns the file at path, passed through redact when given
+func (b *supportBundle) readFile(path string, redact func([]byte) ([]byte, error)) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || redact == nil {
+		return data, err
+	}
+	return redact(data)
+}
+
+// resolvedConfig returns the merged project configuration and the resolved
+// service definitions, redacted
+func (b *supportBundle) resolvedConfig(cfg *config.Config, configs []types.ServiceConfig) ([]byte, error) {
+	data, err := yaml.Marshal(struct {
+		Config   *config.Config        `yaml:"config"`
+		Services []types.ServiceConfig `yaml:"services"`
+	}{cfg, configs})
+	if err != nil {
+		return nil, err
+	}
+	return b.redactYAML(data)
+}
+
+func (b *supportBundle) dockerSection(env *doctorEnv) ([]byte, error) {
+	section := bundleDocker{ServerVersion: env.dockerInfo.ServerVersion, Info: env.dockerInfo}
+	if raw, err := composeVersion(); err == nil {
+		section.ComposeVersion = raw
+	}
+	return b.marshalJSON(section)
+}
+
+// status returns the same document as "status --format json"
+func (b *supportBundle) status(ctx context.Context, client *docker.Client, project string, configs []types.ServiceConfig) ([]byte, error) {
+	statuses, err := client.GetServiceStatus(ctx, project, filterInitContainerNames(configs))
+	if err != nil {
+		return nil, err
+	}
+	output := ci.StatusOutput{Services: make([]any, len(statuses)), Count: len(statuses)}
+	for i, s := range statuses {
+		output.Services[i] = s
+	}
+	return b.marshalJSON(output)
+}
+
+// serviceLogs returns the last lines of each container of service, falling
+// back to the recorded log file when no container exists
+func (b *supportBundle) serviceLogs(ctx context.Context, env *doctorEnv, client *docker.Client, service string, lines int) ([]byte, error) {
+	var containers []docker.ContainerInfo
+	if client != nil {
+		var err error
+		if containers, err = env.serviceContainers(ctx, service); err != nil {
+			return nil, err
+		}
+	}
+
+	var buf bytes.Buffer
+	if len(containers) == 0 {
+		recorded, err := logrecorder.Tail(filepath.Join(logrecorder.Dir(), service+logrecorder.LogFileExt), lines)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(recorded + "\n")
+	}
+	for _, c := range containers {
+		if len(containers) > 1 {
+			fmt.Fprintf(&buf, "==> %s <==\n", c.Name)
+		}
+		if err := containerLogTail(ctx, client, c.ID, lines, &buf); err != nil {
+			return nil, err
+		}
+	}
+	return b.redactSecrets(buf.Bytes()), nil
+}
+
+func containerLogTail(ctx context.Context, client *docker.Client, id string, lines int, buf *bytes.Buffer) error {
+	reader, err := client.GetCli().ContainerLogs(ctx, id, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       strconv.Itoa(lines),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	_, err = stdcopy.StdCopy(buf, buf, reader)
+	return err
+}
+
+// redactYAML replaces the values of sensitive keys, and sensitive KEY=VALUE
+// list entries such as compose environment lists
+func (b *supportBundle) redactYAML(data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	b.redactNode(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+func (b *supportBundle) redactNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if value.Kind == yaml.ScalarNode && sensitiveKey.MatchString(key.Value) {
+				b.redactScalar(value)
+				continue
+			}
+			b.redactNode(value)
+		}
+	case yaml.ScalarNode:
+		if key, value, ok := strings.Cut(node.Value, "="); ok && sensitiveKey.MatchString(key) {
+			b.remember(value)
+			node.Value = key + "=" + RedactedValue
+		}
+	default:
+		for _, child := range node.Content {
+			b.redactNode(child)
+		}
+	}
+}
+
+func (b *supportBundle) redactScalar(node *yaml.Node) {
+	if node.Value == "" {
+		return
+	}
+	b.remember(node.Value)
+	node.Value, node.Tag, node.Style = RedactedValue, "!!str", 0
+}
+
+// redactEnv replaces the values of sensitive variables in an env file
+func (b *supportBundle) redactEnv(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, value, ok := strings.Cut(line, "="); ok && !strings.HasPrefix(strings.TrimSpace(key), "#") && sensitiveKey.MatchString(key) {
+			b.remember(strings.Trim(value, `"'`))
+			line = key + "=" + RedactedValue
+		}
+		out.WriteString(line + "\n")
+	}
+	return out.Bytes(), scanner.Err()
+}
+
+func (b *supportBundle) remember(secret string) {
+	// Unexpanded references such as ${DB_PASSWORD} are not secrets themselves
+	if len(secret) >= minRedactedSecretLength && !strings.HasPrefix(secret, "${") {
+		b.secrets[secret] = true
+	}
+}
+
+// redactSecrets scrubs every secret seen so far from free-form text. Longer
+// secrets go first so one containing another is replaced whole.
+func (b *supportBundle) redactSecrets(data []byte) []byte {
+	secrets := make([]string, 0, len(b.secrets))
+	for secret := range b.secrets {
+		secrets = append(secrets, secret)
+	}
+	sort.Slice(secrets, func(i, j int) bool { return len(secrets[i]) > len(secrets[j]) })
+	for _, secret := range secrets {
+		data = bytes.ReplaceAll(data, []byte(secret), []byte(RedactedValue))
+	}
+	return data
+}
+
+// filterInitContainerNames returns the services that run as long-lived
+// containers, leaving out one-shot init containers
+func filterInitContainerNames(configs []types.ServiceConfig) []string {
+	names := make([]string, 0, len(configs))
+	for _, svc := range configs {
+		if svc.Container.Restart != types.RestartPolicyNo {
+			names = append(names, svc.Name)
+		}
+	}
+	return names
+}
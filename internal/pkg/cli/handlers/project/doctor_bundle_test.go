@@ -0,0 +1,197 @@
+//go:build unit
+
+package project
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+func newTestBundle() *supportBundle {
+	return &supportBundle{secrets: make(map[string]bool)}
+}
+
+func TestRedactYAML(t *testing.T) {
+	b := newTestBundle()
+	out, err := b.redactYAML([]byte(`services:
+  postgres:
+    environment:
+      POSTGRES_USER: app
+      POSTGRES_PASSWORD: hunter22
+  api:
+    environment:
+      - API_TOKEN=abcd1234
+      - LOG_LEVEL=debug
+      - DB_PASSWORD=${DB_PASSWORD}
+`))
+	require.NoError(t, err)
+
+	text := string(out)
+	assert.Contains(t, text, "POSTGRES_USER: app")
+	assert.Contains(t, text, "POSTGRES_PASSWORD: '"+RedactedValue+"'")
+	assert.Contains(t, text, "API_TOKEN="+RedactedValue)
+	assert.Contains(t, text, "LOG_LEVEL=debug")
+	assert.NotContains(t, text, "hunter22")
+	assert.NotContains(t, text, "abcd1234")
+	assert.True(t, b.secrets["hunter22"])
+	assert.False(t, b.secrets["${DB_PASSWORD}"], "references are not secrets")
+}
+
+func TestRedactEnv(t *testing.T) {
+	b := newTestBundle()
+	out, err := b.redactEnv([]byte("# POSTGRES_PASSWORD=example\nPOSTGRES_PASSWORD=\"hunter22\"\nPOSTGRES_PORT=5432\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "# POSTGRES_PASSWORD=example\nPOSTGRES_PASSWORD="+RedactedValue+"\nPOSTGRES_PORT=5432\n", string(out))
+	assert.True(t, b.secrets["hunter22"])
+}
+
+func TestRedactSecrets(t *testing.T) {
+	b := newTestBundle()
+	b.remember("hunter22")
+	b.remember("hunter22-admin")
+	b.remember("abc") // too short to scrub safely
+
+	out := b.redactSecrets([]byte("login hunter22-admin then hunter22 with abc"))
+	assert.Equal(t, "login "+RedactedValue+" then "+RedactedValue+" with abc", string(out))
+}
+
+func TestWriteBundle(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.MkdirAll(filepath.Join(core.OttoStackDir, core.GeneratedDir), core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(core.EnvGeneratedFilePath, []byte("POSTGRES_PASSWORD=hunter22\n"), core.PermReadWrite))
+	require.NoError(t, os.WriteFile(docker.DockerComposeFilePath, []byte("services:\n  postgres:\n    image: postgres:16\n"), core.PermReadWrite))
+
+	var logs bytes.Buffer
+	_, err := stdcopy.NewStdWriter(&logs, stdcopy.Stdout).Write([]byte("connected with password hunter22\n"))
+	require.NoError(t, err)
+
+	var tail string
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(context.Context, container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{{ID: "1", Names: []string{"/app-postgres-1"}, State: docker.StateRunning,
+				Labels: map[string]string{docker.ComposeServiceLabel: "postgres"}}}, nil
+		},
+		ContainerInspectFunc: func(context.Context, string) (container.InspectResponse, error) {
+			return container.InspectResponse{}, assert.AnError
+		},
+		ContainerLogsFunc: func(_ context.Context, _ string, options container.LogsOptions) (io.ReadCloser, error) {
+			tail = options.Tail
+			return io.NopCloser(bytes.NewReader(logs.Bytes())), nil
+		},
+	}
+	env := newTestDoctorEnv(mock, system.Info{ServerVersion: "28.5.2"}, serviceWith("postgres", types.ContainerSpec{
+		Image:       "postgres:16",
+		Environment: map[string]string{"POSTGRES_PASSWORD": "hunter22"},
+	}))
+	results := []CheckResult{{Name: CheckIDDocker, Severity: SeverityError, Passed: true}}
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	missing, err := writeBundle(context.Background(), env, dest, results, 50)
+	require.NoError(t, err)
+	assert.Equal(t, "50", tail)
+	// No shared root in this env, so the registry is reported missing
+	require.Len(t, missing, 1)
+	assert.Contains(t, missing[0], BundleRegistryFile)
+
+	files := readBundle(t, dest)
+	for _, name := range []string{
+		BundleManifestFile, BundleConfigFile, BundleComposeFile, BundleEnvFile, BundleStatusFile,
+		BundleDoctorFile, BundleDockerFile, BundleBuildInfoFile, path.Join(BundleLogsDir, "postgres.log"),
+	} {
+		assert.Contains(t, files, name)
+	}
+	for name, content := range files {
+		assert.NotContains(t, content, "hunter22", name)
+	}
+	assert.Contains(t, files[path.Join(BundleLogsDir, "postgres.log")], "connected with password "+RedactedValue)
+	assert.Contains(t, files[BundleDockerFile], "28.5.2")
+
+	var manifest bundleManifest
+	require.NoError(t, json.Unmarshal([]byte(files[BundleManifestFile]), &manifest))
+	assert.Equal(t, "app", manifest.Project)
+	assert.Equal(t, missing, manifest.Errors)
+}
+
+func TestWriteBundle_ReportsSectionsMissingWithoutConfig(t *testing.T) {
+	t.Chdir(t.TempDir())
+	env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, system.Info{})
+	env.cfg, env.cfgErr = nil, assert.AnError
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	missing, err := writeBundle(context.Background(), env, dest, nil, 10)
+	require.NoError(t, err)
+
+	joined := strings.Join(missing, "\n")
+	for _, name := range []string{BundleConfigFile, BundleStatusFile, BundleLogsDir} {
+		assert.Contains(t, joined, name+": "+assert.AnError.Error())
+	}
+}
+
+func TestWriteBundle_RedactsRegistry(t *testing.T) {
+	t.Chdir(t.TempDir())
+	sharedRoot := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sharedRoot, core.SharedRegistryFile), []byte(`credentials:
+  postgres:
+    shop:
+      database: shop
+      user: shop
+      password: 5f1e2d3c4b5a69788796a5b4
+`), core.PermPrivate))
+
+	env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, system.Info{})
+	env.sharedRoot = sharedRoot
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err := writeBundle(context.Background(), env, dest, nil, 10)
+	require.NoError(t, err)
+
+	registryFile := readBundle(t, dest)[BundleRegistryFile]
+	assert.Contains(t, registryFile, "database: shop")
+	assert.Contains(t, registryFile, RedactedValue)
+	assert.NotContains(t, registryFile, "5f1e2d3c4b5a69788796a5b4")
+}
+
+// readBundle returns the files of a bundle keyed by their path below its top-level directory
+func readBundle(t *testing.T, file string) map[string]string {
+	t.Helper()
+	f, err := os.Open(file)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		dir, name, _ := strings.Cut(header.Name, "/")
+		assert.True(t, strings.HasPrefix(dir, bundleDirPrefix))
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[name] = string(content)
+	}
+	return files
+}
@@ -0,0 +1,494 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	units "github.com/docker/go-units"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/version"
+)
+
+// Check severities. Only a failed error check makes doctor exit non-zero.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Check IDs, as reported in the name field of JSON output
+const (
+	CheckIDDocker              = "docker"
+	CheckIDDockerCompose       = "docker-compose"
+	CheckIDComposeVersion      = "compose-version"
+	CheckIDProjectInit         = "project-init"
+	CheckIDConfiguration       = "configuration"
+	CheckIDGeneratedFiles      = "generated-files"
+	CheckIDPorts               = "ports"
+	CheckIDDiskSpace           = "disk-space"
+	CheckIDMemory              = "memory"
+	CheckIDImages              = "images"
+	CheckIDRegistry            = "registry"
+	CheckIDZombieContainers    = "zombie-containers"
+	CheckIDNetwork             = "network"
+	CheckIDUnhealthyContainers = "unhealthy-containers"
+)
+
+const (
+	// MinComposeVersion is the oldest Docker Compose release otto-stack is tested against
+	MinComposeVersion = "2.20.0"
+	// minFreeDiskBytes is the free space below which the disk check warns
+	minFreeDiskBytes = 5 * units.GiB
+	dockerGetURL     = "https://docs.docker.com/get-docker/"
+)
+
+// DoctorCheck is one diagnostic run by the doctor command
+type DoctorCheck struct {
+	ID       string
+	Severity string
+	// Remediation is shown when the check fails, unless the outcome sets its own
+	Remediation string
+	// PerService checks are scoped to the services named on the command line.
+	// The others only run when doctor is called without arguments.
+	PerService bool
+	Run        func(ctx context.Context, env *doctorEnv) checkOutcome
+	// Fix repairs a failed check for doctor --fix and returns what it changed.
+	// FixPrompt describes it when asking for confirmation.
+	FixPrompt string
+	Fix       func(ctx context.Context, env *doctorEnv) ([]string, error)
+}
+
+// checkOutcome is what a check reports back to the runner
+type checkOutcome struct {
+	Passed      bool
+	Skipped     bool
+	Message     string
+	Details     []string
+	Remediation string
+}
+
+// DoctorChecks lists every check in the order doctor runs them
+var DoctorChecks = []DoctorCheck{
+	{ID: CheckIDDocker, Severity: SeverityError, Remediation: messages.DoctorDockerStartHelp, Run: checkDocker},
+	{ID: CheckIDDockerCompose, Severity: SeverityError, Remediation: messages.DoctorDockerComposeUpdate, Run: checkDockerCompose},
+	{ID: CheckIDComposeVersion, Severity: SeverityWarning, Remediation: messages.DoctorDockerComposeUpdate, Run: checkComposeVersion},
+	{ID: CheckIDProjectInit, Severity: SeverityError, Remediation: fmt.Sprintf(messages.DoctorRunInitHelp, "otto-stack init"), Run: checkProjectInit},
+	{ID: CheckIDConfiguration, Severity: SeverityError, Run: checkConfiguration},
+	{ID: CheckIDGeneratedFiles, Severity: SeverityWarning, Remediation: messages.DoctorGeneratedHelp, Run: checkGeneratedFiles,
+		FixPrompt: messages.DoctorFixGenerated, Fix: fixGeneratedFiles},
+	{ID: CheckIDDiskSpace, Severity: SeverityWarning, Remediation: messages.DoctorDiskHelp, Run: checkDiskSpace},
+	{ID: CheckIDRegistry, Severity: SeverityWarning, Remediation: messages.DoctorRegistryHelp, Run: checkRegistry,
+		FixPrompt: messages.DoctorFixRegistry, Fix: fixRegistry},
+	{ID: CheckIDZombieContainers, Severity: SeverityWarning, Remediation: messages.DoctorZombiesHelp, Run: checkZombieContainers,
+		FixPrompt: messages.DoctorFixZombies, Fix: fixZombieContainers},
+	{ID: CheckIDNetwork, Severity: SeverityWarning, Remediation: messages.DoctorNetworkHelp, Run: checkNetwork,
+		FixPrompt: messages.DoctorFixNetwork, Fix: fixNetwork},
+	{ID: CheckIDPorts, Severity: SeverityError, Remediation: messages.DoctorPortsHelp, PerService: true, Run: checkPorts,
+		FixPrompt: messages.DoctorFixPorts, Fix: fixPorts},
+	{ID: CheckIDMemory, Severity: SeverityWarning, Remediation: messages.DoctorMemoryHelp, PerService: true, Run: checkMemory},
+	{ID: CheckIDImages, Severity: SeverityInfo, Remediation: messages.DoctorImagesHelp, PerService: true, Run: checkImages},
+	{ID: CheckIDUnhealthyContainers, Severity: SeverityError, Remediation: messages.DoctorContainersHelp, PerService: true, Run: checkUnhealthyContainers},
+}
+
+func passed(message string, args ...any) checkOutcome {
+	return checkOutcome{Passed: true, Message: fmt.Sprintf(message, args...)}
+}
+
+func failed(message string, args ...any) checkOutcome {
+	return checkOutcome{Message: fmt.Sprintf(message, args...)}
+}
+
+func skipped(message string, args ...any) checkOutcome {
+	return checkOutcome{Skipped: true, Message: fmt.Sprintf(message, args...)}
+}
+
+func checkDocker(ctx context.Context, env *doctorEnv) checkOutcome {
+	if !isCommandAvailable(docker.DockerCmd) {
+		outcome := failed(messages.DoctorDockerNotFound)
+		outcome.Remediation = fmt.Sprintf(messages.DoctorDockerInstallHelp, dockerGetURL)
+		return outcome
+	}
+	if _, err := env.docker(ctx); err != nil {
+		return failed(messages.DoctorDockerDaemonNotRunning)
+	}
+	return passed(messages.DoctorDockerAvailable)
+}
+
+func checkDockerCompose(_ context.Context, _ *doctorEnv) checkOutcome {
+	if hasDockerComposePlugin() {
+		return passed(messages.DoctorDockerComposeAvailable)
+	}
+	return failed(messages.DoctorDockerComposeNotFound)
+}
+
+func checkComposeVersion(_ context.Context, _ *doctorEnv) checkOutcome {
+	raw, err := composeVersion()
+	if err != nil {
+		return skipped(messages.DoctorComposeVersionUnknown)
+	}
+	return compareComposeVersion(raw)
+}
+
+// composeVersion returns the output of "docker compose version --short"
+func composeVersion() (string, error) {
+	out, err := exec.Command(docker.DockerCmd, docker.DockerComposeCmd, docker.DockerVersionCmd, "--short").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// compareComposeVersion checks a "docker compose version --short" string against MinComposeVersion
+func compareComposeVersion(raw string) checkOutcome {
+	current, err := version.ParseVersion(raw)
+	if err != nil {
+		return skipped(messages.DoctorComposeVersionUnknown)
+	}
+	minimum, err := version.ParseVersion(MinComposeVersion)
+	if err != nil {
+		return skipped(messages.DoctorComposeVersionUnknown)
+	}
+	// Docker Desktop ships builds such as 2.29.1-desktop.1, which are releases
+	// rather than pre-releases of 2.29.1
+	current.PreRelease = ""
+	if current.Compare(*minimum) < 0 {
+		return failed(messages.DoctorComposeVersionOld, raw, MinComposeVersion)
+	}
+	return passed(messages.DoctorComposeVersionOk, raw, MinComposeVersion)
+}
+
+func checkProjectInit(_ context.Context, _ *doctorEnv) checkOutcome {
+	if _, err := os.Stat(core.OttoStackDir); os.IsNotExist(err) {
+		return failed(messages.DoctorProjectNotInitialized)
+	}
+	return passed(messages.DoctorProjectInitialized)
+}
+
+func checkConfiguration(_ context.Context, env *doctorEnv) checkOutcome {
+	configPath := filepath.Join(core.OttoStackDir, core.ConfigFileName)
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		outcome := failed(messages.DoctorConfigDirMissing)
+		outcome.Remediation = fmt.Sprintf(messages.DoctorRunInitHelp, "otto-stack init")
+		return outcome
+	}
+	if _, _, err := env.config(); err != nil {
+		outcome := failed(messages.DoctorConfigLoadFailed, err)
+		outcome.Remediation = fmt.Sprintf(messages.DoctorConfigFixHelp, configPath)
+		return outcome
+	}
+	return passed(messages.DoctorConfigValid)
+}
+
+func checkDiskSpace(ctx context.Context, env *doctorEnv) checkOutcome {
+	if _, err := env.docker(ctx); err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDDiskSpace)
+	}
+	root := env.dockerInfo.DockerRootDir
+	free, ok := freeDiskBytes(root)
+	if !ok {
+		// Docker Desktop keeps its data inside a VM, out of reach of the host
+		return skipped(messages.DoctorDiskUnavailable, CheckIDDiskSpace, root)
+	}
+	return compareDiskSpace(free, root)
+}
+
+func compareDiskSpace(free uint64, root string) checkOutcome {
+	if free < minFreeDiskBytes {
+		return failed(messages.DoctorDiskLow, units.BytesSize(float64(free)), root, units.BytesSize(minFreeDiskBytes))
+	}
+	return passed(messages.DoctorDiskOk, units.BytesSize(float64(free)), root)
+}
+
+func checkRegistry(ctx context.Context, env *doctorEnv) checkOutcome {
+	if env.sharedRoot == "" {
+		return skipped(messages.DoctorRegistryUnavailable, CheckIDRegistry)
+	}
+	if _, err := os.Stat(filepath.Join(env.sharedRoot, core.SharedRegistryFile)); os.IsNotExist(err) {
+		return skipped(messages.DoctorRegistryUnavailable, CheckIDRegistry)
+	}
+	client, err := env.docker(ctx)
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDRegistry)
+	}
+
+	warnings := registry.NewManager(env.sharedRoot).ValidateAgainstDocker(ctx, client)
+	if len(warnings) == 0 {
+		return passed(messages.DoctorRegistryConsistent)
+	}
+	outcome := failed(messages.DoctorRegistryInconsistent, len(warnings))
+	outcome.Details = warnings
+	return outcome
+}
+
+func checkPorts(ctx context.Context, env *doctorEnv) checkOutcome {
+	if _, _, err := env.config(); err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDPorts)
+	}
+
+	busy := env.busyPorts(ctx)
+	if len(busy) == 0 {
+		return passed(messages.DoctorPortsAvailable)
+	}
+	outcome := failed(messages.DoctorPortsBusy, len(busy))
+	for _, b := range busy {
+		if b.holder != "" {
+			outcome.Details = append(outcome.Details, fmt.Sprintf(messages.DoctorPortHeld, b.port, b.service, b.holder))
+		} else {
+			outcome.Details = append(outcome.Details, fmt.Sprintf(messages.DoctorPortBusy, b.port, b.service))
+		}
+	}
+	return outcome
+}
+
+func checkGeneratedFiles(_ context.Context, env *doctorEnv) checkOutcome {
+	if _, _, err := env.config(); err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDGeneratedFiles)
+	}
+
+	newest, source := newestConfigSource()
+	for _, path := range []string{docker.DockerComposeFilePath, core.EnvGeneratedFilePath} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return failed(messages.DoctorGeneratedMissing, path)
+		}
+		if info.ModTime().Before(newest) {
+			return failed(messages.DoctorGeneratedStale, source)
+		}
+	}
+	return passed(messages.DoctorGeneratedCurrent)
+}
+
+// newestConfigSource returns the modification time and path of the most
+// recently changed file the generated files are built from
+func newestConfigSource() (time.Time, string) {
+	sources := []string{
+		filepath.Join(core.OttoStackDir, core.ConfigFileName),
+		filepath.Join(core.OttoStackDir, core.LocalConfigFileName),
+	}
+	serviceFiles, _ := filepath.Glob(filepath.Join(core.OttoStackDir, core.ServiceConfigsDir, "*.yml"))
+	sources = append(sources, serviceFiles...)
+
+	var newest time.Time
+	var newestPath string
+	for _, path := range sources {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(newest) {
+			newest, newestPath = info.ModTime(), path
+		}
+	}
+	return newest, newestPath
+}
+
+func checkZombieContainers(ctx context.Context, env *doctorEnv) checkOutcome {
+	if env.sharedRoot == "" {
+		return skipped(messages.DoctorRegistryUnavailable, CheckIDZombieContainers)
+	}
+	if _, err := env.docker(ctx); err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDZombieContainers)
+	}
+
+	zombies, err := env.zombieContainers(ctx)
+	if err != nil {
+		return failed(messages.DoctorContainersListFailed, err)
+	}
+	if len(zombies) == 0 {
+		return passed(messages.DoctorZombiesNone)
+	}
+	outcome := failed(messages.DoctorZombiesFound, len(zombies))
+	for _, z := range zombies {
+		outcome.Details = append(outcome.Details, fmt.Sprintf(messages.DoctorZombie, z.Container, z.Reason))
+	}
+	return outcome
+}
+
+func checkNetwork(ctx context.Context, env *doctorEnv) checkOutcome {
+	cfg, _, err := env.config()
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDNetwork)
+	}
+	client, err := env.docker(ctx)
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDNetwork)
+	}
+
+	name := projectNetworkName(cfg.Project.Name)
+	containers, err := env.projectServiceContainers(ctx)
+	if err != nil {
+		return failed(messages.DoctorContainersListFailed, err)
+	}
+	if len(containers) == 0 {
+		// Compose creates the network on the next up
+		return skipped(messages.DoctorNetworkUnused, CheckIDNetwork, name)
+	}
+
+	exists, err := networkExists(ctx, client, name)
+	if err != nil {
+		return failed(messages.DoctorContainersListFailed, err)
+	}
+	if !exists {
+		return failed(messages.DoctorNetworkMissing, name)
+	}
+	return passed(messages.DoctorNetworkOk, name)
+}
+
+func projectNetworkName(project string) string {
+	return project + docker.NetworkNameSuffix
+}
+
+func networkExists(ctx context.Context, client *docker.Client, name string) (bool, error) {
+	names, err := docker.NewResourceManager(client).List(ctx, docker.ResourceNetwork, filters.NewArgs(filters.Arg("name", name)))
+	if err != nil {
+		return false, err
+	}
+	// The name filter matches substrings
+	return slices.Contains(names, name), nil
+}
+
+func checkMemory(ctx context.Context, env *doctorEnv) checkOutcome {
+	_, configs, err := env.config()
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDMemory)
+	}
+	if _, err := env.docker(ctx); err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDMemory)
+	}
+
+	var total int64
+	var details []string
+	for _, svc := range configs {
+		if svc.Container.MemoryLimit == "" {
+			continue
+		}
+		limit, err := units.RAMInBytes(svc.Container.MemoryLimit)
+		if err != nil {
+			details = append(details, fmt.Sprintf(messages.DoctorMemoryInvalid, svc.Name, svc.Container.MemoryLimit))
+			continue
+		}
+		total += limit
+		details = append(details, fmt.Sprintf(messages.DoctorMemoryLimit, svc.Name, svc.Container.MemoryLimit))
+	}
+
+	if len(details) == 0 {
+		return passed(messages.DoctorMemoryNoLimits)
+	}
+	available := env.dockerInfo.MemTotal
+	if total > available {
+		outcome := failed(messages.DoctorMemoryExceeded, units.BytesSize(float64(total)), units.BytesSize(float64(available)))
+		outcome.Details = details
+		return outcome
+	}
+	return passed(messages.DoctorMemoryOk, units.BytesSize(float64(total)), units.BytesSize(float64(available)))
+}
+
+func checkImages(ctx context.Context, env *doctorEnv) checkOutcome {
+	_, configs, err := env.config()
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDImages)
+	}
+	client, err := env.docker(ctx)
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDImages)
+	}
+
+	images, err := client.GetCli().ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return failed(messages.DoctorImagesListFailed, err)
+	}
+	present := make(map[string]bool)
+	for _, img := range images {
+		for _, tag := range img.RepoTags {
+			present[normalizeImageRef(tag)] = true
+		}
+	}
+
+	var missing []string
+	for _, svc := range configs {
+		ref := svc.Container.Image
+		if ref == "" || present[normalizeImageRef(ref)] {
+			continue
+		}
+		missing = append(missing, fmt.Sprintf(messages.DoctorImageMissing, ref, svc.Name))
+	}
+
+	if len(missing) == 0 {
+		return passed(messages.DoctorImagesPresent)
+	}
+	outcome := failed(messages.DoctorImagesMissing, len(missing))
+	outcome.Details = missing
+	return outcome
+}
+
+// normalizeImageRef reduces an image reference to the form Docker lists it in:
+// Docker Hub library images without a registry prefix and an explicit tag
+func normalizeImageRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "docker.io/")
+	ref = strings.TrimPrefix(ref, "library/")
+	if strings.Contains(ref, "@") {
+		return ref
+	}
+	if i := strings.LastIndex(ref, ":"); i < 0 || strings.Contains(ref[i:], "/") {
+		ref += ":latest"
+	}
+	return ref
+}
+
+func checkUnhealthyContainers(ctx context.Context, env *doctorEnv) checkOutcome {
+	_, configs, err := env.config()
+	if err != nil {
+		return skipped(messages.DoctorSkippedNoConfig, CheckIDUnhealthyContainers)
+	}
+	if _, err := env.docker(ctx); err != nil {
+		return skipped(messages.DoctorSkippedNoDocker, CheckIDUnhealthyContainers)
+	}
+
+	var unhealthy []string
+	for _, svc := range configs {
+		containers, err := env.serviceContainers(ctx, svc.Name)
+		if err != nil {
+			return failed(messages.DoctorContainersListFailed, err)
+		}
+		for _, c := range containers {
+			if isUnhealthy(c) {
+				unhealthy = append(unhealthy, fmt.Sprintf(messages.DoctorContainerUnhealthy, c.Name, svc.Name, c.Status))
+			}
+		}
+	}
+
+	if len(unhealthy) == 0 {
+		return passed(messages.DoctorContainersHealthy)
+	}
+	outcome := failed(messages.DoctorContainersUnhealthy, len(unhealthy))
+	outcome.Details = unhealthy
+	return outcome
+}
+
+// isUnhealthy reports containers failing their health check, stuck restarting,
+// dead, or exited with a non-zero code
+func isUnhealthy(c docker.ContainerInfo) bool {
+	switch c.State {
+	case docker.StateRestarting, docker.StateDead:
+		return true
+	case docker.StateStopped:
+		var code int
+		_, err := fmt.Sscanf(c.Status, "Exited (%d)", &code)
+		return err == nil && code != 0
+	}
+	return strings.Contains(c.Status, "("+docker.HealthUnhealthy+")")
+}
+
+// hasDockerComposePlugin checks if the Docker Compose plugin is available by
+// running "docker compose version". This is distinct from Docker daemon health.
+func hasDockerComposePlugin() bool {
+	return exec.Command(docker.DockerCmd, docker.DockerComposeCmd, docker.DockerVersionCmd).Run() == nil
+}
@@ -0,0 +1,177 @@
+//go:build unit
+
+package project
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/system"
+	units "github.com/docker/go-units"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+// newTestDoctorEnv returns an env with the given services already resolved and
+// Docker backed by mock
+func newTestDoctorEnv(mock *testhelpers.MockDockerClient, info system.Info, configs ...types.ServiceConfig) *doctorEnv {
+	return &doctorEnv{
+		configLoaded: true,
+		cfg:          &config.Config{Project: config.ProjectConfig{Name: "app"}},
+		configs:      configs,
+		dockerLoaded: true,
+		dockerClient: docker.NewClientWithDependencies(mock, nil, nil),
+		dockerInfo:   info,
+	}
+}
+
+func serviceWith(name string, spec types.ContainerSpec) types.ServiceConfig {
+	return types.ServiceConfig{Name: name, Container: spec}
+}
+
+func TestDoctorChecks_RegistryIsWellFormed(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, check := range DoctorChecks {
+		assert.NotEmpty(t, check.ID)
+		assert.False(t, seen[check.ID], "duplicate check %s", check.ID)
+		seen[check.ID] = true
+		assert.Contains(t, []string{SeverityError, SeverityWarning, SeverityInfo}, check.Severity, check.ID)
+		assert.NotNil(t, check.Run, check.ID)
+	}
+}
+
+func TestCheckProjectInit(t *testing.T) {
+	t.Chdir(t.TempDir())
+	assert.False(t, checkProjectInit(context.Background(), nil).Passed)
+
+	require.NoError(t, os.Mkdir(core.OttoStackDir, core.PermReadWriteExec))
+	assert.True(t, checkProjectInit(context.Background(), nil).Passed)
+}
+
+func TestCheckConfiguration_MissingFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	outcome := checkConfiguration(context.Background(), newDoctorEnv(nil, ""))
+	assert.False(t, outcome.Passed)
+	assert.NotEmpty(t, outcome.Remediation)
+}
+
+func TestCompareComposeVersion(t *testing.T) {
+	assert.True(t, compareComposeVersion("2.29.1").Passed)
+	assert.True(t, compareComposeVersion("v2.20.0-desktop.1").Passed)
+	assert.False(t, compareComposeVersion("2.12.2").Passed)
+	assert.True(t, compareComposeVersion("garbage").Skipped)
+}
+
+func TestCompareDiskSpace(t *testing.T) {
+	assert.True(t, compareDiskSpace(minFreeDiskBytes, "/var/lib/docker").Passed)
+	assert.False(t, compareDiskSpace(units.GiB, "/var/lib/docker").Passed)
+}
+
+func TestNormalizeImageRef(t *testing.T) {
+	tests := map[string]string{
+		"postgres":                         "postgres:latest",
+		"postgres:16":                      "postgres:16",
+		"docker.io/library/redis:7":        "redis:7",
+		"localhost:5000/app":               "localhost:5000/app:latest",
+		"ghcr.io/org/app:1.0":              "ghcr.io/org/app:1.0",
+		"postgres@sha256:0123456789abcdef": "postgres@sha256:0123456789abcdef",
+	}
+	for ref, want := range tests {
+		assert.Equal(t, want, normalizeImageRef(ref), ref)
+	}
+}
+
+func TestIsUnhealthy(t *testing.T) {
+	assert.True(t, isUnhealthy(docker.ContainerInfo{State: docker.StateRunning, Status: "Up 5 minutes (unhealthy)"}))
+	assert.True(t, isUnhealthy(docker.ContainerInfo{State: docker.StateRestarting, Status: "Restarting (1) 3 seconds ago"}))
+	assert.True(t, isUnhealthy(docker.ContainerInfo{State: docker.StateStopped, Status: "Exited (137) 2 minutes ago"}))
+	assert.False(t, isUnhealthy(docker.ContainerInfo{State: docker.StateStopped, Status: "Exited (0) 2 minutes ago"}))
+	assert.False(t, isUnhealthy(docker.ContainerInfo{State: docker.StateRunning, Status: "Up 5 minutes (healthy)"}))
+}
+
+func TestCheckMemory(t *testing.T) {
+	info := system.Info{MemTotal: 2 * units.GiB}
+
+	t.Run("passes when limits fit", func(t *testing.T) {
+		env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, info,
+			serviceWith("postgres", types.ContainerSpec{MemoryLimit: "512m"}),
+			serviceWith("redis", types.ContainerSpec{MemoryLimit: "256m"}))
+		assert.True(t, checkMemory(context.Background(), env).Passed)
+	})
+
+	t.Run("fails when limits exceed Docker memory", func(t *testing.T) {
+		env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, info,
+			serviceWith("postgres", types.ContainerSpec{MemoryLimit: "2g"}),
+			serviceWith("kafka", types.ContainerSpec{MemoryLimit: "1g"}))
+		outcome := checkMemory(context.Background(), env)
+		assert.False(t, outcome.Passed)
+		assert.Len(t, outcome.Details, 2)
+	})
+
+	t.Run("passes without limits", func(t *testing.T) {
+		env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, info, serviceWith("redis", types.ContainerSpec{}))
+		assert.True(t, checkMemory(context.Background(), env).Passed)
+	})
+}
+
+func TestCheckImages(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		ImageListFunc: func(context.Context, image.ListOptions) ([]image.Summary, error) {
+			return []image.Summary{{RepoTags: []string{"postgres:16"}}}, nil
+		},
+	}
+	env := newTestDoctorEnv(mock, system.Info{},
+		serviceWith("postgres", types.ContainerSpec{Image: "docker.io/library/postgres:16"}),
+		serviceWith("redis", types.ContainerSpec{Image: "redis:7"}))
+
+	outcome := checkImages(context.Background(), env)
+	assert.False(t, outcome.Passed)
+	assert.Equal(t, []string{"redis:7 (redis)"}, outcome.Details)
+}
+
+func TestCheckUnhealthyContainers(t *testing.T) {
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(context.Context, container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "1", Names: []string{"/app-postgres-1"}, State: docker.StateRunning, Status: "Up (unhealthy)",
+					Labels: map[string]string{docker.ComposeServiceLabel: "postgres"}},
+				{ID: "2", Names: []string{"/app-redis-1"}, State: docker.StateRunning, Status: "Up (healthy)",
+					Labels: map[string]string{docker.ComposeServiceLabel: "redis"}},
+			}, nil
+		},
+	}
+
+	t.Run("reports unhealthy containers", func(t *testing.T) {
+		env := newTestDoctorEnv(mock, system.Info{}, serviceWith("postgres", types.ContainerSpec{}), serviceWith("redis", types.ContainerSpec{}))
+		outcome := checkUnhealthyContainers(context.Background(), env)
+		assert.False(t, outcome.Passed)
+		require.Len(t, outcome.Details, 1)
+		assert.Contains(t, outcome.Details[0], "app-postgres-1")
+	})
+
+	t.Run("only looks at services in scope", func(t *testing.T) {
+		env := newTestDoctorEnv(mock, system.Info{}, serviceWith("redis", types.ContainerSpec{}))
+		assert.True(t, checkUnhealthyContainers(context.Background(), env).Passed)
+	})
+}
+
+func TestChecks_SkipWithoutDocker(t *testing.T) {
+	env := &doctorEnv{
+		configLoaded: true,
+		cfg:          &config.Config{Project: config.ProjectConfig{Name: "app"}},
+		dockerLoaded: true,
+		dockerErr:    assert.AnError,
+	}
+	for _, check := range []func(context.Context, *doctorEnv) checkOutcome{checkMemory, checkImages, checkUnhealthyContainers, checkDiskSpace} {
+		assert.True(t, check(context.Background(), env).Skipped)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package project
+
+import "syscall"
+
+// freeDiskBytes returns the space available to unprivileged users on the
+// filesystem holding path. It reports false when path does not exist here.
+func freeDiskBytes(path string) (uint64, bool) {
+	var stat syscall.Statfs_t
+	if path == "" || syscall.Statfs(path, &stat) != nil {
+		return 0, false
+	}
+	return stat.Bavail * uint64(stat.Bsize), true
+}
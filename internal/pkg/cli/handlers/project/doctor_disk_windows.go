@@ -0,0 +1,8 @@
+//go:build windows
+
+package project
+
+// freeDiskBytes is not supported on Windows, where Docker keeps its data in a VM
+func freeDiskBytes(path string) (uint64, bool) {
+	return 0, false
+}
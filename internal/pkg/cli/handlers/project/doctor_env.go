@@ -0,0 +1,269 @@
+package project
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/system"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// doctorEnv gives checks lazy, shared access to the project configuration and
+// Docker, so each is loaded at most once however many checks need it.
+type doctorEnv struct {
+	// services named on the command line; empty means the whole stack
+	services   []string
+	sharedRoot string
+
+	configLoaded bool
+	cfg          *config.Config
+	cfgErr       error
+	configs      []types.ServiceConfig
+
+	dockerLoaded bool
+	dockerClient *docker.Client
+	dockerInfo   system.Info
+	dockerErr    error
+
+	projectLoaded     bool
+	projectContainers []docker.ContainerInfo
+	allLoaded         bool
+	allContainers     []docker.ContainerInfo
+}
+
+func newDoctorEnv(services []string, sharedRoot string) *doctorEnv {
+	return &doctorEnv{services: services, sharedRoot: sharedRoot}
+}
+
+// config loads the project configuration and resolves the services in scope
+func (e *doctorEnv) config() (*config.Config, []types.ServiceConfig, error) {
+	if e.configLoaded {
+		return e.cfg, e.configs, e.cfgErr
+	}
+	e.configLoaded = true
+
+	e.cfg, e.cfgErr = config.LoadConfig()
+	if e.cfgErr != nil {
+		return nil, nil, e.cfgErr
+	}
+
+	resolved, err := services.ResolveUpServices(e.services, e.cfg)
+	if err != nil {
+		e.cfgErr = err
+		return nil, nil, err
+	}
+	// Dependencies pulled in by the resolver are not part of a scoped run
+	for _, svc := range resolved {
+		if len(e.services) == 0 || slices.Contains(e.services, svc.Name) {
+			e.configs = append(e.configs, svc)
+		}
+	}
+	return e.cfg, e.configs, nil
+}
+
+// docker connects to the daemon and caches its system info
+func (e *doctorEnv) docker(ctx context.Context) (*docker.Client, error) {
+	if e.dockerLoaded {
+		return e.dockerClient, e.dockerErr
+	}
+	e.dockerLoaded = true
+
+	client, err := docker.NewClient(nil)
+	if err != nil {
+		e.dockerErr = err
+		return nil, err
+	}
+	info, err := client.GetCli().Info(ctx)
+	if err != nil {
+		_ = client.Close()
+		e.dockerErr = err
+		return nil, err
+	}
+	e.dockerClient, e.dockerInfo = client, info
+	return client, nil
+}
+
+// projectServiceContainers returns the containers of the project in the working directory
+func (e *doctorEnv) projectServiceContainers(ctx context.Context) ([]docker.ContainerInfo, error) {
+	if e.projectLoaded {
+		return e.projectContainers, nil
+	}
+	cfg, _, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	client, err := e.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	containers, err := client.ListContainers(ctx, cfg.Project.Name)
+	if err != nil {
+		return nil, err
+	}
+	e.projectLoaded, e.projectContainers = true, containers
+	return containers, nil
+}
+
+// sharedContainer returns the shared container of service, if one exists
+func (e *doctorEnv) sharedContainer(ctx context.Context, service string) (docker.ContainerInfo, bool) {
+	if !e.allLoaded {
+		client, err := e.docker(ctx)
+		if err != nil {
+			return docker.ContainerInfo{}, false
+		}
+		containers, err := client.ListContainers(ctx, "")
+		if err != nil {
+			return docker.ContainerInfo{}, false
+		}
+		e.allLoaded, e.allContainers = true, containers
+	}
+	for _, c := range e.allContainers {
+		if c.Name == docker.SharedContainerPrefix+service {
+			return c, true
+		}
+	}
+	return docker.ContainerInfo{}, false
+}
+
+// serviceContainers returns the containers backing service: its project
+// containers or, when it runs shared, the shared container
+func (e *doctorEnv) serviceContainers(ctx context.Context, service string) ([]docker.ContainerInfo, error) {
+	project, err := e.projectServiceContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []docker.ContainerInfo
+	for _, c := range project {
+		if c.Service == service {
+			result = append(result, c)
+		}
+	}
+	if len(result) == 0 {
+		if shared, ok := e.sharedContainer(ctx, service); ok {
+			result = append(result, shared)
+		}
+	}
+	return result, nil
+}
+
+// serviceRunning reports whether a container of service is running
+func (e *doctorEnv) serviceRunning(ctx context.Context, service string) bool {
+	containers, err := e.serviceContainers(ctx, service)
+	if err != nil {
+		return false
+	}
+	for _, c := range containers {
+		if c.State == docker.StateRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// busyPort is a host port a service needs that is already taken
+type busyPort struct {
+	port    int
+	service string
+	// holder names the paused otto-stack container holding the port, when
+	// that is what holds it
+	holder   string
+	holderID string
+}
+
+// busyPorts returns the host ports of the services in scope that are in use,
+// ignoring ports held by the service's own running container
+func (e *doctorEnv) busyPorts(ctx context.Context) []busyPort {
+	_, configs, err := e.config()
+	if err != nil {
+		return nil
+	}
+
+	holders := e.pausedPortHolders(ctx)
+	conflicts := NewConflictsHandler()
+	var busy []busyPort
+	for _, svc := range configs {
+		if e.serviceRunning(ctx, svc.Name) {
+			continue
+		}
+		for _, mapping := range svc.Container.Ports {
+			port := conflicts.parsePort(mapping.External)
+			if port <= 0 || !conflicts.isPortInUse(port) {
+				continue
+			}
+			b := busyPort{port: port, service: svc.Name}
+			if holder, ok := holders[port]; ok {
+				b.holder = strings.TrimPrefix(holder.Names[0], "/")
+				b.holderID = holder.ID
+			}
+			busy = append(busy, b)
+		}
+	}
+	return busy
+}
+
+// pausedPortHolders maps published host ports to the paused otto-stack
+// containers holding them. Docker releases the ports of exited containers, so
+// only paused ones keep a port from a service that is not running.
+func (e *doctorEnv) pausedPortHolders(ctx context.Context) map[int]container.Summary {
+	client, err := e.docker(ctx)
+	if err != nil {
+		return nil
+	}
+	containers, err := client.GetCli().ContainerList(ctx, container.ListOptions{All: true, Filters: docker.NewManagedFilter("")})
+	if err != nil {
+		return nil
+	}
+
+	holders := make(map[int]container.Summary)
+	for _, c := range containers {
+		if c.State != docker.StatePaused || len(c.Names) == 0 {
+			continue
+		}
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				holders[int(p.PublicPort)] = c
+			}
+		}
+	}
+	return holders
+}
+
+// zombieContainers returns shared containers that no existing project uses
+func (e *doctorEnv) zombieContainers(ctx context.Context) ([]registry.OrphanInfo, error) {
+	client, err := e.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans, err := registry.NewManager(e.sharedRoot).FindOrphansWithChecks(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var zombies []registry.OrphanInfo
+	for _, o := range orphans {
+		// Entries without a container are the registry check's concern
+		if o.Severity == registry.OrphanSeveritySafe && o.ContainerState != registry.ContainerStateNotFound {
+			zombies = append(zombies, o)
+		}
+	}
+	return zombies, nil
+}
+
+// refresh drops cached container lists so checks see the effect of fixes
+func (e *doctorEnv) refresh() {
+	e.projectLoaded, e.projectContainers = false, nil
+	e.allLoaded, e.allContainers = false, nil
+}
+
+func (e *doctorEnv) close() {
+	if e.dockerClient != nil {
+		_ = e.dockerClient.Close()
+	}
+}
@@ -0,0 +1,169 @@
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// fixReport records what doctor --fix did for one check
+type fixReport struct {
+	ID       string
+	Changes  []string
+	Declined bool
+	Err      error
+}
+
+// ApplyFixes runs the fix of every failed check that has one, asking confirm
+// first. Reports come back in check order.
+func (hcm *HealthCheckManager) ApplyFixes(ctx context.Context, env *doctorEnv, results []CheckResult, confirm func(DoctorCheck) bool) []fixReport {
+	failedIDs := make(map[string]bool)
+	for _, r := range results {
+		if r.Failed() {
+			failedIDs[r.Name] = true
+		}
+	}
+
+	var reports []fixReport
+	for _, check := range hcm.checks {
+		if check.Fix == nil || !failedIDs[check.ID] {
+			continue
+		}
+		if !confirm(check) {
+			reports = append(reports, fixReport{ID: check.ID, Declined: true})
+			continue
+		}
+		changes, err := check.Fix(ctx, env)
+		reports = append(reports, fixReport{ID: check.ID, Changes: changes, Err: err})
+	}
+	return reports
+}
+
+// attachFixes copies fix reports onto the results of the same checks
+func attachFixes(results []CheckResult, reports []fixReport) {
+	byID := make(map[string]fixReport, len(reports))
+	for _, r := range reports {
+		byID[r.ID] = r
+	}
+	for i := range results {
+		report, ok := byID[results[i].Name]
+		if !ok {
+			continue
+		}
+		results[i].Fixed = report.Changes
+		if report.Err != nil {
+			results[i].FixError = report.Err.Error()
+		}
+	}
+}
+
+func countChanges(reports []fixReport) int {
+	n := 0
+	for _, r := range reports {
+		n += len(r.Changes)
+	}
+	return n
+}
+
+func fixRegistry(ctx context.Context, env *doctorEnv) ([]string, error) {
+	client, err := env.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := registry.NewManager(env.sharedRoot).Reconcile(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, service := range result.Removed {
+		changes = append(changes, fmt.Sprintf(messages.DoctorFixedRegistryEntry, service))
+	}
+	return changes, nil
+}
+
+func fixZombieContainers(ctx context.Context, env *doctorEnv) ([]string, error) {
+	client, err := env.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+	zombies, err := env.zombieContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	for _, z := range zombies {
+		if err := client.RemoveContainer(ctx, z.Container, true); err != nil {
+			return changes, err
+		}
+		changes = append(changes, fmt.Sprintf(messages.DoctorFixedZombie, z.Container))
+	}
+
+	// Drop the registry entries of the containers just removed
+	reconciled, err := fixRegistry(ctx, env)
+	return append(changes, reconciled...), err
+}
+
+func fixGeneratedFiles(_ context.Context, env *doctorEnv) ([]string, error) {
+	cfg, configs, err := env.config()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := NewProjectManager().RegenerateFiles(cfg, configs)
+
+	var changes []string
+	for _, path := range paths {
+		changes = append(changes, fmt.Sprintf(messages.DoctorFixedFile, path))
+	}
+	return changes, err
+}
+
+func fixPorts(ctx context.Context, env *doctorEnv) ([]string, error) {
+	client, err := env.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	removed := make(map[string]bool)
+	for _, b := range env.busyPorts(ctx) {
+		// Ports held by other processes are left for the user
+		if b.holderID == "" || removed[b.holderID] {
+			continue
+		}
+		if err := client.RemoveContainer(ctx, b.holderID, true); err != nil {
+			return changes, err
+		}
+		removed[b.holderID] = true
+		changes = append(changes, fmt.Sprintf(messages.DoctorFixedPort, b.holder, b.port))
+	}
+	return changes, nil
+}
+
+func fixNetwork(ctx context.Context, env *doctorEnv) ([]string, error) {
+	cfg, _, err := env.config()
+	if err != nil {
+		return nil, err
+	}
+	client, err := env.docker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Labelled as compose would, so the next up adopts the network
+	name := projectNetworkName(cfg.Project.Name)
+	labels := map[string]string{
+		docker.ComposeProjectLabel: cfg.Project.Name,
+		docker.ComposeNetworkLabel: docker.DefaultNetworkName,
+		docker.LabelOttoManaged:    "true",
+		docker.LabelOttoProject:    cfg.Project.Name,
+	}
+	if err := docker.NewResourceManager(client).CreateNetwork(ctx, name, labels); err != nil {
+		return nil, err
+	}
+	return []string{fmt.Sprintf(messages.DoctorFixedNetwork, name)}, nil
+}
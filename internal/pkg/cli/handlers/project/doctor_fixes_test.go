@@ -0,0 +1,171 @@
+//go:build unit
+
+package project
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+func TestApplyFixes(t *testing.T) {
+	fixed := func(context.Context, *doctorEnv) ([]string, error) { return []string{"done"}, nil }
+	broken := func(context.Context, *doctorEnv) ([]string, error) { return nil, assert.AnError }
+	hcm := &HealthCheckManager{checks: []DoctorCheck{
+		{ID: "a", Fix: fixed},
+		{ID: "b", Fix: broken},
+		{ID: "c", Fix: fixed},
+		{ID: "d", Fix: fixed},
+		{ID: "e"},
+	}}
+	results := []CheckResult{
+		{Name: "a"},
+		{Name: "b"},
+		{Name: "c"},
+		{Name: "d", Passed: true},
+		{Name: "e"},
+	}
+
+	reports := hcm.ApplyFixes(context.Background(), &doctorEnv{}, results, func(c DoctorCheck) bool {
+		return c.ID != "c"
+	})
+
+	require.Len(t, reports, 3)
+	assert.Equal(t, fixReport{ID: "a", Changes: []string{"done"}}, reports[0])
+	assert.ErrorIs(t, reports[1].Err, assert.AnError)
+	assert.True(t, reports[2].Declined)
+	assert.Equal(t, 1, countChanges(reports))
+
+	attachFixes(results, reports)
+	assert.Equal(t, []string{"done"}, results[0].Fixed)
+	assert.Equal(t, assert.AnError.Error(), results[1].FixError)
+	assert.Empty(t, results[2].Fixed)
+}
+
+func TestCheckNetwork(t *testing.T) {
+	running := func(context.Context, container.ListOptions) ([]container.Summary, error) {
+		return []container.Summary{{ID: "1", Names: []string{"/app-redis-1"}, State: docker.StateRunning}}, nil
+	}
+	withNetworks := func(names ...string) func(context.Context, network.ListOptions) ([]network.Summary, error) {
+		return func(context.Context, network.ListOptions) ([]network.Summary, error) {
+			var result []network.Summary
+			for _, name := range names {
+				result = append(result, network.Summary{Name: name})
+			}
+			return result, nil
+		}
+	}
+
+	t.Run("passes when the network exists", func(t *testing.T) {
+		mock := &testhelpers.MockDockerClient{ContainerListFunc: running, NetworkListFunc: withNetworks("app-network")}
+		assert.True(t, checkNetwork(context.Background(), newTestDoctorEnv(mock, system.Info{})).Passed)
+	})
+
+	t.Run("fails when the network is gone", func(t *testing.T) {
+		mock := &testhelpers.MockDockerClient{ContainerListFunc: running, NetworkListFunc: withNetworks("my-app-network")}
+		assert.False(t, checkNetwork(context.Background(), newTestDoctorEnv(mock, system.Info{})).Passed)
+	})
+
+	t.Run("skips when nothing runs", func(t *testing.T) {
+		mock := &testhelpers.MockDockerClient{NetworkListFunc: withNetworks()}
+		assert.True(t, checkNetwork(context.Background(), newTestDoctorEnv(mock, system.Info{})).Skipped)
+	})
+}
+
+func TestFixNetwork(t *testing.T) {
+	var created string
+	var labels map[string]string
+	mock := &testhelpers.MockDockerClient{
+		NetworkCreateFunc: func(_ context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+			created, labels = name, options.Labels
+			return network.CreateResponse{ID: name}, nil
+		},
+	}
+
+	changes, err := fixNetwork(context.Background(), newTestDoctorEnv(mock, system.Info{}))
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "app-network", created)
+	assert.Equal(t, "app", labels[docker.ComposeProjectLabel])
+	assert.Equal(t, docker.DefaultNetworkName, labels[docker.ComposeNetworkLabel])
+}
+
+func TestCheckGeneratedFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	env := newTestDoctorEnv(&testhelpers.MockDockerClient{}, system.Info{})
+	require.NoError(t, os.MkdirAll(filepath.Join(core.OttoStackDir, core.GeneratedDir), core.PermReadWriteExec))
+	configPath := filepath.Join(core.OttoStackDir, core.ConfigFileName)
+	require.NoError(t, os.WriteFile(configPath, []byte("project:\n  name: app\n"), core.PermReadWrite))
+
+	assert.False(t, checkGeneratedFiles(context.Background(), env).Passed, "missing files")
+
+	for _, path := range []string{docker.DockerComposeFilePath, core.EnvGeneratedFilePath} {
+		require.NoError(t, os.WriteFile(path, nil, core.PermReadWrite))
+	}
+	assert.True(t, checkGeneratedFiles(context.Background(), env).Passed)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(configPath, later, later))
+	outcome := checkGeneratedFiles(context.Background(), env)
+	assert.False(t, outcome.Passed, "stale files")
+	assert.Contains(t, outcome.Message, core.ConfigFileName)
+}
+
+func TestBusyPortsAndFixPorts(t *testing.T) {
+	listener, err := net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	var removed []string
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(_ context.Context, options container.ListOptions) ([]container.Summary, error) {
+			if options.Filters.ExactMatch("label", docker.ComposeProjectLabel+"=app") {
+				return nil, nil
+			}
+			return []container.Summary{{
+				ID:    "paused",
+				Names: []string{"/old-redis-1"},
+				State: docker.StatePaused,
+				Ports: []container.Port{{PublicPort: uint16(port)}},
+			}, {
+				// Docker has released the ports of an exited container
+				ID:    "exited",
+				Names: []string{"/older-redis-1"},
+				State: "exited",
+				Ports: []container.Port{{PublicPort: uint16(port)}},
+			}}, nil
+		},
+		ContainerRemoveFunc: func(_ context.Context, id string, _ container.RemoveOptions) error {
+			removed = append(removed, id)
+			return nil
+		},
+	}
+	env := newTestDoctorEnv(mock, system.Info{}, serviceWith("redis", types.ContainerSpec{
+		Ports: []types.PortSpec{{External: strconv.Itoa(port), Internal: "6379"}},
+	}))
+
+	busy := env.busyPorts(context.Background())
+	require.Len(t, busy, 1)
+	assert.Equal(t, "old-redis-1", busy[0].holder)
+
+	changes, err := fixPorts(context.Background(), env)
+	require.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, []string{"paused"}, removed)
+}
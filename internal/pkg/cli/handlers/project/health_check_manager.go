@@ -2,173 +2,112 @@ package project
 
 import (
 	"context"
-	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 
-	"github.com/otto-nation/otto-stack/internal/core"
-	"github.com/otto-nation/otto-stack/internal/core/docker"
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
-	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
 )
 
 // CheckResult holds the outcome of a single health check for structured output.
 type CheckResult struct {
-	Name    string `json:"name"`
-	Passed  bool   `json:"passed"`
-	Message string `json:"message"`
+	Name        string   `json:"name"`
+	Severity    string   `json:"severity"`
+	Passed      bool     `json:"passed"`
+	Skipped     bool     `json:"skipped,omitempty"`
+	Message     string   `json:"message"`
+	Details     []string `json:"details,omitempty"`
+	Remediation string   `json:"remediation,omitempty"`
+	// Fixed lists the changes doctor --fix made for this check
+	Fixed    []string `json:"fixed,omitempty"`
+	FixError string   `json:"fix_error,omitempty"`
+}
+
+// Failed reports a check that ran and did not pass
+func (r CheckResult) Failed() bool {
+	return !r.Passed && !r.Skipped
 }
 
 // HealthCheckManager handles system health checks
-type HealthCheckManager struct{}
+type HealthCheckManager struct {
+	checks []DoctorCheck
+}
 
 // NewHealthCheckManager creates a new health check manager
 func NewHealthCheckManager() *HealthCheckManager {
-	return &HealthCheckManager{}
+	return &HealthCheckManager{checks: DoctorChecks}
 }
 
-// collectResults runs all checks without printing and returns structured results.
-func (hcm *HealthCheckManager) collectResults(ctx context.Context) []CheckResult {
-	const numChecks = 4
-	results := make([]CheckResult, 0, numChecks)
-
-	// Docker availability
-	dockerPassed := false
-	dockerMsg := messages.DoctorDockerNotFound
-	if isCommandAvailable(docker.DockerCmd) {
-		stackService, err := common.NewServiceManager(false)
-		if err == nil {
-			if err = stackService.CheckDockerHealth(ctx); err == nil {
-				dockerPassed = true
-				dockerMsg = messages.DoctorDockerAvailable
-			} else {
-				dockerMsg = messages.DoctorDockerDaemonNotRunning
+// RunChecks runs the registered checks and returns their results in order.
+// When services are named only the per-service checks run, scoped to them.
+func (hcm *HealthCheckManager) RunChecks(ctx context.Context, env *doctorEnv) []CheckResult {
+	results := make([]CheckResult, 0, len(hcm.checks))
+	for _, check := range hcm.checks {
+		if len(env.services) > 0 && !check.PerService {
+			continue
+		}
+		outcome := check.Run(ctx, env)
+		result := CheckResult{
+			Name:     check.ID,
+			Severity: check.Severity,
+			Passed:   outcome.Passed,
+			Skipped:  outcome.Skipped,
+			Message:  outcome.Message,
+			Details:  outcome.Details,
+		}
+		if result.Failed() {
+			result.Remediation = outcome.Remediation
+			if result.Remediation == "" {
+				result.Remediation = check.Remediation
 			}
 		}
+		results = append(results, result)
 	}
-	results = append(results, CheckResult{Name: "docker", Passed: dockerPassed, Message: dockerMsg})
-
-	// Docker Compose availability
-	composePassed := hcm.hasDockerComposePlugin() ||
-		isCommandAvailable(fmt.Sprintf("%s %s", docker.DockerCmd, docker.DockerComposeCmd))
-	composeMsg := messages.DoctorDockerComposeAvailable
-	if !composePassed {
-		composeMsg = messages.DoctorDockerComposeNotFound
-	}
-	results = append(results, CheckResult{Name: "docker-compose", Passed: composePassed, Message: composeMsg})
-
-	// Project initialization
-	_, statErr := os.Stat(core.OttoStackDir)
-	projectPassed := !os.IsNotExist(statErr)
-	projectMsg := messages.DoctorProjectInitialized
-	if !projectPassed {
-		projectMsg = messages.DoctorProjectNotInitialized
-	}
-	results = append(results, CheckResult{Name: "project-init", Passed: projectPassed, Message: projectMsg})
-
-	// Configuration file
-	configPath := filepath.Join(core.OttoStackDir, core.ConfigFileName)
-	_, statErr = os.Stat(configPath)
-	configPassed := !os.IsNotExist(statErr)
-	configMsg := messages.DoctorConfigValid
-	if !configPassed {
-		configMsg = messages.DoctorConfigDirMissing
-	}
-	results = append(results, CheckResult{Name: "configuration", Passed: configPassed, Message: configMsg})
-
 	return results
 }
 
-// RunAllChecks executes all health checks and returns overall status
-func (hcm *HealthCheckManager) RunAllChecks(ctx context.Context, base *base.BaseCommand) bool {
-	return hcm.CheckDocker(ctx, base) &&
-		hcm.CheckDockerCompose(base) &&
-		hcm.CheckProjectInit(base) &&
-		hcm.CheckConfiguration(base)
-}
-
-// CheckDocker verifies Docker is available and running
-func (hcm *HealthCheckManager) CheckDocker(ctx context.Context, base *base.BaseCommand) bool {
-	base.Output.Info(messages.DoctorCheckingDocker)
-
-	if !isCommandAvailable(docker.DockerCmd) {
-		base.Output.Error(messages.DoctorDockerNotFound)
-		base.Output.Info(messages.DoctorDockerInstallHelp, "https://docs.docker.com/get-docker/")
-		return false
-	}
-
-	// Use StackService to run docker info command
-	stackService, err := common.NewServiceManager(false)
-	if err != nil {
-		base.Output.Error(messages.ErrorsStackCreateFailed, err)
-		return false
-	}
-
-	err = stackService.CheckDockerHealth(ctx)
-	if err != nil {
-		base.Output.Error(messages.DoctorDockerDaemonNotRunning)
-		base.Output.Info(messages.DoctorDockerStartHelp)
-		return false
+// PrintResults writes results to the output, with details and a remediation
+// hint under each failed check
+func (hcm *HealthCheckManager) PrintResults(base *base.BaseCommand, results []CheckResult) {
+	for _, r := range results {
+		switch {
+		case r.Passed:
+			base.Output.Success("%s", r.Message)
+			continue
+		case r.Skipped:
+			base.Output.Muted("%s", r.Message)
+			continue
+		case r.Severity == SeverityError:
+			base.Output.Error("%s", r.Message)
+		case r.Severity == SeverityWarning:
+			base.Output.Warning("%s", r.Message)
+		default:
+			base.Output.Info("%s", r.Message)
+		}
+		for _, detail := range r.Details {
+			base.Output.Info(messages.DoctorDetail, detail)
+		}
+		if r.Remediation != "" {
+			base.Output.Info(messages.DoctorRemediation, r.Remediation)
+		}
 	}
-
-	base.Output.Success(messages.DoctorDockerAvailable)
-	return true
 }
 
-// CheckDockerCompose verifies Docker Compose is available
-func (hcm *HealthCheckManager) CheckDockerCompose(base *base.BaseCommand) bool {
-	base.Output.Info(messages.DoctorCheckingDockerCompose)
-
-	if hcm.hasDockerComposePlugin() {
-		base.Output.Success(messages.DoctorDockerComposeAvailable)
-		return true
-	}
-
-	// Check if docker compose command is available
-	composeCommand := fmt.Sprintf("%s %s", docker.DockerCmd, docker.DockerComposeCmd)
-	if isCommandAvailable(composeCommand) {
-		base.Output.Success(messages.DoctorDockerComposeAvailable)
-		return true
+// hasErrors reports whether any error check failed
+func hasErrors(results []CheckResult) bool {
+	for _, r := range results {
+		if r.Failed() && r.Severity == SeverityError {
+			return true
+		}
 	}
-
-	base.Output.Error(messages.DoctorDockerComposeNotFound)
-	base.Output.Info(messages.DoctorDockerComposeUpdate)
 	return false
 }
 
-// CheckProjectInit verifies project is initialized
-func (hcm *HealthCheckManager) CheckProjectInit(base *base.BaseCommand) bool {
-	base.Output.Info(messages.DoctorCheckingProjectInit)
-
-	if _, err := os.Stat(core.OttoStackDir); os.IsNotExist(err) {
-		base.Output.Error(messages.DoctorProjectNotInitialized)
-		base.Output.Info(messages.DoctorRunInitHelp, "otto init")
-		return false
-	}
-
-	base.Output.Success(messages.DoctorProjectInitialized)
-	return true
-}
-
-// CheckConfiguration verifies configuration files exist and are valid
-func (hcm *HealthCheckManager) CheckConfiguration(base *base.BaseCommand) bool {
-	base.Output.Info(messages.DoctorCheckingConfig)
-
-	configPath := filepath.Join(core.OttoStackDir, core.ConfigFileName)
-	if _, err := os.Stat(configPath); os.IsNotExist(err) {
-		base.Output.Error(messages.DoctorConfigDirMissing)
-		base.Output.Info(messages.InfoExpectedPath, configPath)
-		return false
+// countWarnings counts failed checks that are not errors
+func countWarnings(results []CheckResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Failed() && r.Severity != SeverityError {
+			n++
+		}
 	}
-
-	base.Output.Success(messages.DoctorConfigValid)
-	return true
-}
-
-// hasDockerComposePlugin checks if the Docker Compose plugin is available by
-// running "docker compose version". This is distinct from Docker daemon health.
-func (hcm *HealthCheckManager) hasDockerComposePlugin() bool {
-	return exec.Command(docker.DockerCmd, docker.DockerComposeCmd, "version").Run() == nil
+	return n
 }
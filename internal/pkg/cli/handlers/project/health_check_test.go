@@ -11,6 +11,7 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHealthCheckManager_New(t *testing.T) {
@@ -18,54 +19,65 @@ func TestHealthCheckManager_New(t *testing.T) {
 	assert.NotNil(t, manager)
 }
 
-func TestHealthCheckManager_RunAllChecks(t *testing.T) {
-	manager := NewHealthCheckManager()
-	mockBase := &base.BaseCommand{
-		Output: &mockOutput{},
-	}
-
-	result := manager.RunAllChecks(context.Background(), mockBase)
-	assert.IsType(t, false, result)
+func TestHealthCheckManager_RunChecks(t *testing.T) {
+	pass := func(context.Context, *doctorEnv) checkOutcome { return passed("ok") }
+	fail := func(context.Context, *doctorEnv) checkOutcome { return failed("broken") }
+	manager := &HealthCheckManager{checks: []DoctorCheck{
+		{ID: "global", Severity: SeverityError, Remediation: "fix global", Run: fail},
+		{ID: "per-service", Severity: SeverityWarning, Remediation: "fix service", PerService: true, Run: pass},
+	}}
+
+	t.Run("runs every check without services", func(t *testing.T) {
+		results := manager.RunChecks(context.Background(), newDoctorEnv(nil, ""))
+		require.Len(t, results, 2)
+		assert.Equal(t, "global", results[0].Name)
+		assert.Equal(t, SeverityError, results[0].Severity)
+		assert.Equal(t, "fix global", results[0].Remediation)
+		assert.Empty(t, results[1].Remediation, "passing checks carry no remediation")
+		assert.True(t, hasErrors(results))
+	})
+
+	t.Run("runs only per-service checks with services", func(t *testing.T) {
+		results := manager.RunChecks(context.Background(), newDoctorEnv([]string{"postgres"}, ""))
+		require.Len(t, results, 1)
+		assert.Equal(t, "per-service", results[0].Name)
+		assert.False(t, hasErrors(results))
+	})
+
+	t.Run("outcome remediation overrides the default", func(t *testing.T) {
+		override := &HealthCheckManager{checks: []DoctorCheck{{
+			ID: "custom", Severity: SeverityError, Remediation: "default",
+			Run: func(context.Context, *doctorEnv) checkOutcome {
+				return checkOutcome{Message: "broken", Remediation: "specific"}
+			},
+		}}}
+		results := override.RunChecks(context.Background(), newDoctorEnv(nil, ""))
+		assert.Equal(t, "specific", results[0].Remediation)
+	})
 }
 
-func TestHealthCheckManager_CheckDocker(t *testing.T) {
+func TestHealthCheckManager_PrintResults(t *testing.T) {
 	manager := NewHealthCheckManager()
 	mockBase := &base.BaseCommand{
 		Output: &mockOutput{},
 	}
 
-	result := manager.CheckDocker(context.Background(), mockBase)
-	assert.IsType(t, false, result)
+	manager.PrintResults(mockBase, []CheckResult{
+		{Name: "a", Severity: SeverityError, Passed: true, Message: "ok"},
+		{Name: "b", Severity: SeverityWarning, Message: "warn", Details: []string{"x"}, Remediation: "fix"},
+		{Name: "c", Severity: SeverityInfo, Skipped: true, Message: "skipped"},
+	})
 }
 
-func TestHealthCheckManager_CheckDockerCompose(t *testing.T) {
-	manager := NewHealthCheckManager()
-	mockBase := &base.BaseCommand{
-		Output: &mockOutput{},
+func TestCountWarnings(t *testing.T) {
+	results := []CheckResult{
+		{Severity: SeverityError, Passed: true},
+		{Severity: SeverityWarning},
+		{Severity: SeverityInfo},
+		{Severity: SeverityWarning, Skipped: true},
 	}
-
-	result := manager.CheckDockerCompose(mockBase)
-	assert.IsType(t, false, result)
-}
-
-func TestHealthCheckManager_CheckProjectInit(t *testing.T) {
-	manager := NewHealthCheckManager()
-	mockBase := &base.BaseCommand{
-		Output: &mockOutput{},
-	}
-
-	result := manager.CheckProjectInit(mockBase)
-	assert.IsType(t, false, result)
-}
-
-func TestHealthCheckManager_CheckConfiguration(t *testing.T) {
-	manager := NewHealthCheckManager()
-	mockBase := &base.BaseCommand{
-		Output: &mockOutput{},
-	}
-
-	result := manager.CheckConfiguration(mockBase)
-	assert.IsType(t, false, result)
+	assert.Equal(t, 2, countWarnings(results))
+	assert.False(t, hasErrors(results))
 }
 
 func TestValidationManager_New(t *testing.T) {
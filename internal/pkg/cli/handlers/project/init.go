@@ -86,6 +86,7 @@ func (h *InitHandler) Handle(ctx context.Context, cmd *cobra.Command, args []str
 	}
 
 	h.displaySuccessMessage(projectCtx.Project.Name, base)
+	common.RecordProject(projectCtx.Project.Name, projectCtx.Services.Names, base.Output)
 
 	if projectCtx.Advanced != nil && projectCtx.Advanced.AutoStart {
 		base.Output.Info("%s", messages.InfoAutoStarting)
@@ -0,0 +1,194 @@
+package project
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// PortAssignment is a host port up picked for a service port under
+// ports.auto. Key is the variable the port is read from, or its container
+// port; Wanted is the port it would have had.
+type PortAssignment struct {
+	Service string
+	Key     string
+	Wanted  int
+	Port    int
+}
+
+// Moved reports whether the wanted port was taken
+func (a PortAssignment) Moved() bool {
+	return a.Port != a.Wanted
+}
+
+// AllocatePorts picks a host port for each port of the project-local
+// services that has none in ports.assigned, when ports.auto is set: the
+// default port plus ports.offset, or the next free one when that is taken.
+// The picks are recorded in config.local.yaml and in cfg, so ApplyPorts
+// uses them and later runs keep them.
+func AllocatePorts(serviceConfigs []types.ServiceConfig, cfg *config.Config) ([]PortAssignment, error) {
+	if cfg == nil || cfg.Ports == nil || !cfg.Ports.Auto {
+		return nil, nil
+	}
+
+	taken := make(map[int]bool)
+	for _, ports := range cfg.Ports.Assigned {
+		for _, port := range ports {
+			taken[port] = true
+		}
+	}
+
+	var assignments []PortAssignment
+	for _, svc := range localServices(serviceConfigs, cfg) {
+		for _, port := range svc.Container.Ports {
+			key, defaultPort := hostPortDefault(port)
+			if _, ok := cfg.Ports.Assigned[svc.Name][key]; ok {
+				continue
+			}
+			start, err := strconv.Atoi(defaultPort)
+			if err != nil {
+				continue
+			}
+			start += cfg.Ports.Offset
+			free, ok := firstFreePort(start, taken)
+			if !ok {
+				return nil, pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsPortsNoFreePort, start, svc.Name)
+			}
+			taken[free] = true
+			assignments = append(assignments, PortAssignment{Service: svc.Name, Key: key, Wanted: start, Port: free})
+		}
+	}
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+
+	if cfg.Ports.Assigned == nil {
+		cfg.Ports.Assigned = make(map[string]map[string]int)
+	}
+	picked := make(map[string]map[string]int)
+	for _, a := range assignments {
+		for _, assigned := range []map[string]map[string]int{picked, cfg.Ports.Assigned} {
+			if assigned[a.Service] == nil {
+				assigned[a.Service] = make(map[string]int)
+			}
+			assigned[a.Service][a.Key] = a.Port
+		}
+	}
+	if err := config.AssignPorts(core.OttoStackDir, picked); err != nil {
+		return nil, err
+	}
+	return assignments, nil
+}
+
+// ApplyPorts returns serviceConfigs, as resolved from the catalog, with the
+// host ports cfg.Ports gives the project-local services: an assigned port,
+// or else the default plus the offset. A port read from a variable keeps it,
+// with the new port as its default, and the service's environment follows,
+// so the compose file and .env.generated agree. Shared services keep the
+// ports of their shared container.
+func ApplyPorts(serviceConfigs []types.ServiceConfig, cfg *config.Config) []types.ServiceConfig {
+	if cfg == nil || cfg.Ports == nil {
+		return serviceConfigs
+	}
+	local := make(map[string]bool)
+	for _, svc := range localServices(serviceConfigs, cfg) {
+		local[svc.Name] = true
+	}
+
+	applied := slices.Clone(serviceConfigs)
+	for i, svc := range applied {
+		if !local[svc.Name] || len(svc.Container.Ports) == 0 {
+			continue
+		}
+		ports := slices.Clone(svc.Container.Ports)
+		defaults := make(map[string]string)
+		for j, port := range ports {
+			host, ok := hostPort(cfg.Ports, svc.Name, port)
+			if !ok {
+				continue
+			}
+			if match := portVariable.FindStringSubmatch(port.External); match != nil {
+				ports[j].External = fmt.Sprintf("${%s:-%d}", match[1], host)
+				defaults[port.External] = ports[j].External
+			} else {
+				ports[j].External = strconv.Itoa(host)
+			}
+		}
+		svc.Container.Ports = ports
+		svc.Environment = replaceDefaults(svc.Environment, defaults)
+		svc.Container.Environment = replaceDefaults(svc.Container.Environment, defaults)
+		svc.AllEnvironment = replaceDefaults(svc.AllEnvironment, defaults)
+		applied[i] = svc
+	}
+	return applied
+}
+
+// PublishedPort returns the host port a service publishes a container port
+// on, reading a ${VAR:-default} port from the environment as the compose
+// file does
+func PublishedPort(svc types.ServiceConfig, containerPort int) (int, bool) {
+	for _, port := range svc.Container.Ports {
+		if port.Internal != strconv.Itoa(containerPort) {
+			continue
+		}
+		external := port.External
+		if match := portVariable.FindStringSubmatch(external); match != nil {
+			external = match[2]
+			if value := os.Getenv(match[1]); value != "" {
+				external = value
+			}
+		}
+		published, err := strconv.Atoi(external)
+		return published, err == nil
+	}
+	return 0, false
+}
+
+// hostPort returns the host port ports gives a service port, if any
+func hostPort(ports *config.PortsConfig, service string, port types.PortSpec) (int, bool) {
+	key, defaultPort := hostPortDefault(port)
+	if assigned, ok := ports.Assigned[service][key]; ok {
+		return assigned, true
+	}
+	if ports.Offset == 0 {
+		return 0, false
+	}
+	start, err := strconv.Atoi(defaultPort)
+	if err != nil {
+		return 0, false
+	}
+	return start + ports.Offset, true
+}
+
+// replaceDefaults rewrites references such as ${POSTGRES_PORT:-5432} in env
+// values to carry the new default
+func replaceDefaults(env map[string]string, defaults map[string]string) map[string]string {
+	if len(env) == 0 || len(defaults) == 0 {
+		return env
+	}
+	replaced := make(map[string]string, len(env))
+	for key, value := range env {
+		for _, old := range slices.Sorted(maps.Keys(defaults)) {
+			value = strings.ReplaceAll(value, old, defaults[old])
+		}
+		replaced[key] = value
+	}
+	return replaced
+}
+
+// localServices returns the services the project runs itself
+func localServices(serviceConfigs []types.ServiceConfig, cfg *config.Config) []types.ServiceConfig {
+	if cfg.Sharing == nil {
+		return serviceConfigs
+	}
+	return FilterProjectServices(serviceConfigs, cfg.Sharing.Enabled, cfg.Sharing.Services)
+}
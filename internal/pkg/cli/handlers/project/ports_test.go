@@ -0,0 +1,128 @@
+//go:build unit
+
+package project
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func portedService(name, variable, port string, shareable bool) types.ServiceConfig {
+	ref := "${" + variable + ":-" + port + "}"
+	return types.ServiceConfig{
+		Name:           name,
+		Shareable:      shareable,
+		Environment:    map[string]string{variable: ref, "URL": "db://localhost:" + ref + "/app"},
+		AllEnvironment: map[string]string{variable: ref, "URL": "db://localhost:" + ref + "/app"},
+		Container:      types.ContainerSpec{Ports: []types.PortSpec{{External: ref, Internal: port}}},
+	}
+}
+
+func TestApplyPorts_Offset(t *testing.T) {
+	configs := []types.ServiceConfig{
+		portedService("postgres", "POSTGRES_PORT", "5432", true),
+		portedService("redis", "REDIS_PORT", "6379", true),
+		{Name: "web", Container: types.ContainerSpec{Ports: []types.PortSpec{{External: "8080", Internal: "80"}}}},
+	}
+	cfg := &config.Config{
+		Sharing: &config.SharingConfig{Enabled: true, Services: map[string]bool{"redis": true}},
+		Ports:   &config.PortsConfig{Offset: 100},
+	}
+
+	applied := ApplyPorts(configs, cfg)
+
+	assert.Equal(t, "${POSTGRES_PORT:-5532}", applied[0].Container.Ports[0].External)
+	assert.Equal(t, "db://localhost:${POSTGRES_PORT:-5532}/app", applied[0].AllEnvironment["URL"])
+	assert.Equal(t, "${POSTGRES_PORT:-5532}", applied[0].Environment["POSTGRES_PORT"])
+	// redis runs on the shared container, which keeps its ports
+	assert.Equal(t, "${REDIS_PORT:-6379}", applied[1].Container.Ports[0].External)
+	assert.Equal(t, "8180", applied[2].Container.Ports[0].External)
+	// the resolved configs are left as they were
+	assert.Equal(t, "${POSTGRES_PORT:-5432}", configs[0].Container.Ports[0].External)
+}
+
+func TestApplyPorts_AssignedWinsOverOffset(t *testing.T) {
+	configs := []types.ServiceConfig{portedService("postgres", "POSTGRES_PORT", "5432", true)}
+	cfg := &config.Config{Ports: &config.PortsConfig{
+		Offset:   100,
+		Assigned: map[string]map[string]int{"postgres": {"POSTGRES_PORT": 5440}},
+	}}
+
+	applied := ApplyPorts(configs, cfg)
+	assert.Equal(t, "${POSTGRES_PORT:-5440}", applied[0].Container.Ports[0].External)
+}
+
+func TestApplyPorts_NoPortsConfig(t *testing.T) {
+	configs := []types.ServiceConfig{portedService("postgres", "POSTGRES_PORT", "5432", true)}
+	assert.Equal(t, configs, ApplyPorts(configs, &config.Config{}))
+}
+
+func TestAllocatePorts_SkipsTakenPortAndRecords(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.Mkdir(core.OttoStackDir, core.PermReadWriteExec))
+
+	listener, err := net.Listen("tcp", ":0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	busy := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+
+	configs := []types.ServiceConfig{portedService("app", "APP_PORT", busy, false)}
+	cfg := &config.Config{Ports: &config.PortsConfig{Auto: true}}
+
+	assignments, err := AllocatePorts(configs, cfg)
+	require.NoError(t, err)
+	require.Len(t, assignments, 1)
+	assert.True(t, assignments[0].Moved())
+	assert.Equal(t, "APP_PORT", assignments[0].Key)
+	assert.Equal(t, assignments[0].Port, cfg.Ports.Assigned["app"]["APP_PORT"])
+
+	data, err := os.ReadFile(filepath.Join(core.OttoStackDir, core.LocalConfigFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "APP_PORT: "+strconv.Itoa(assignments[0].Port))
+
+	// a recorded port is kept
+	assignments, err = AllocatePorts(configs, cfg)
+	require.NoError(t, err)
+	assert.Empty(t, assignments)
+}
+
+func TestAllocatePorts_OffWithoutAuto(t *testing.T) {
+	configs := []types.ServiceConfig{portedService("postgres", "POSTGRES_PORT", "5432", false)}
+	assignments, err := AllocatePorts(configs, &config.Config{Ports: &config.PortsConfig{Offset: 100}})
+	require.NoError(t, err)
+	assert.Empty(t, assignments)
+}
+
+func TestPublishedPort(t *testing.T) {
+	svc := types.ServiceConfig{Name: "jaeger"}
+	svc.Container.Ports = []types.PortSpec{
+		{External: "${JAEGER_UI_PORT:-16786}", Internal: "16686"},
+		{External: "14268", Internal: "14268"},
+	}
+
+	port, ok := PublishedPort(svc, 16686)
+	assert.True(t, ok)
+	assert.Equal(t, 16786, port)
+
+	t.Setenv("JAEGER_UI_PORT", "17000")
+	port, ok = PublishedPort(svc, 16686)
+	assert.True(t, ok)
+	assert.Equal(t, 17000, port)
+
+	port, ok = PublishedPort(svc, 14268)
+	assert.True(t, ok)
+	assert.Equal(t, 14268, port)
+
+	_, ok = PublishedPort(svc, 9090)
+	assert.False(t, ok)
+}
@@ -2,8 +2,10 @@ package project
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -12,10 +14,13 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
 	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
 	"github.com/otto-nation/otto-stack/internal/pkg/compose"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	"github.com/otto-nation/otto-stack/internal/pkg/env"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/filesystem"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/provision"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
 	svc "github.com/otto-nation/otto-stack/internal/pkg/services"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 )
@@ -66,10 +71,15 @@ func (pm *ProjectManager) CreateProjectStructure(projectCtx clicontext.Context,
 		return pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, "", messages.ErrorsConfigWriteFailed, err)
 	}
 
+	// config.yaml and the service config files point editors at these schemas
+	if _, err := WriteSchemas(filepath.Join(core.OttoStackDir, core.SchemasDir)); err != nil {
+		base.Output.Warning(messages.WarningsSchemaWriteFailed, err)
+	}
+
 	pm.configManager.GenerateServiceConfigs(projectCtx.Services.Configs, projectCtx.Sharing.Enabled, base)
 
 	// Generate env file with ALL services (shared and non-shared)
-	if err := pm.generateEnvFile(projectCtx.Services.Configs, projectCtx.Project.Name, base); err != nil {
+	if err := pm.generateEnvFile(projectCtx.Services.Configs, projectCtx.Project.Name, projectCtx.Sharing, base); err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ValidationFailedGenerateEnv, err)
 	}
 
@@ -104,22 +114,131 @@ func (pm *ProjectManager) CreateProjectStructure(projectCtx clicontext.Context,
 	return nil
 }
 
+// RegenerateFiles rewrites the generated env and compose files from the
+// project configuration, the same way init writes them. It returns the paths
+// it wrote and prints nothing.
+func (pm *ProjectManager) RegenerateFiles(cfg *config.Config, serviceConfigs []types.ServiceConfig) ([]string, error) {
+	sharing := sharingSpec(cfg)
+	serviceConfigs = ApplyPorts(ApplyTLS(serviceConfigs, cfg), cfg)
+
+	if err := pm.writeEnvFile(serviceConfigs, cfg.Project.Name, sharing); err != nil {
+		return nil, err
+	}
+	projectServices := pm.filterProjectServices(serviceConfigs, sharing)
+	if err := pm.writeComposeFile(projectServices, cfg.Project.Name, sharing.Enabled, config.AttachedProjects(cfg)); err != nil {
+		return []string{core.EnvGeneratedFilePath}, err
+	}
+	return []string{core.EnvGeneratedFilePath, docker.DockerComposeFilePath}, nil
+}
+
+// RegenerateEnvFile rewrites the generated env file, picking up the
+// credentials the project was provisioned on shared containers and the host
+// ports it was given
+func (pm *ProjectManager) RegenerateEnvFile(cfg *config.Config, serviceConfigs []types.ServiceConfig) error {
+	return pm.writeEnvFile(ApplyPorts(ApplyTLS(serviceConfigs, cfg), cfg), cfg.Project.Name, sharingSpec(cfg))
+}
+
+// sharingSpec reads the sharing settings generation needs from cfg
+func sharingSpec(cfg *config.Config) *clicontext.SharingSpec {
+	sharing := &clicontext.SharingSpec{}
+	if cfg.Sharing != nil {
+		sharing.Enabled, sharing.Services, sharing.Instances = cfg.Sharing.Enabled, cfg.Sharing.Services, cfg.Sharing.Instances
+	}
+	return sharing
+}
+
 // generateEnvFile generates the .env file
-func (pm *ProjectManager) generateEnvFile(serviceConfigs []types.ServiceConfig, projectName string, base *base.BaseCommand) error {
-	if err := env.GenerateFile(projectName, serviceConfigs, core.EnvGeneratedFilePath); err != nil {
-		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ValidationFailedGenerateEnv, err)
+func (pm *ProjectManager) generateEnvFile(serviceConfigs []types.ServiceConfig, projectName string, sharing *clicontext.SharingSpec, base *base.BaseCommand) error {
+	if err := pm.writeEnvFile(serviceConfigs, projectName, sharing); err != nil {
+		return err
 	}
 
 	base.Output.Success(messages.SuccessCreatedEnvFile, core.EnvGeneratedFilePath)
 	return nil
 }
 
+func (pm *ProjectManager) writeEnvFile(serviceConfigs []types.ServiceConfig, projectName string, sharing *clicontext.SharingSpec) error {
+	overrides := pm.sharedEnvOverrides(serviceConfigs, projectName, sharing)
+	if err := env.GenerateFile(projectName, serviceConfigs, overrides, core.EnvGeneratedFilePath); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ValidationFailedGenerateEnv, err)
+	}
+	return nil
+}
+
+// sharedEnvOverrides points the env file at the credentials and namespaces
+// the project was given on the shared containers it uses, such as its own
+// DATABASE_URL or redis database. Credentials also apply to services the
+// project has since moved to run itself.
+func (pm *ProjectManager) sharedEnvOverrides(serviceConfigs []types.ServiceConfig, projectName string, sharing *clicontext.SharingSpec) env.Overrides {
+	if sharing == nil || !sharing.Enabled {
+		return nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	sharedRoot := filepath.Join(homeDir, core.OttoStackDir, core.SharedDir)
+	// Loading creates the registry file, so a machine without one is left alone
+	if _, err := os.Stat(filepath.Join(sharedRoot, core.SharedRegistryFile)); err != nil {
+		return nil
+	}
+	reg := registry.NewManager(sharedRoot)
+	creds, err := reg.ProjectCredentials(projectName)
+	if err != nil {
+		return nil
+	}
+	namespaces, err := reg.ProjectNamespaces(projectName)
+	if err != nil {
+		return nil
+	}
+	containers, err := reg.List()
+	if err != nil {
+		return nil
+	}
+
+	local := pm.filterProjectServices(serviceConfigs, sharing)
+	overrides := make(env.Overrides)
+	for _, cfg := range serviceConfigs {
+		if slices.ContainsFunc(local, func(l types.ServiceConfig) bool { return l.Name == cfg.Name }) {
+			// A service moved off a shared container keeps the database and
+			// role it had there, which came along with its data
+			if vars := provision.EnvOverrides(cfg.Name, creds[cfg.Name]); len(vars) > 0 {
+				overrides[cfg.Name] = vars
+			}
+			continue
+		}
+		// A second instance is registered under its own name and publishes
+		// its own host ports
+		key := SharedInstanceName(cfg.Name, sharing.Instances[cfg.Name])
+		vars := make(map[string]string)
+		if container := containers[key]; container != nil && key != cfg.Name {
+			maps.Copy(vars, container.Ports)
+		}
+		maps.Copy(vars, provision.EnvOverrides(cfg.Name, creds[key]))
+		maps.Copy(vars, provision.NamespaceEnvOverrides(cfg.Name, namespaces[key]))
+		if len(vars) > 0 {
+			overrides[cfg.Name] = vars
+		}
+	}
+	return overrides
+}
+
 // generateDockerComposeWithSharing generates the docker-compose.yml file with sharing info
 func (pm *ProjectManager) generateDockerComposeWithSharing(serviceConfigs []types.ServiceConfig, projectName string, hasSharingEnabled bool, base *base.BaseCommand) error {
+	if err := pm.writeComposeFile(serviceConfigs, projectName, hasSharingEnabled, nil); err != nil {
+		return err
+	}
+
+	base.Output.Success(messages.SuccessCreatedComposeFile, docker.DockerComposeFilePath)
+	return nil
+}
+
+func (pm *ProjectManager) writeComposeFile(serviceConfigs []types.ServiceConfig, projectName string, hasSharingEnabled bool, attachProjects []string) error {
 	generator, err := compose.NewGenerator(projectName)
 	if err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsComposeGeneratorCreateFailed, err)
 	}
+	generator.AttachProjects(attachProjects)
 
 	var header string
 	if hasSharingEnabled {
@@ -142,8 +261,6 @@ func (pm *ProjectManager) generateDockerComposeWithSharing(serviceConfigs []type
 	if err := filesystem.WriteFile(docker.DockerComposeFilePath, content, core.PermReadWrite); err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsComposeWriteFailed, err)
 	}
-
-	base.Output.Success(messages.SuccessCreatedComposeFile, docker.DockerComposeFilePath)
 	return nil
 }
 
@@ -180,7 +297,7 @@ func (pm *ProjectManager) createComposeOverrideFile(base *base.BaseCommand) erro
 func (pm *ProjectManager) createGitignoreEntries(base *base.BaseCommand) error {
 	entries := []string{
 		"# " + core.AppNameTitle,
-		core.OttoStackDir + "/logs/",
+		core.OttoStackDir + "/" + core.LogsDir + "/",
 		core.ExtENV + core.LocalFileExtension,
 		core.LocalConfigFileName,
 		docker.DockerComposeOverrideFileName,
@@ -348,7 +465,7 @@ func GenerateSharedFiles(serviceConfigs []types.ServiceConfig, sharedRoot string
 	base.Output.Success(messages.SuccessCreatedSharedComposeFile, composePath)
 
 	envPath := filepath.Join(generatedDir, core.EnvGeneratedFileName)
-	if err := env.GenerateFile("shared", sharedConfigs, envPath); err != nil {
+	if err := env.GenerateFile("shared", sharedConfigs, nil, envPath); err != nil {
 		return err
 	}
 	base.Output.Success(messages.SuccessCreatedEnvFile, envPath)
@@ -365,6 +482,9 @@ func GenerateSharedFiles(serviceConfigs []types.ServiceConfig, sharedRoot string
 			return err
 		}
 	}
+	if _, err := WriteSchemas(filepath.Join(sharedRoot, core.SchemasDir)); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -0,0 +1,178 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// Output formats accepted by projects
+const (
+	projectsFormatTable = "table"
+	projectsFormatJSON  = "json"
+)
+
+// ProjectsHandler handles the projects command
+type ProjectsHandler struct {
+	// dockerClient is connected on demand when nil
+	dockerClient *docker.Client
+}
+
+// NewProjectsHandler creates a new projects handler
+func NewProjectsHandler() *ProjectsHandler {
+	return &ProjectsHandler{}
+}
+
+// projectUsage is what Docker reports about every compose project at once
+type projectUsage struct {
+	running map[string]int
+	disk    map[string]int64
+}
+
+// Handle executes the projects command
+func (h *ProjectsHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParseProjectsFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	switch flags.Format {
+	case "", projectsFormatTable, projectsFormatJSON:
+	default:
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationProjectsFormatInvalid, flags.Format)
+	}
+	jsonOutput := ci.GetFlags(cmd).JSON || flags.Format == projectsFormatJSON
+
+	index, err := common.ProjectIndex()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProjectIndexLoadFailed, err)
+	}
+
+	var pruned []*registry.ProjectEntry
+	if flags.Prune {
+		if pruned, err = index.Prune(); err != nil {
+			return err
+		}
+	}
+
+	entries, err := index.List()
+	if err != nil {
+		return err
+	}
+
+	usage := h.usage(ctx, len(entries) > 0)
+	rows := make([]display.ProjectRow, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, h.buildRow(entry, usage))
+	}
+
+	if jsonOutput {
+		return h.outputJSON(base, rows, pruned)
+	}
+
+	base.Output.Header(messages.ProjectsHeader)
+	if flags.Prune {
+		for _, entry := range pruned {
+			base.Output.Info(messages.ProjectsPruned, entry.Name, entry.Path)
+		}
+		if len(pruned) == 0 {
+			base.Output.Info(messages.ProjectsNothingPruned)
+		}
+	}
+	if len(rows) == 0 {
+		base.Output.Info(messages.ProjectsNone)
+		return nil
+	}
+	if usage == nil {
+		base.Output.Warning(messages.ProjectsDockerUnavailable)
+	}
+
+	display.RenderProjectsTable(base.Output.Writer(), rows, time.Now(), base.Output.GetNoColor())
+	base.Output.Info(messages.ProjectsTotal, len(rows))
+	return nil
+}
+
+// usage asks Docker for running containers and volume sizes. Docker is
+// optional here, so a nil result means it could not be reached.
+func (h *ProjectsHandler) usage(ctx context.Context, needed bool) *projectUsage {
+	if !needed {
+		return nil
+	}
+
+	client := h.dockerClient
+	if client == nil {
+		var err error
+		if client, err = docker.NewClient(nil); err != nil {
+			return nil
+		}
+		defer func() { _ = client.Close() }()
+	}
+
+	running, err := client.RunningContainersByProject(ctx)
+	if err != nil {
+		return nil
+	}
+	disk, err := client.ProjectVolumeUsage(ctx)
+	if err != nil {
+		disk = nil
+	}
+	return &projectUsage{running: running, disk: disk}
+}
+
+func (h *ProjectsHandler) buildRow(entry *registry.ProjectEntry, usage *projectUsage) display.ProjectRow {
+	row := display.ProjectRow{
+		Name:     entry.Name,
+		Path:     entry.Path,
+		Services: entry.Services,
+		LastUsed: entry.LastUsed,
+	}
+
+	_, statErr := os.Stat(entry.Path)
+	exists := !os.IsNotExist(statErr)
+	if usage != nil {
+		running := usage.running[entry.Name]
+		row.Running = &running
+		if usage.disk != nil {
+			size := usage.disk[entry.Name]
+			row.DiskUsage = &size
+		}
+	}
+	row.State = display.ProjectState(exists, row.Running)
+	return row
+}
+
+func (h *ProjectsHandler) outputJSON(base *base.BaseCommand, rows []display.ProjectRow, pruned []*registry.ProjectEntry) error {
+	output := ci.ProjectsOutput{
+		Projects: make([]any, 0, len(rows)),
+		Count:    len(rows),
+	}
+	for _, row := range rows {
+		output.Projects = append(output.Projects, row)
+	}
+	for _, entry := range pruned {
+		output.Pruned = append(output.Pruned, entry)
+	}
+	return json.NewEncoder(base.Output.Writer()).Encode(output)
+}
+
+// ValidateArgs validates the command arguments
+func (h *ProjectsHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *ProjectsHandler) GetRequiredFlags() []string {
+	return []string{}
+}
@@ -0,0 +1,97 @@
+//go:build unit
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	dockertypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+func newProjectsCommand(format string, prune bool) *cobra.Command {
+	cmd := &cobra.Command{Use: core.CommandProjects}
+	cmd.Flags().Bool(core.FlagPrune, prune, "")
+	cmd.Flags().String(core.FlagFormat, format, "")
+	return cmd
+}
+
+func TestProjectsHandler_ListsProjectsAsJSON(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	apiDir := t.TempDir()
+	goneDir := filepath.Join(t.TempDir(), "deleted")
+	index := registry.NewProjectIndexManager(filepath.Join(home, core.OttoStackDir))
+	require.NoError(t, index.Touch("gone", goneDir, nil))
+	require.NoError(t, index.Touch("api", apiDir, []string{"postgres"}))
+
+	mock := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{{State: docker.StateRunning, Labels: map[string]string{docker.ComposeProjectLabel: "api"}}}, nil
+		},
+		DiskUsageFunc: func(ctx context.Context, options dockertypes.DiskUsageOptions) (dockertypes.DiskUsage, error) {
+			return dockertypes.DiskUsage{Volumes: []*volume.Volume{
+				{Labels: map[string]string{docker.ComposeProjectLabel: "api"}, UsageData: &volume.UsageData{Size: 2048}},
+			}}, nil
+		},
+	}
+	handler := &ProjectsHandler{dockerClient: docker.NewClientWithDependencies(mock, nil, nil)}
+
+	output := &bufferOutput{}
+	require.NoError(t, handler.Handle(context.Background(), newProjectsCommand("json", false), nil, &base.BaseCommand{Output: output}))
+
+	var result struct {
+		Projects []display.ProjectRow `json:"projects"`
+		Count    int                  `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(output.buf.Bytes(), &result))
+	require.Equal(t, 2, result.Count)
+
+	api := result.Projects[0]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, docker.HealthStatusRunning, api.State)
+	require.NotNil(t, api.Running)
+	assert.Equal(t, 1, *api.Running)
+	require.NotNil(t, api.DiskUsage)
+	assert.EqualValues(t, 2048, *api.DiskUsage)
+	assert.Equal(t, display.StateMissing, result.Projects[1].State)
+}
+
+func TestProjectsHandler_Prune(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	index := registry.NewProjectIndexManager(filepath.Join(home, core.OttoStackDir))
+	require.NoError(t, index.Touch("gone", filepath.Join(t.TempDir(), "deleted"), nil))
+
+	handler := &ProjectsHandler{dockerClient: docker.NewClientWithDependencies(&testhelpers.MockDockerClient{}, nil, nil)}
+	output := &bufferOutput{}
+	require.NoError(t, handler.Handle(context.Background(), newProjectsCommand("json", true), nil, &base.BaseCommand{Output: output}))
+
+	var result struct {
+		Pruned []registry.ProjectEntry `json:"pruned"`
+		Count  int                     `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(output.buf.Bytes(), &result))
+	assert.Zero(t, result.Count)
+	require.Len(t, result.Pruned, 1)
+	assert.Equal(t, "gone", result.Pruned[0].Name)
+}
+
+func TestProjectsHandler_RejectsUnknownFormat(t *testing.T) {
+	err := NewProjectsHandler().Handle(context.Background(), newProjectsCommand("yaml", false), nil, &base.BaseCommand{Output: &mockOutput{}})
+	assert.Error(t, err)
+}
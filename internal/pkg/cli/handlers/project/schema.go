@@ -0,0 +1,115 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/validation"
+)
+
+// SchemaActionExport writes the schemas to disk
+const SchemaActionExport = "export"
+
+// SchemaHandler handles the schema command
+type SchemaHandler struct{}
+
+// NewSchemaHandler creates a new schema handler
+func NewSchemaHandler() *SchemaHandler {
+	return &SchemaHandler{}
+}
+
+// Handle executes the schema command
+func (h *SchemaHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	if err := h.ValidateArgs(args); err != nil {
+		return err
+	}
+	flags, err := core.ParseSchemaFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+
+	dir := flags.Dir
+	if dir == "" {
+		if err := validation.CheckInitialization(); err != nil {
+			return err
+		}
+		dir = filepath.Join(core.OttoStackDir, core.SchemasDir)
+	}
+
+	written, err := WriteSchemas(dir)
+	if err != nil {
+		return err
+	}
+	base.Output.Success(messages.SchemaExported, len(written), dir)
+	return nil
+}
+
+// ValidateArgs validates the command arguments
+func (h *SchemaHandler) ValidateArgs(args []string) error {
+	if len(args) != 1 || args[0] != SchemaActionExport {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ValidationSchemaActionInvalid, strings.Join(args, " "))
+	}
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *SchemaHandler) GetRequiredFlags() []string {
+	return []string{}
+}
+
+// WriteSchemas writes the JSON Schema for the project config and for every
+// catalog service into dir, returning the paths it wrote
+func WriteSchemas(dir string) ([]string, error) {
+	manager, err := services.New()
+	if err != nil {
+		return nil, err
+	}
+
+	documents := map[string]map[string]any{core.ConfigSchemaFileName: config.JSONSchema()}
+	for name, service := range manager.GetAllServices() {
+		documents[filepath.Join(core.ServiceConfigsDir, name+core.SchemaFileSuffix)] = services.ServiceJSONSchema(service)
+	}
+
+	names := slices.Sorted(maps.Keys(documents))
+	written := make([]string, 0, len(names))
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		if err := writeSchemaFile(file, documents[name]); err != nil {
+			return written, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeOperationFail, file, messages.ErrorsSchemaWriteFailed, file)
+		}
+		written = append(written, file)
+	}
+	return written, nil
+}
+
+func writeSchemaFile(file string, schema map[string]any) error {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), core.PermReadWriteExec); err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), core.PermReadWrite)
+}
+
+// serviceSchemaModeline is the first line of a service config file, pointing
+// editors at the schema WriteSchemas puts beside the services directory
+func serviceSchemaModeline(serviceName string) string {
+	return fmt.Sprintf(core.SchemaModeline, path.Join("..", core.SchemasDir, core.ServiceConfigsDir, serviceName+core.SchemaFileSuffix))
+}
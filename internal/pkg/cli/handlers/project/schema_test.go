@@ -0,0 +1,66 @@
+//go:build unit
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+)
+
+func newSchemaCommand(dir string) *cobra.Command {
+	cmd := &cobra.Command{Use: core.CommandSchema}
+	cmd.Flags().String(core.FlagDir, dir, "")
+	return cmd
+}
+
+func TestWriteSchemas(t *testing.T) {
+	dir := t.TempDir()
+
+	written, err := WriteSchemas(dir)
+	require.NoError(t, err)
+	assert.Contains(t, written, filepath.Join(dir, core.ConfigSchemaFileName))
+	assert.Contains(t, written, filepath.Join(dir, core.ServiceConfigsDir, "kafka"+core.SchemaFileSuffix))
+
+	data, err := os.ReadFile(filepath.Join(dir, core.ServiceConfigsDir, "kafka"+core.SchemaFileSuffix))
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	assert.Contains(t, schema["properties"], "topics")
+	assert.Equal(t, false, schema["additionalProperties"])
+}
+
+func TestSchemaHandler_Export(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir := filepath.Join("out", "schemas")
+
+	err := NewSchemaHandler().Handle(context.Background(), newSchemaCommand(dir), []string{SchemaActionExport}, &base.BaseCommand{Output: &mockOutput{}})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(dir, core.ConfigSchemaFileName))
+}
+
+func TestSchemaHandler_RequiresExportAction(t *testing.T) {
+	for _, args := range [][]string{nil, {"import"}, {SchemaActionExport, "extra"}} {
+		err := NewSchemaHandler().Handle(context.Background(), newSchemaCommand(t.TempDir()), args, &base.BaseCommand{Output: &mockOutput{}})
+		var appErr *pkgerrors.Error
+		require.ErrorAs(t, err, &appErr, "args %v", args)
+		assert.Equal(t, pkgerrors.FieldArgs, appErr.Context)
+	}
+}
+
+func TestServiceConfigContent_SchemaModeline(t *testing.T) {
+	content := NewConfigManager().generateServiceConfigContent("kafka")
+	firstLine, _, _ := strings.Cut(content, "\n")
+	assert.Equal(t, "# yaml-language-server: $schema=../schemas/services/kafka.schema.json", firstLine)
+}
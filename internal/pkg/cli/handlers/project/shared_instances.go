@@ -0,0 +1,250 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/compose"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// maxPortProbes bounds the search for a free host port
+const maxPortProbes = 100
+
+// fingerprintSettingsKey holds a service's config file settings in its
+// fingerprint
+const fingerprintSettingsKey = "settings"
+
+// portVariable matches a host port given as ${VAR:-default}
+var portVariable = regexp.MustCompile(`^\$\{([A-Za-z_][A-Za-z0-9_]*):-(\d+)\}$`)
+
+// SharedInstanceName is the name a second shared instance of service goes by
+// in the registry, the shared compose file and its container name
+func SharedInstanceName(service, instance string) string {
+	if instance == "" {
+		return service
+	}
+	return service + core.SharedInstanceSeparator + instance
+}
+
+// UseSharedInstances renames the services the project runs as a second
+// shared instance, so they are found under that name in the registry and in
+// Docker. Other services are returned unchanged.
+func UseSharedInstances(serviceConfigs []types.ServiceConfig, sharing *config.SharingConfig) []types.ServiceConfig {
+	if sharing == nil || !sharing.Enabled || len(sharing.Instances) == 0 {
+		return serviceConfigs
+	}
+	renamed := make([]types.ServiceConfig, len(serviceConfigs))
+	for i, svc := range serviceConfigs {
+		if instance := sharing.Instances[svc.Name]; instance != "" && svc.Shareable {
+			svc.Name = SharedInstanceName(svc.Name, instance)
+		}
+		renamed[i] = svc
+	}
+	return renamed
+}
+
+// SharedInstance returns svc as its second instance: renamed, and publishing
+// on host ports that do not clash with the default instance. An instance
+// already in the registry keeps the ports recorded there; a new one gets the
+// first free ports above the service's defaults. The ports are returned keyed
+// by the environment variable that names them.
+func SharedInstance(svc types.ServiceConfig, instance string, reg *registry.Manager) (types.ServiceConfig, map[string]string, error) {
+	name := SharedInstanceName(svc.Name, instance)
+	containers, err := reg.List()
+	if err != nil {
+		return svc, nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	var recorded map[string]string
+	taken := make(map[int]bool)
+	for key, info := range containers {
+		if key == name {
+			recorded = info.Ports
+			continue
+		}
+		for _, port := range info.Ports {
+			if p, err := strconv.Atoi(port); err == nil {
+				taken[p] = true
+			}
+		}
+	}
+
+	ports := make(map[string]string)
+	instancePorts := make([]types.PortSpec, len(svc.Container.Ports))
+	for i, port := range svc.Container.Ports {
+		variable, defaultPort := hostPortDefault(port)
+
+		hostPort, ok := recorded[variable]
+		if !ok {
+			hostPort, err = freePortAbove(defaultPort, taken)
+			if err != nil {
+				return svc, nil, err
+			}
+		}
+		if p, err := strconv.Atoi(hostPort); err == nil {
+			taken[p] = true
+		}
+		port.External = hostPort
+		ports[variable] = hostPort
+		instancePorts[i] = port
+	}
+
+	svc.Name = name
+	svc.Container.Ports = instancePorts
+	return svc, ports, nil
+}
+
+// hostPortDefault returns the variable a port's host port is read from, or
+// its container port when it has none, and the default host port
+func hostPortDefault(port types.PortSpec) (string, string) {
+	if match := portVariable.FindStringSubmatch(port.External); match != nil {
+		return match[1], match[2]
+	}
+	return port.Internal, port.External
+}
+
+// freePortAbove returns the first host port after defaultPort that is
+// neither taken nor in use
+func freePortAbove(defaultPort string, taken map[int]bool) (string, error) {
+	start, err := strconv.Atoi(defaultPort)
+	if err != nil {
+		return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsSharedInstanceNoPort, defaultPort)
+	}
+	if port, ok := firstFreePort(start+1, taken); ok {
+		return strconv.Itoa(port), nil
+	}
+	return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldServiceName, messages.ErrorsSharedInstanceNoPort, defaultPort)
+}
+
+// firstFreePort returns the first host port from start that is neither
+// taken nor in use, looking at up to maxPortProbes ports
+func firstFreePort(start int, taken map[int]bool) (int, bool) {
+	for port := start; port < start+maxPortProbes; port++ {
+		if !taken[port] && !portInUse(port) {
+			return port, true
+		}
+	}
+	return 0, false
+}
+
+// InstanceSuffix picks the suffix of a second shared instance: the tag of the
+// image the project asks for when it differs from the running one, as in
+// postgres-16, or else the project name
+func InstanceSuffix(registeredImage, requestedImage, projectName string) string {
+	if tag := imageTag(requestedImage); tag != "" && tag != imageTag(registeredImage) && tag != "latest" {
+		return sanitizeInstanceSuffix(tag)
+	}
+	return sanitizeInstanceSuffix(projectName)
+}
+
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return ""
+}
+
+// sanitizeInstanceSuffix keeps the characters container and compose service
+// names accept
+func sanitizeInstanceSuffix(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// SharedFingerprint is the effective configuration of a shared service: the
+// compose service the shared compose file holds for it, less otto-stack's
+// own labels and container name, plus the settings from its config file
+func SharedFingerprint(svc types.ServiceConfig) (registry.Fingerprint, error) {
+	generator, err := compose.NewGenerator("shared")
+	if err != nil {
+		return registry.Fingerprint{}, err
+	}
+	definition := generator.ServiceDefinition(svc)
+	if definition == nil {
+		definition = make(map[string]any)
+	}
+	delete(definition, docker.ComposeFieldLabels)
+	delete(definition, docker.ComposeFieldContainerName)
+	if len(svc.Settings) > 0 {
+		definition[fingerprintSettingsKey] = svc.Settings
+	}
+	return registry.NewFingerprint(definition), nil
+}
+
+// SharedComposeServices returns the services the shared compose file
+// defines, or nil when it does not exist
+func SharedComposeServices(sharedRoot string) (map[string]bool, error) {
+	data, err := os.ReadFile(filepath.Join(sharedRoot, core.GeneratedDir, docker.DockerComposeFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var file struct {
+		Services map[string]any `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(file.Services))
+	for name := range file.Services {
+		names[name] = true
+	}
+	return names, nil
+}
+
+// AddSharedServices adds services to the existing shared compose file,
+// replacing any of the same name and keeping the rest
+func AddSharedServices(serviceConfigs []types.ServiceConfig, sharedRoot string) error {
+	composePath := filepath.Join(sharedRoot, core.GeneratedDir, docker.DockerComposeFileName)
+	data, err := os.ReadFile(composePath)
+	if err != nil {
+		return err
+	}
+	var existing map[string]any
+	if err := yaml.Unmarshal(data, &existing); err != nil {
+		return err
+	}
+
+	generator, err := compose.NewGenerator("shared")
+	if err != nil {
+		return err
+	}
+	services, _ := existing[docker.ComposeFieldServices].(map[string]any)
+	if services == nil {
+		services = make(map[string]any)
+	}
+	for _, svc := range serviceConfigs {
+		if definition := generator.ServiceDefinition(svc); definition != nil {
+			services[svc.Name] = definition
+		}
+	}
+	existing[docker.ComposeFieldServices] = services
+
+	content, err := yaml.Marshal(existing)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(composePath, append([]byte(core.ComposeHeaderShared), content...), core.PermReadWrite)
+}
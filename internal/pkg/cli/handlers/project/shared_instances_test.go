@@ -0,0 +1,86 @@
+//go:build unit
+
+package project
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func TestSharedInstanceName(t *testing.T) {
+	assert.Equal(t, "postgres", SharedInstanceName("postgres", ""))
+	assert.Equal(t, "postgres-16", SharedInstanceName("postgres", "16"))
+}
+
+func TestInstanceSuffix(t *testing.T) {
+	tests := []struct {
+		name                  string
+		registered, requested string
+		want                  string
+	}{
+		{"differing tag", "postgres:15-alpine", "postgres:16-alpine", "16-alpine"},
+		{"same tag", "postgres:16", "postgres:16", "shop"},
+		{"latest", "postgres:16", "postgres:latest", "shop"},
+		{"no tag", "postgres:16", "postgres", "shop"},
+		{"registry port", "postgres:16", "localhost:5000/postgres", "shop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, InstanceSuffix(tt.registered, tt.requested, "Shop"))
+		})
+	}
+	assert.Equal(t, "my-app", InstanceSuffix("", "", "My App"))
+}
+
+func TestUseSharedInstances(t *testing.T) {
+	configs := []types.ServiceConfig{
+		{Name: "postgres", Shareable: true},
+		{Name: "redis", Shareable: true},
+		{Name: "app"},
+	}
+
+	assert.Equal(t, configs, UseSharedInstances(configs, nil))
+	assert.Equal(t, configs, UseSharedInstances(configs, &config.SharingConfig{Enabled: false, Instances: map[string]string{"postgres": "16"}}))
+
+	renamed := UseSharedInstances(configs, &config.SharingConfig{Enabled: true, Instances: map[string]string{"postgres": "16", "app": "x"}})
+	assert.Equal(t, "postgres-16", renamed[0].Name)
+	assert.Equal(t, "redis", renamed[1].Name)
+	assert.Equal(t, "app", renamed[2].Name)
+	assert.Equal(t, "postgres", configs[0].Name)
+}
+
+func TestSharedInstance_ReusesRecordedPorts(t *testing.T) {
+	reg := registry.NewManager(t.TempDir())
+	require.NoError(t, reg.RegisterWithSpec("postgres-16", "otto-stack-postgres-16", registry.ProjectRef{Name: "shop"}, &registry.ContainerSpec{
+		Service: "postgres",
+		Ports:   map[string]string{"POSTGRES_PORT": "15432"},
+	}))
+
+	svc := types.ServiceConfig{Name: "postgres", Shareable: true}
+	svc.Container.Ports = []types.PortSpec{{External: "${POSTGRES_PORT:-5432}", Internal: "5432"}}
+
+	instance, ports, err := SharedInstance(svc, "16", reg)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres-16", instance.Name)
+	assert.Equal(t, map[string]string{"POSTGRES_PORT": "15432"}, ports)
+	assert.Equal(t, "15432", instance.Container.Ports[0].External)
+	assert.Equal(t, "${POSTGRES_PORT:-5432}", svc.Container.Ports[0].External)
+}
+
+func TestSharedInstance_AllocatesPortAboveDefault(t *testing.T) {
+	reg := registry.NewManager(t.TempDir())
+	svc := types.ServiceConfig{Name: "postgres", Shareable: true}
+	svc.Container.Ports = []types.PortSpec{{External: "${POSTGRES_PORT:-45432}", Internal: "5432"}}
+
+	instance, ports, err := SharedInstance(svc, "16", reg)
+	require.NoError(t, err)
+	require.Contains(t, ports, "POSTGRES_PORT")
+	assert.NotEqual(t, "45432", ports["POSTGRES_PORT"])
+	assert.Equal(t, ports["POSTGRES_PORT"], instance.Container.Ports[0].External)
+}
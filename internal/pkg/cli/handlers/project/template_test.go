@@ -21,7 +21,7 @@ func TestGenerateEnvFile(t *testing.T) {
 	assert.NoError(t, err)
 
 	serviceConfigs := []types.ServiceConfig{{Name: services.ServicePostgres}}
-	err = handler.projectManager.generateEnvFile(serviceConfigs, TestProjectName, &base.BaseCommand{Output: ui.NewOutput()})
+	err = handler.projectManager.generateEnvFile(serviceConfigs, TestProjectName, nil, &base.BaseCommand{Output: ui.NewOutput()})
 	if err != nil {
 		t.Logf("Expected error in test environment: %v", err)
 	}
@@ -50,7 +50,7 @@ func TestGenerateInitialComposeFiles(t *testing.T) {
 	serviceConfigs := []types.ServiceConfig{{Name: services.ServicePostgres}}
 	baseCmd := &base.BaseCommand{Output: ui.NewOutput()}
 
-	err := handler.projectManager.generateEnvFile(serviceConfigs, TestProjectName, baseCmd)
+	err := handler.projectManager.generateEnvFile(serviceConfigs, TestProjectName, nil, baseCmd)
 	if err != nil {
 		t.Logf("Expected error in test environment: %v", err)
 	}
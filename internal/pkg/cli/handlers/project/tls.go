@@ -0,0 +1,161 @@
+package project
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/certs"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/proxy"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// tlsSetting is the service config file setting that turns TLS on
+const tlsSetting = "tls"
+
+// TLSCertificate is the certificate a project-local service serves TLS with
+type TLSCertificate struct {
+	Service string
+	certs.Certificate
+	// Issued reports whether it was issued just now
+	Issued bool
+}
+
+// IssueTLS makes sure each project-local service whose service config file
+// sets tls: true has a current certificate from the local CA, creating the
+// CA the first time. It returns those certificates, and the shared services
+// that asked for TLS, which keep their shared container's settings.
+func IssueTLS(serviceConfigs []types.ServiceConfig, cfg *config.Config) ([]TLSCertificate, []string, error) {
+	local := make(map[string]bool)
+	for _, svc := range localServices(serviceConfigs, cfg) {
+		local[svc.Name] = true
+	}
+
+	var wanted []types.ServiceConfig
+	var shared []string
+	for _, svc := range serviceConfigs {
+		if !tlsRequested(svc) {
+			continue
+		}
+		if local[svc.Name] {
+			wanted = append(wanted, svc)
+		} else {
+			shared = append(shared, svc.Name)
+		}
+	}
+	if len(wanted) == 0 {
+		return nil, shared, nil
+	}
+
+	caDir, err := certs.DefaultDir()
+	if err != nil {
+		return nil, nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsCaFailed, filepath.Join("~", core.OttoStackDir, core.CADir)), err)
+	}
+	authority, _, err := certs.Open(caDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issued := make([]TLSCertificate, 0, len(wanted))
+	for _, svc := range wanted {
+		hosts := tlsHosts(svc, cfg.Project.Name)
+		cert, fresh, err := authority.Ensure(authority.IssuedDir(hosts[0]), hosts)
+		if err != nil {
+			return nil, nil, err
+		}
+		issued = append(issued, TLSCertificate{Service: svc.Name, Certificate: cert, Issued: fresh})
+	}
+	return issued, shared, nil
+}
+
+// ApplyTLS returns serviceConfigs with the project-local services that serve
+// TLS set up for it: their certificate directory mounted, the entrypoint,
+// command and health check of the catalog's tls entry applied, and its
+// variables added to the env file. A service whose certificate has not been
+// issued yet, by IssueTLS, is left as it is.
+func ApplyTLS(serviceConfigs []types.ServiceConfig, cfg *config.Config) []types.ServiceConfig {
+	if cfg == nil {
+		return serviceConfigs
+	}
+	caDir, err := certs.DefaultDir()
+	if err != nil {
+		return serviceConfigs
+	}
+	local := make(map[string]bool)
+	for _, svc := range localServices(serviceConfigs, cfg) {
+		local[svc.Name] = true
+	}
+
+	applied := slices.Clone(serviceConfigs)
+	for i, svc := range applied {
+		if !local[svc.Name] || !tlsRequested(svc) {
+			continue
+		}
+		name := tlsHosts(svc, cfg.Project.Name)[0]
+		cert := certs.Certificate{Dir: certs.IssuedDir(caDir, name)}
+		if _, err := os.Stat(cert.CertFile()); err != nil {
+			continue
+		}
+		applied[i] = withTLS(svc, cert)
+	}
+	return applied
+}
+
+// withTLS sets svc up to serve TLS with cert as its catalog entry says
+func withTLS(svc types.ServiceConfig, cert certs.Certificate) types.ServiceConfig {
+	spec := svc.TLS
+	svc.Container.Volumes = append(slices.Clone(svc.Container.Volumes), types.VolumeSpec{Name: cert.Dir, Mount: spec.Mount, ReadOnly: true})
+	if len(spec.Entrypoint) > 0 {
+		svc.Container.Entrypoint = spec.Entrypoint
+	}
+	svc.Container.Command = append(slices.Clone(svc.Container.Command), spec.Command...)
+	if len(spec.HealthCheck) > 0 && svc.Container.HealthCheck != nil {
+		check := *svc.Container.HealthCheck
+		check.Test = spec.HealthCheck
+		svc.Container.HealthCheck = &check
+	}
+
+	paths := strings.NewReplacer("{{.CA}}", cert.CAFile(), "{{.Cert}}", cert.CertFile(), "{{.Key}}", cert.KeyFile())
+	env := maps.Clone(svc.AllEnvironment)
+	if env == nil {
+		env = make(map[string]string, len(spec.Environment))
+	}
+	for key, value := range spec.Environment {
+		env[key] = paths.Replace(value)
+	}
+	svc.AllEnvironment = env
+	return svc
+}
+
+// tlsRequested reports whether a service can serve TLS and its service
+// config file asks it to
+func tlsRequested(svc types.ServiceConfig) bool {
+	if svc.TLS == nil {
+		return false
+	}
+	settings, _, err := services.LoadServiceSettings(svc)
+	if err != nil {
+		return false
+	}
+	enabled, _ := settings[tlsSetting].(bool)
+	return enabled
+}
+
+// tlsHosts are the names a project's service is reached by: its proxy
+// hostname, which also names its certificate, its compose service and
+// container names, and the loopback addresses its ports are published on
+func tlsHosts(svc types.ServiceConfig, projectName string) []string {
+	hosts := []string{proxy.Hostname(svc.Name, projectName), svc.Name}
+	if svc.Shareable {
+		hosts = append(hosts, core.SharedContainerPrefix+svc.Name)
+	}
+	return append(hosts, "localhost", "127.0.0.1", "::1")
+}
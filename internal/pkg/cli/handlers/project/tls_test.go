@@ -0,0 +1,88 @@
+//go:build unit
+
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func tlsService(name string) types.ServiceConfig {
+	return types.ServiceConfig{
+		Name:                name,
+		Shareable:           true,
+		ConfigurationSchema: map[string]any{"type": "object", "properties": map[string]any{"tls": map[string]any{"type": "boolean", "default": false}}},
+		AllEnvironment:      map[string]string{"PGHOST": "localhost"},
+		Container: types.ContainerSpec{
+			Command:     []string{"-c", "max_connections=100"},
+			HealthCheck: &types.HealthCheckSpec{Test: []string{"CMD", "pg_isready"}},
+		},
+		TLS: &types.TLSSpec{
+			Mount:       "/otto-stack/tls",
+			Entrypoint:  []string{"sh", "-c", "exec docker-entrypoint.sh \"$$@\"", "sh"},
+			Command:     []string{"-c", "ssl=on"},
+			HealthCheck: []string{"CMD", "pg_isready", "--ssl"},
+			Environment: map[string]string{"PGSSLROOTCERT": "{{.CA}}"},
+		},
+	}
+}
+
+// withTLSProject runs in a project whose service config files ask postgres
+// and redis for TLS, with its own home for the local CA
+func withTLSProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Chdir(t.TempDir())
+	dir := filepath.Join(core.OttoStackDir, core.ServiceConfigsDir)
+	require.NoError(t, os.MkdirAll(dir, core.PermReadWriteExec))
+	for _, name := range []string{"postgres", "redis"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name+core.YMLFileExtension), []byte("tls: true\n"), core.PermReadWrite))
+	}
+}
+
+func TestIssueAndApplyTLS(t *testing.T) {
+	withTLSProject(t)
+	configs := []types.ServiceConfig{tlsService("postgres"), tlsService("redis"), tlsService("mysql")}
+	cfg := &config.Config{
+		Project: config.ProjectConfig{Name: "shop"},
+		Sharing: &config.SharingConfig{Enabled: true, Services: map[string]bool{"redis": true}},
+	}
+
+	// nothing is applied before a certificate is issued
+	assert.Equal(t, configs, ApplyTLS(configs, cfg))
+
+	issued, shared, err := IssueTLS(configs, cfg)
+	require.NoError(t, err)
+	require.Len(t, issued, 1)
+	assert.Equal(t, "postgres", issued[0].Service)
+	assert.True(t, issued[0].Issued)
+	assert.Contains(t, issued[0].Hosts, "postgres.shop.localhost")
+	assert.Contains(t, issued[0].Hosts, "otto-stack-postgres")
+	assert.Equal(t, []string{"redis"}, shared)
+
+	again, _, err := IssueTLS(configs, cfg)
+	require.NoError(t, err)
+	assert.False(t, again[0].Issued)
+
+	applied := ApplyTLS(configs, cfg)
+	postgres := applied[0]
+	assert.Equal(t, []types.VolumeSpec{{Name: issued[0].Dir, Mount: "/otto-stack/tls", ReadOnly: true}}, postgres.Container.Volumes)
+	assert.Equal(t, []string{"-c", "max_connections=100", "-c", "ssl=on"}, postgres.Container.Command)
+	assert.Equal(t, "sh", postgres.Container.Entrypoint[0])
+	assert.Equal(t, []string{"CMD", "pg_isready", "--ssl"}, postgres.Container.HealthCheck.Test)
+	assert.Equal(t, issued[0].CAFile(), postgres.AllEnvironment["PGSSLROOTCERT"])
+	assert.Equal(t, "localhost", postgres.AllEnvironment["PGHOST"])
+
+	// shared and unrequested services, and the resolved configs, are unchanged
+	assert.Equal(t, configs[1:], applied[1:])
+	assert.Empty(t, configs[0].Container.Volumes)
+	assert.NotContains(t, configs[0].AllEnvironment, "PGSSLROOTCERT")
+}
@@ -2,6 +2,7 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -31,49 +32,107 @@ func (h *ValidateHandler) Handle(ctx context.Context, cmd *cobra.Command, args [
 	if err != nil {
 		return err
 	}
-	quiet := ci.GetFlags(cmd).Quiet
+	ciFlags := ci.GetFlags(cmd)
+	jsonOutput := ciFlags.JSON || flags.Format == "json"
+	// Progress lines would corrupt JSON output
+	quiet := ciFlags.Quiet || jsonOutput
 
-	if err := validation.CheckInitialization(); err != nil {
+	issues, err := h.validate(base, flags, quiet)
+	if jsonOutput {
+		return h.outputJSON(base, issues, err)
+	}
+	if err != nil {
 		return err
 	}
 
-	cfg, err := config.LoadConfig()
+	base.Output.Success(messages.SuccessConfigurationValid)
+	return nil
+}
+
+// validate runs the checks in order and stops at the first that fails. Schema
+// issues are returned alongside the error they produce.
+func (h *ValidateHandler) validate(base *base.BaseCommand, flags *core.ValidateFlags, quiet bool) ([]config.Issue, error) {
+	if err := validation.CheckInitialization(); err != nil {
+		return nil, err
+	}
+
+	issues, err := config.ValidateFiles()
 	if err != nil {
-		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentProject, messages.ErrorsConfigLoadFailed, err)
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return issues, config.IssuesError(issues)
 	}
 	if !quiet {
 		base.Output.Success(messages.ValidateCheckConfigSyntax)
+		base.Output.Success(messages.ValidateCheckConfigSchema)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentProject, messages.ErrorsConfigLoadFailed, err)
 	}
 
 	vm := NewValidationManager()
 	if err := vm.ValidateProjectName(cfg.Project.Name); err != nil {
-		return err
+		return nil, err
 	}
 	if !quiet {
 		base.Output.Success(messages.ValidateCheckProjectName)
 	}
 
-	if _, err := services.ResolveUpServices(cfg.Stack.Enabled, cfg); err != nil {
-		return err
+	serviceConfigs, err := services.ResolveUpServices(cfg.Stack.Enabled, cfg)
+	if err != nil {
+		return nil, err
 	}
 	if !quiet {
 		base.Output.Success(messages.ValidateCheckServices)
 	}
 
+	issues, err = services.ValidateServiceConfigFiles(serviceConfigs)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return issues, config.IssuesError(issues)
+	}
+	if !quiet {
+		base.Output.Success(messages.ValidateCheckServiceConfigs)
+	}
+
 	if flags.Strict {
 		if !isCommandAvailable(docker.DockerCmd) {
-			return pkgerrors.NewSystemError(pkgerrors.ErrCodeInvalid, messages.ValidateStrictDockerUnavailable, nil)
+			return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeInvalid, messages.ValidateStrictDockerUnavailable, nil)
 		}
 		if !quiet {
 			base.Output.Success(messages.ValidateCheckDocker)
 		}
 
-		if _, err := os.Stat(".git"); os.IsNotExist(err) {
+		if _, err := os.Stat(".git"); os.IsNotExist(err) && !quiet {
 			base.Output.Warning("%s", messages.WarningsNotGitRepository)
 		}
 	}
+	return nil, nil
+}
 
-	base.Output.Success(messages.SuccessConfigurationValid)
+// outputJSON writes the validation result as JSON and exits non-zero when
+// validation failed
+func (h *ValidateHandler) outputJSON(base *base.BaseCommand, issues []config.Issue, err error) error {
+	output := ci.ValidateOutput{
+		Valid:  err == nil,
+		Issues: make([]any, len(issues)),
+		Count:  len(issues),
+	}
+	for i, issue := range issues {
+		output.Issues[i] = issue
+	}
+	if err != nil && len(issues) == 0 {
+		output.Error = err.Error()
+	}
+	_ = json.NewEncoder(base.Output.Writer()).Encode(output)
+	if err != nil {
+		return pkgerrors.ErrSilentExit
+	}
 	return nil
 }
 
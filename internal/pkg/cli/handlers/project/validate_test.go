@@ -0,0 +1,83 @@
+//go:build unit
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/ci"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+)
+
+// bufferOutput captures what a handler writes to the output writer
+type bufferOutput struct {
+	mockOutput
+	buf bytes.Buffer
+}
+
+func (o *bufferOutput) Writer() io.Writer { return &o.buf }
+
+func newValidateCommand(format string) *cobra.Command {
+	cmd := &cobra.Command{Use: core.CommandValidate}
+	cmd.Flags().Bool(core.FlagStrict, false, "")
+	cmd.Flags().String(core.FlagFormat, format, "")
+	return cmd
+}
+
+func writeProjectConfig(t *testing.T, content string) {
+	t.Helper()
+	require.NoError(t, os.Mkdir(core.OttoStackDir, core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(filepath.Join(core.OttoStackDir, core.ConfigFileName), []byte(content), core.PermReadWrite))
+}
+
+func TestValidateHandler_ReportsSchemaIssuesAsJSON(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeProjectConfig(t, "project:\n  name: app\nshareing:\n  enabled: true\n")
+
+	output := &bufferOutput{}
+	err := NewValidateHandler().Handle(context.Background(), newValidateCommand("json"), nil, &base.BaseCommand{Output: output})
+	assert.ErrorIs(t, err, pkgerrors.ErrSilentExit)
+
+	var result struct {
+		ci.ValidateOutput
+		Issues []map[string]any `json:"issues"`
+	}
+	require.NoError(t, json.Unmarshal(output.buf.Bytes(), &result))
+	assert.False(t, result.Valid)
+	require.Len(t, result.Issues, 1)
+	assert.Equal(t, "sharing", result.Issues[0]["suggestion"])
+	assert.EqualValues(t, 3, result.Issues[0]["line"])
+}
+
+func TestValidateHandler_SchemaIssuesFailInText(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeProjectConfig(t, "project:\n  name: app\nstack:\n  enable: [postgres]\n")
+
+	err := NewValidateHandler().Handle(context.Background(), newValidateCommand("text"), nil, &base.BaseCommand{Output: &mockOutput{}})
+	require.Error(t, err)
+	assert.Contains(t, pkgerrors.OutputOf(err), `:4:3: unknown field stack.enable (did you mean "enabled"?)`)
+}
+
+func TestValidateHandler_ReportsServiceConfigIssues(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeProjectConfig(t, "project:\n  name: app\nstack:\n  enabled: [postgres]\n")
+	require.NoError(t, os.Mkdir(filepath.Join(core.OttoStackDir, core.ServiceConfigsDir), core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(services.ServiceConfigFilePath("postgres"), []byte("name: postgres\nschemas:\n  - nme: app\n"), core.PermReadWrite))
+
+	err := NewValidateHandler().Handle(context.Background(), newValidateCommand("text"), nil, &base.BaseCommand{Output: &mockOutput{}})
+	require.Error(t, err)
+	assert.Contains(t, pkgerrors.OutputOf(err), "postgres.yml:3:5: unknown field schemas[0].nme")
+}
@@ -0,0 +1,117 @@
+package utility
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/certs"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// CertsHandler handles the certs command
+type CertsHandler struct{}
+
+// NewCertsHandler creates a new certs handler
+func NewCertsHandler() *CertsHandler {
+	return &CertsHandler{}
+}
+
+// Handle executes the certs command
+func (h *CertsHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	if err := h.ValidateArgs(args); err != nil {
+		return err
+	}
+	authority, err := h.open(base)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return h.list(authority, base)
+	}
+	return h.issue(authority, args, base)
+}
+
+// open opens the local CA, creating it the first time
+func (h *CertsHandler) open(base *base.BaseCommand) (*certs.Authority, error) {
+	caDir, err := certs.DefaultDir()
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsCertsCaFailed, filepath.Join("~", core.OttoStackDir, core.CADir)), err)
+	}
+	authority, created, err := certs.Open(caDir)
+	if err != nil {
+		return nil, err
+	}
+	if created {
+		base.Output.Success(messages.CertsCaCreated, caDir)
+	}
+	return authority, nil
+}
+
+// list prints the CA certificate and the certificates it issued
+func (h *CertsHandler) list(authority *certs.Authority, base *base.BaseCommand) error {
+	issued, err := authority.List()
+	if err != nil {
+		return err
+	}
+
+	base.Output.Header("%s", messages.CertsHeader)
+	base.Output.Info(messages.CertsCa, authority.CertFile(), authority.NotAfter().Format(time.DateOnly))
+	if len(issued) == 0 {
+		base.Output.Info("%s", messages.CertsNone)
+	} else {
+		display.RenderTable(base.Output.Writer(), []string{display.HeaderHostname, display.HeaderHosts, display.HeaderExpires}, certRows(issued))
+	}
+	base.Output.Muted("%s", messages.CertsTrustHint)
+	return nil
+}
+
+// issue makes sure there is a current certificate for hosts, named after
+// the first
+func (h *CertsHandler) issue(authority *certs.Authority, hosts []string, base *base.BaseCommand) error {
+	cert, issued, err := authority.Ensure(authority.IssuedDir(hosts[0]), hosts)
+	if err != nil {
+		return err
+	}
+	if issued {
+		base.Output.Success(messages.CertsIssued, strings.Join(hosts, ", "))
+	} else {
+		base.Output.Info(messages.CertsCurrent, strings.Join(hosts, ", "))
+	}
+	base.Output.Info(messages.CertsCertFile, cert.CertFile())
+	base.Output.Info(messages.CertsKeyFile, cert.KeyFile())
+	base.Output.Info(messages.CertsCaFile, cert.CAFile())
+	return nil
+}
+
+// certRows returns a name, hosts and expiry row for each certificate
+func certRows(issued []certs.Certificate) [][]string {
+	rows := make([][]string, 0, len(issued))
+	for _, cert := range issued {
+		rows = append(rows, []string{filepath.Base(cert.Dir), strings.Join(cert.Hosts, ", "), cert.NotAfter.Format(time.DateOnly)})
+	}
+	return rows
+}
+
+// ValidateArgs validates the command arguments
+func (h *CertsHandler) ValidateArgs(args []string) error {
+	for _, host := range args {
+		if !certs.ValidHost(host) {
+			return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ValidationCertsHostInvalid, host)
+		}
+	}
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *CertsHandler) GetRequiredFlags() []string {
+	return []string{}
+}
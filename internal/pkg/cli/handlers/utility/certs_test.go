@@ -0,0 +1,29 @@
+//go:build unit
+
+package utility
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/certs"
+)
+
+func TestCertsHandler_ValidateArgs(t *testing.T) {
+	handler := NewCertsHandler()
+	assert.NoError(t, handler.ValidateArgs(nil))
+	assert.NoError(t, handler.ValidateArgs([]string{"api.shop.localhost", "127.0.0.1"}))
+	assert.Error(t, handler.ValidateArgs([]string{"api.shop.localhost", "not a host"}))
+}
+
+func TestCertRows(t *testing.T) {
+	expires := time.Date(2028, 1, 20, 0, 0, 0, 0, time.UTC)
+	rows := certRows([]certs.Certificate{{
+		Dir:      "/home/me/.otto-stack/ca/issued/postgres.shop.localhost",
+		Hosts:    []string{"postgres.shop.localhost", "127.0.0.1"},
+		NotAfter: expires,
+	}})
+	assert.Equal(t, [][]string{{"postgres.shop.localhost", "postgres.shop.localhost, 127.0.0.1", "2028-01-20"}}, rows)
+}
@@ -0,0 +1,145 @@
+package utility
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/base"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/display"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/proxy"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// ProxyHandler handles the proxy command
+type ProxyHandler struct{}
+
+// NewProxyHandler creates a new proxy handler
+func NewProxyHandler() *ProxyHandler {
+	return &ProxyHandler{}
+}
+
+// Handle executes the proxy command
+func (h *ProxyHandler) Handle(ctx context.Context, cmd *cobra.Command, args []string, base *base.BaseCommand) error {
+	flags, err := core.ParseProxyFlags(cmd)
+	if err != nil {
+		return pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldFlags, messages.ValidationFailedParseFlags, err)
+	}
+	port, err := h.port(flags.Port)
+	if err != nil {
+		return err
+	}
+
+	manager, err := services.New()
+	if err != nil {
+		return err
+	}
+	catalog := manager.GetAllServices()
+	sharedRoot, err := common.SharedRoot()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryGetFailed, err)
+	}
+	client, err := docker.NewClient(nil)
+	if err != nil {
+		return pkgerrors.NewDockerError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDockerClientCreateFailed, err)
+	}
+	defer func() { _ = client.Close() }()
+
+	addr := proxy.Addr(port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeUnavailable, fmt.Sprintf(messages.ErrorsProxyListenFailed, addr), err)
+	}
+
+	base.Output.Header("%s", messages.ProxyHeader)
+	h.printRoutes(catalog, port, base)
+	base.Output.Info(messages.ProxyListening, addr)
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := proxy.Serve(ctx, listener, proxy.NewDockerResolver(client, catalog, sharedRoot)); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsProxyListenFailed, addr), err)
+	}
+	base.Output.Info("%s", messages.ProxyStopped)
+	return nil
+}
+
+// port returns the port to listen on: the one given, which is remembered,
+// or the one remembered
+func (h *ProxyHandler) port(requested int) (int, error) {
+	home, err := common.OttoStackHome()
+	if err != nil {
+		return 0, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProxySettingsLoadFailed, err)
+	}
+	settings, err := proxy.LoadSettings(home)
+	if err != nil {
+		return 0, err
+	}
+	if requested == 0 || requested == settings.Port {
+		return settings.Port, nil
+	}
+	settings.Port = requested
+	return requested, proxy.SaveSettings(home, settings)
+}
+
+// printRoutes lists the hostnames of the indexed projects' services that
+// have a web interface. The index is a convenience, so it is skipped when it
+// cannot be read.
+func (h *ProxyHandler) printRoutes(catalog map[string]types.ServiceConfig, port int, base *base.BaseCommand) {
+	index, err := common.ProjectIndex()
+	if err != nil {
+		return
+	}
+	entries, err := index.List()
+	if err != nil {
+		return
+	}
+
+	rows := proxyRoutes(entries, catalog, port)
+	if len(rows) == 0 {
+		base.Output.Info("%s", messages.ProxyNoRoutes)
+		return
+	}
+	display.RenderTable(base.Output.Writer(), []string{display.HeaderHostname, display.HeaderService, display.HeaderProject}, rows)
+}
+
+// proxyRoutes returns a hostname, service and project row for each indexed
+// project's service with a web interface
+func proxyRoutes(entries []*registry.ProjectEntry, catalog map[string]types.ServiceConfig, port int) [][]string {
+	var rows [][]string
+	for _, entry := range entries {
+		for _, name := range entry.Services {
+			svc, ok := catalog[name]
+			if !ok {
+				continue
+			}
+			if _, ok := proxy.WebPort(svc); !ok {
+				continue
+			}
+			rows = append(rows, []string{proxy.URL(name, entry.Name, port, ""), name, entry.Name})
+		}
+	}
+	return rows
+}
+
+// ValidateArgs validates the command arguments
+func (h *ProxyHandler) ValidateArgs(args []string) error {
+	return nil
+}
+
+// GetRequiredFlags returns required flags for this command
+func (h *ProxyHandler) GetRequiredFlags() []string {
+	return []string{}
+}
@@ -0,0 +1,26 @@
+//go:build unit
+
+package utility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func TestProxyRoutes(t *testing.T) {
+	catalog := map[string]types.ServiceConfig{
+		"jaeger":   {Name: "jaeger", Documentation: types.DocumentationSpec{WebInterfaces: []types.WebInterface{{Name: "Jaeger UI", Port: 16686}}}},
+		"postgres": {Name: "postgres"},
+	}
+	entries := []*registry.ProjectEntry{
+		{Name: "shop", Services: []string{"postgres", "jaeger"}},
+		{Name: "billing", Services: []string{"postgres", "retired"}},
+	}
+
+	rows := proxyRoutes(entries, catalog, 8780)
+	assert.Equal(t, [][]string{{"http://jaeger.shop.localhost:8780", "jaeger", "shop"}}, rows)
+}
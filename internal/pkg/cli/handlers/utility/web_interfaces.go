@@ -3,7 +3,10 @@ package utility
 import (
 	"context"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
 	"time"
 
@@ -12,9 +15,11 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/base"
 	"github.com/otto-nation/otto-stack/internal/pkg/ci"
 	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/common"
+	"github.com/otto-nation/otto-stack/internal/pkg/cli/handlers/project"
 	"github.com/otto-nation/otto-stack/internal/pkg/display"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/proxy"
 	"github.com/otto-nation/otto-stack/internal/pkg/services"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 	"github.com/otto-nation/otto-stack/internal/pkg/ui"
@@ -57,6 +62,7 @@ func (h *WebInterfacesHandler) Handle(ctx context.Context, cmd *cobra.Command, a
 	if err != nil {
 		return ci.FormatError(ciFlags, err)
 	}
+	serviceConfigs = project.ApplyPorts(serviceConfigs, setup.Config)
 
 	interfaces, err := h.collectInterfaces(ctx, setup, serviceConfigs, flags.All)
 	if err != nil {
@@ -75,6 +81,7 @@ func (h *WebInterfacesHandler) collectInterfaces(ctx context.Context, setup *com
 	}
 
 	interfaces := h.extractWebInterfaces(serviceConfigs, runningServices, showAll)
+	h.locate(interfaces, serviceConfigs, setup.Config.Project.Name, proxyPort())
 	h.checkAvailabilityConcurrent(interfaces)
 	return interfaces, nil
 }
@@ -133,11 +140,56 @@ func (h *WebInterfacesHandler) createWebInterfaces(serviceName string, webInterf
 			Name:        webIface.Name,
 			URL:         webIface.URL,
 			Description: webIface.Description,
+			port:        webIface.Port,
 		}
 	}
 	return interfaces
 }
 
+// locate points the URL of each interface with a container port at the host
+// port its service publishes it on, and gives the interface the proxy serves
+// for its service its hostname
+func (h *WebInterfacesHandler) locate(interfaces []WebInterface, serviceConfigs []types.ServiceConfig, projectName string, proxyPort int) {
+	byName := make(map[string]types.ServiceConfig, len(serviceConfigs))
+	for _, svc := range serviceConfigs {
+		byName[svc.Name] = svc
+	}
+
+	for i := range interfaces {
+		iface := &interfaces[i]
+		svc, ok := byName[iface.Service]
+		if !ok || iface.port == 0 {
+			continue
+		}
+		if published, ok := project.PublishedPort(svc, iface.port); ok {
+			iface.URL = withPort(iface.URL, published)
+		}
+		if webPort, ok := proxy.WebPort(svc); ok && webPort == iface.port {
+			iface.Hostname = proxy.URL(iface.Service, projectName, proxyPort, iface.URL)
+		}
+	}
+}
+
+// withPort returns rawURL on port instead of its own
+func withPort(rawURL string, port int) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Port() == "" {
+		return rawURL
+	}
+	parsed.Host = net.JoinHostPort(parsed.Hostname(), strconv.Itoa(port))
+	return parsed.String()
+}
+
+// proxyPort is the port the proxy serves hostnames on, as last run
+func proxyPort() int {
+	home, err := common.OttoStackHome()
+	if err != nil {
+		return proxy.DefaultPort
+	}
+	settings, _ := proxy.LoadSettings(home)
+	return settings.Port
+}
+
 // checkAvailabilityConcurrent performs HTTP checks for all interfaces in parallel.
 func (h *WebInterfacesHandler) checkAvailabilityConcurrent(interfaces []WebInterface) {
 	var wg sync.WaitGroup
@@ -199,11 +251,15 @@ func (h *WebInterfacesHandler) outputJSON(interfaces []WebInterface, ciFlags ci.
 
 // printTable prints interfaces in table format
 func (h *WebInterfacesHandler) printTable(interfaces []WebInterface, writer io.Writer) {
-	headers := []string{display.HeaderService, display.HeaderInterface, display.HeaderURL, display.HeaderStatus}
+	headers := []string{display.HeaderService, display.HeaderInterface, display.HeaderURL, display.HeaderHostname, display.HeaderStatus}
 	rows := make([][]string, len(interfaces))
 
 	for i, iface := range interfaces {
-		rows[i] = []string{iface.Service, iface.Name, iface.URL, h.formatStatus(iface.Available)}
+		hostname := iface.Hostname
+		if hostname == "" {
+			hostname = display.NotApplicable
+		}
+		rows[i] = []string{iface.Service, iface.Name, iface.URL, hostname, h.formatStatus(iface.Available)}
 	}
 
 	display.RenderTable(writer, headers, rows)
@@ -218,11 +274,16 @@ func (h *WebInterfacesHandler) formatStatus(available bool) string {
 
 // WebInterface represents a service web interface
 type WebInterface struct {
-	Service     string `json:"service"`
-	Name        string `json:"name"`
-	URL         string `json:"url"`
+	Service string `json:"service"`
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	// Hostname is the interface's URL through the proxy, if it serves it
+	Hostname    string `json:"hostname,omitempty"`
 	Description string `json:"description"`
 	Available   bool   `json:"available"`
+
+	// port is the container port serving the interface, if known
+	port int
 }
 
 // ValidateArgs validates the command arguments
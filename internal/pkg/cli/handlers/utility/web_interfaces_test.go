@@ -110,3 +110,22 @@ func TestWebInterfacesHandler_ValidateArgs(t *testing.T) {
 	err = handler.ValidateArgs([]string{services.ServicePostgres, services.ServiceRedis})
 	assert.NoError(t, err)
 }
+
+func TestWebInterfacesHandler_locate(t *testing.T) {
+	handler := NewWebInterfacesHandler()
+
+	jaeger := fixtures.NewServiceConfig("jaeger").Build()
+	jaeger.Container.Ports = []types.PortSpec{{External: "${JAEGER_UI_PORT:-16786}", Internal: "16686"}}
+	jaeger.Documentation.WebInterfaces = []types.WebInterface{
+		{Name: "Jaeger UI", URL: "http://localhost:16686/search", Port: 16686},
+		{Name: "Docs", URL: "https://www.jaegertracing.io/docs/"},
+	}
+
+	interfaces := handler.createWebInterfaces(jaeger.Name, jaeger.Documentation.WebInterfaces)
+	handler.locate(interfaces, []types.ServiceConfig{jaeger}, "shop", 8780)
+
+	assert.Equal(t, "http://localhost:16786/search", interfaces[0].URL)
+	assert.Equal(t, "http://jaeger.shop.localhost:8780/search", interfaces[0].Hostname)
+	assert.Equal(t, "https://www.jaegertracing.io/docs/", interfaces[1].URL)
+	assert.Empty(t, interfaces[1].Hostname)
+}
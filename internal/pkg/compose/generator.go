@@ -23,7 +23,9 @@ const expectedEnvParts = 2
 // Generator handles docker-compose file generation
 type Generator struct {
 	projectName string
-	logger      *slog.Logger
+	// attached are the projects whose networks every service also joins
+	attached []string
+	logger   *slog.Logger
 }
 
 // NewGenerator creates a new compose generator
@@ -34,6 +36,12 @@ func NewGenerator(projectName string) (*Generator, error) {
 	}, nil
 }
 
+// AttachProjects makes every service also join the networks of projects,
+// which compose expects to exist already
+func (g *Generator) AttachProjects(projects []string) {
+	g.attached = projects
+}
+
 // buildComposeStructure creates the compose structure from ServiceConfigs
 func (g *Generator) buildComposeStructure(serviceConfigs []types.ServiceConfig) (map[string]any, error) {
 	if g.projectName == "" {
@@ -45,18 +53,75 @@ func (g *Generator) buildComposeStructure(serviceConfigs []types.ServiceConfig)
 		return nil, err
 	}
 
-	return map[string]any{
-		docker.ComposeFieldServices: services,
-		docker.ComposeFieldNetworks: map[string]any{
-			docker.DefaultNetworkName: map[string]any{
-				docker.ComposeFieldName: g.projectName + docker.NetworkNameSuffix,
-				docker.ComposeFieldLabels: map[string]string{
-					docker.LabelOttoManaged: "true",
-					docker.LabelOttoProject: g.projectName,
-				},
+	networks := map[string]any{
+		docker.DefaultNetworkName: map[string]any{
+			docker.ComposeFieldName: docker.ProjectNetworkName(g.projectName),
+			docker.ComposeFieldLabels: map[string]string{
+				docker.LabelOttoManaged: "true",
+				docker.LabelOttoProject: g.projectName,
 			},
 		},
-	}, nil
+	}
+	g.attachNetworks(services, networks)
+
+	structure := map[string]any{
+		docker.ComposeFieldServices: services,
+		docker.ComposeFieldNetworks: networks,
+	}
+	if volumes := g.buildNamedVolumes(serviceConfigs); len(volumes) > 0 {
+		structure[docker.ComposeFieldVolumes] = volumes
+	}
+	return structure, nil
+}
+
+// attachNetworks declares the attached projects' networks as external ones,
+// keyed by network name so that no project name can shadow the default, and
+// puts every service on them as well as the default
+func (g *Generator) attachNetworks(services, networks map[string]any) {
+	if len(g.attached) == 0 {
+		return
+	}
+	serviceNetworks := []string{docker.DefaultNetworkName}
+	for _, project := range g.attached {
+		name := docker.ProjectNetworkName(project)
+		networks[name] = map[string]any{
+			docker.ComposeFieldName:     name,
+			docker.ComposeFieldExternal: true,
+		}
+		serviceNetworks = append(serviceNetworks, name)
+	}
+	for _, service := range services {
+		if fields, ok := service.(map[string]any); ok {
+			fields[docker.ComposeFieldNetworks] = serviceNetworks
+		}
+	}
+}
+
+// buildNamedVolumes declares the named volumes services mount, which compose
+// requires at the top level. External volumes keep their own name instead of
+// getting the project prefix.
+func (g *Generator) buildNamedVolumes(serviceConfigs []types.ServiceConfig) map[string]any {
+	volumes := make(map[string]any)
+	for _, config := range serviceConfigs {
+		for _, vol := range config.Container.Volumes {
+			if !isNamedVolume(vol.Name) {
+				continue
+			}
+			if vol.External {
+				volumes[vol.Name] = map[string]any{docker.ComposeFieldExternal: true}
+			} else if _, declared := volumes[vol.Name]; !declared {
+				volumes[vol.Name] = map[string]any{}
+			}
+		}
+	}
+	return volumes
+}
+
+// isNamedVolume reports whether a volume source names a Docker volume rather
+// than a host path
+func isNamedVolume(source string) bool {
+	return source != "" && !strings.ContainsAny(source, `/\`) &&
+		!strings.HasPrefix(source, ".") && !strings.HasPrefix(source, "~") && !strings.HasPrefix(source, "$")
 }
 
 // buildServicesFromConfigs creates the services section from ServiceConfigs
@@ -125,6 +190,12 @@ func (g *Generator) buildService(config *types.ServiceConfig) map[string]any {
 	return service
 }
 
+// ServiceDefinition returns the compose service the generator writes for
+// config, or nil for a service without an image
+func (g *Generator) ServiceDefinition(config types.ServiceConfig) map[string]any {
+	return g.buildService(&config)
+}
+
 // createBaseService creates the base service configuration
 func (g *Generator) createBaseService(config *types.ServiceConfig) map[string]any {
 	service := map[string]any{
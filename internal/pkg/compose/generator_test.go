@@ -212,3 +212,45 @@ func TestGenerator_GenerateFromServiceConfigs(t *testing.T) {
 
 	_ = gen.GenerateFromServiceConfigs(configs, "test-project")
 }
+
+func TestGenerator_DeclaresNamedVolumes(t *testing.T) {
+	gen, err := NewGenerator("shop")
+	require.NoError(t, err)
+
+	svc := fixtures.NewServiceConfig("postgres").WithImage("postgres:16").Build()
+	svc.Container.Volumes = []types.VolumeSpec{
+		{Name: "shop_postgres_data", Mount: "/var/lib/postgresql/data", External: true},
+		{Name: "cache", Mount: "/cache"},
+		{Name: "./init", Mount: "/docker-entrypoint-initdb.d", ReadOnly: true},
+	}
+
+	structure, err := gen.buildComposeStructure([]types.ServiceConfig{svc})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"shop_postgres_data": map[string]any{"external": true},
+		"cache":              map[string]any{},
+	}, structure["volumes"])
+
+	structure, err = gen.buildComposeStructure([]types.ServiceConfig{fixtures.NewServiceConfig("redis").WithImage("redis:7").Build()})
+	require.NoError(t, err)
+	assert.NotContains(t, structure, "volumes")
+}
+
+func TestGenerator_AttachProjects(t *testing.T) {
+	gen, err := NewGenerator("shop")
+	require.NoError(t, err)
+	svc := fixtures.NewServiceConfig("redis").WithImage("redis:7").Build()
+
+	structure, err := gen.buildComposeStructure([]types.ServiceConfig{svc})
+	require.NoError(t, err)
+	assert.NotContains(t, structure["services"].(map[string]any)["redis"], "networks")
+
+	gen.AttachProjects([]string{"billing", "default"})
+	structure, err = gen.buildComposeStructure([]types.ServiceConfig{svc})
+	require.NoError(t, err)
+	networks := structure["networks"].(map[string]any)
+	assert.Equal(t, map[string]any{"name": "billing-network", "external": true}, networks["billing-network"])
+	assert.Equal(t, map[string]any{"name": "default-network", "external": true}, networks["default-network"])
+	assert.Equal(t, "shop-network", networks["default"].(map[string]any)["name"])
+	assert.Equal(t, []string{"default", "billing-network", "default-network"}, structure["services"].(map[string]any)["redis"].(map[string]any)["networks"])
+}
@@ -1,8 +1,13 @@
 package config
 
 import (
+	"fmt"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
 
 	goversion "github.com/hashicorp/go-version"
 	embeddedconfig "github.com/otto-nation/otto-stack/internal/config"
@@ -74,6 +79,9 @@ func LoadConfig() (*Config, error) {
 		if err := validateSharingPolicy(baseConfig); err != nil {
 			return nil, err
 		}
+		if err := validateNetworks(baseConfig); err != nil {
+			return nil, err
+		}
 		if err := validateRequiredVersion(baseConfig); err != nil {
 			return nil, err
 		}
@@ -84,6 +92,9 @@ func LoadConfig() (*Config, error) {
 	if err := validateSharingPolicy(merged); err != nil {
 		return nil, err
 	}
+	if err := validateNetworks(merged); err != nil {
+		return nil, err
+	}
 	if err := validateRequiredVersion(merged); err != nil {
 		return nil, err
 	}
@@ -138,8 +149,9 @@ func GenerateConfig(ctx clicontext.Context) ([]byte, error) {
 
 	if ctx.Sharing != nil {
 		config.Sharing = &SharingConfig{
-			Enabled:  ctx.Sharing.Enabled,
-			Services: ctx.Sharing.Services,
+			Enabled:   ctx.Sharing.Enabled,
+			Services:  ctx.Sharing.Services,
+			Instances: ctx.Sharing.Instances,
 		}
 	}
 
@@ -147,7 +159,12 @@ func GenerateConfig(ctx clicontext.Context) ([]byte, error) {
 		config.Advanced = &AdvancedConfig{AutoStart: true}
 	}
 
-	return yaml.Marshal(config)
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	modeline := fmt.Sprintf(core.SchemaModeline, path.Join(core.SchemasDir, core.ConfigSchemaFileName))
+	return append([]byte(modeline), data...), nil
 }
 
 // getConfigPath returns the path to the main config file
@@ -219,16 +236,100 @@ func mergeConfigs(base, local *Config) *Config {
 		merged.Advanced = local.Advanced
 	}
 
+	if local.Logs != nil {
+		merged.Logs = local.Logs
+	}
+
 	if local.Version != nil {
 		merged.Version = local.Version
 	}
 
+	if local.Volumes != nil {
+		merged.Volumes = local.Volumes
+	}
+
+	if local.Ports != nil {
+		merged.Ports = mergePorts(base.Ports, local.Ports)
+	}
+
+	if local.Networks != nil {
+		merged.Networks = local.Networks
+	}
+
 	return &merged
 }
 
-// validateSharingPolicy validates that shared services are marked as shareable
+// mergePorts merges port settings field by field, as otto-stack records the
+// ports it assigns in config.local.yaml next to settings from config.yaml
+func mergePorts(base, local *PortsConfig) *PortsConfig {
+	merged := &PortsConfig{}
+	if base != nil {
+		*merged = *base
+	}
+	if local.Offset != 0 {
+		merged.Offset = local.Offset
+	}
+	if local.Auto {
+		merged.Auto = true
+	}
+	if len(local.Assigned) > 0 {
+		assigned := make(map[string]map[string]int, len(merged.Assigned)+len(local.Assigned))
+		for service, ports := range merged.Assigned {
+			assigned[service] = maps.Clone(ports)
+		}
+		for service, ports := range local.Assigned {
+			if assigned[service] == nil {
+				assigned[service] = make(map[string]int, len(ports))
+			}
+			maps.Copy(assigned[service], ports)
+		}
+		merged.Assigned = assigned
+	}
+	return merged
+}
+
+// validateNetworks checks that networks.attach names other projects
+func validateNetworks(cfg *Config) error {
+	for _, project := range AttachedProjects(cfg) {
+		if strings.TrimSpace(project) == "" || project == cfg.Project.Name {
+			return pkgerrors.NewValidationErrorf(
+				pkgerrors.ErrCodeInvalid,
+				pkgerrors.FieldNetworksAttach,
+				messages.ValidationNetworksAttachInvalid,
+				project,
+			)
+		}
+	}
+	return nil
+}
+
+// validateSharingPolicy validates the conflict policy, the idle check and that
+// shared services are marked as shareable
 func validateSharingPolicy(cfg *Config) error {
-	if cfg.Sharing == nil || !cfg.Sharing.Enabled || len(cfg.Sharing.Services) == 0 {
+	if cfg.Sharing == nil {
+		return nil
+	}
+	switch cfg.Sharing.OnConflict {
+	case "", core.SharingOnConflictWarn, core.SharingOnConflictFail:
+	default:
+		return pkgerrors.NewValidationErrorf(
+			pkgerrors.ErrCodeInvalid,
+			"sharing.on_conflict",
+			messages.ValidationSharingOnConflictInvalid,
+			cfg.Sharing.OnConflict,
+		)
+	}
+	if cfg.Sharing.IdleCheck != "" {
+		if window, err := time.ParseDuration(cfg.Sharing.IdleCheck); err != nil || window <= 0 {
+			return pkgerrors.NewValidationErrorf(
+				pkgerrors.ErrCodeInvalid,
+				"sharing.idle_check",
+				messages.ValidationSharingIdleCheckInvalid,
+				cfg.Sharing.IdleCheck,
+			)
+		}
+	}
+	if !cfg.Sharing.Enabled || len(cfg.Sharing.Services) == 0 {
 		return nil
 	}
 
@@ -251,6 +251,35 @@ func TestMergeConfigs_EdgeCases(t *testing.T) {
 		result := mergeConfigs(base, local)
 		assert.Equal(t, []string{"redis", "mysql"}, result.Stack.Enabled)
 	})
+
+	t.Run("merges ports field by field", func(t *testing.T) {
+		base := &Config{Ports: &PortsConfig{Auto: true, Assigned: map[string]map[string]int{
+			"postgres": {"POSTGRES_PORT": 5433},
+			"redis":    {"REDIS_PORT": 6380},
+		}}}
+		local := &Config{Ports: &PortsConfig{Assigned: map[string]map[string]int{
+			"postgres": {"POSTGRES_PORT": 5434},
+		}}}
+
+		result := mergeConfigs(base, local)
+		require.NotNil(t, result.Ports)
+		assert.True(t, result.Ports.Auto)
+		assert.Equal(t, map[string]map[string]int{
+			"postgres": {"POSTGRES_PORT": 5434},
+			"redis":    {"REDIS_PORT": 6380},
+		}, result.Ports.Assigned)
+		assert.Equal(t, 5433, base.Ports.Assigned["postgres"]["POSTGRES_PORT"])
+	})
+
+	t.Run("overrides logs when local has logs", func(t *testing.T) {
+		base := &Config{Project: ProjectConfig{Name: "test"}}
+		local := &Config{Logs: &LogsConfig{Record: true, MaxFiles: 2}}
+
+		result := mergeConfigs(base, local)
+		require.NotNil(t, result.Logs)
+		assert.True(t, result.Logs.Record)
+		assert.Equal(t, 2, result.Logs.MaxFiles)
+	})
 }
 
 func TestGenerateConfig_ErrorCases(t *testing.T) {
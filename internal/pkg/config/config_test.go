@@ -195,3 +195,32 @@ func TestValidateSharingPolicy_SkipsUnknownServices(t *testing.T) {
 	err := validateSharingPolicy(cfg)
 	assert.NoError(t, err)
 }
+
+func TestValidateSharingPolicy_OnConflict(t *testing.T) {
+	for _, policy := range []string{"", "warn", "fail"} {
+		assert.NoError(t, validateSharingPolicy(&Config{Sharing: &SharingConfig{Enabled: true, OnConflict: policy}}), policy)
+	}
+	assert.Error(t, validateSharingPolicy(&Config{Sharing: &SharingConfig{Enabled: true, OnConflict: "ignore"}}))
+}
+
+func TestValidateSharingPolicy_IdleCheck(t *testing.T) {
+	assert.NoError(t, validateSharingPolicy(&Config{Sharing: &SharingConfig{Enabled: true, IdleCheck: "72h"}}))
+	for _, window := range []string{"3d", "-1h", "0s"} {
+		assert.Error(t, validateSharingPolicy(&Config{Sharing: &SharingConfig{Enabled: true, IdleCheck: window}}), window)
+	}
+}
+
+func TestValidateNetworks(t *testing.T) {
+	cfg := &Config{Project: ProjectConfig{Name: "shop"}}
+	assert.NoError(t, validateNetworks(cfg))
+	assert.Empty(t, AttachedProjects(cfg))
+
+	cfg.Networks = &NetworksConfig{Attach: []string{"billing"}}
+	assert.NoError(t, validateNetworks(cfg))
+	assert.Equal(t, []string{"billing"}, AttachedProjects(cfg))
+
+	for _, attach := range []string{"shop", " "} {
+		cfg.Networks = &NetworksConfig{Attach: []string{"billing", attach}}
+		assert.Error(t, validateNetworks(cfg), attach)
+	}
+}
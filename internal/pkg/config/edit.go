@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// SetSharingInstance records in the project's configuration that it uses a
+// second shared instance of service. The change goes to config.local.yaml
+// when that file has a sharing section, since it would hide one written to
+// config.yaml. Comments in the file are kept.
+func SetSharingInstance(service, instance string) error {
+	return setValue(sharingConfigPath(core.OttoStackDir), []string{"sharing", "instances", service}, instance)
+}
+
+// UnshareService records in the configuration of the project whose
+// .otto-stack directory is configDir that it runs service itself, mounting
+// the named volumes its data was copied to, keyed by container path. An empty
+// sharing.services map shares every shareable service, so stillShared lists
+// the services to keep shared when that map has to be started. The project
+// stops using a second instance of the service.
+func UnshareService(configDir, service string, stillShared []string, volumes map[string]string) error {
+	return editFile(sharingConfigPath(configDir), func(root *yaml.Node) {
+		services := lookup(root, "sharing", "services")
+		if services == nil || len(services.Content) == 0 {
+			for _, other := range stillShared {
+				setScalar(root, []string{"sharing", "services", other}, "!!bool", "true", 0)
+			}
+		}
+		setScalar(root, []string{"sharing", "services", service}, "!!bool", "false", 0)
+		deleteKey(root, []string{"sharing", "instances", service})
+		for _, mount := range slices.Sorted(maps.Keys(volumes)) {
+			setScalar(root, []string{"volumes", service, mount}, "!!str", volumes[mount], yaml.DoubleQuotedStyle)
+		}
+	})
+}
+
+// AssignPorts records host ports up picked for services in the project's
+// config.local.yaml, keyed by service and then the port's variable, creating
+// the file when there is none
+func AssignPorts(configDir string, assigned map[string]map[string]int) error {
+	path := filepath.Join(configDir, core.LocalConfigFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.WriteFile(path, nil, core.PermReadWrite); err != nil {
+			return pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, path, messages.ErrorsConfigWriteFailed, err)
+		}
+	}
+	return editFile(path, func(root *yaml.Node) {
+		for _, service := range slices.Sorted(maps.Keys(assigned)) {
+			for _, key := range slices.Sorted(maps.Keys(assigned[service])) {
+				setScalar(root, []string{"ports", "assigned", service, key}, "!!int", strconv.Itoa(assigned[service][key]), 0)
+			}
+		}
+	})
+}
+
+// sharingConfigPath returns the file in configDir whose sharing section is
+// in effect: config.local.yaml when it has one, as it hides config.yaml's
+func sharingConfigPath(configDir string) string {
+	local := filepath.Join(configDir, core.LocalConfigFileName)
+	if data, err := os.ReadFile(local); err == nil {
+		var cfg Config
+		if yaml.Unmarshal(data, &cfg) == nil && cfg.Sharing != nil {
+			return local
+		}
+	}
+	return filepath.Join(configDir, core.ConfigFileName)
+}
+
+// setValue sets the string at keys in the YAML file at path, creating the
+// mappings leading to it
+func setValue(path string, keys []string, value string) error {
+	return editFile(path, func(root *yaml.Node) {
+		setScalar(root, keys, "!!str", value, yaml.DoubleQuotedStyle)
+	})
+}
+
+// editFile applies edit to the top-level mapping of the YAML file at path and
+// writes it back, keeping comments
+func editFile(path string, edit func(root *yaml.Node)) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeNotFound, path, messages.ErrorsConfigNotFound, path)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, path, messages.ErrorsConfigParseFailed, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	edit(doc.Content[0])
+
+	var out bytes.Buffer
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	err = enc.Encode(&doc)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		return pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, path, messages.ErrorsConfigMarshalFailed, err)
+	}
+	if err := os.WriteFile(path, out.Bytes(), core.PermReadWrite); err != nil {
+		return pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, path, messages.ErrorsConfigWriteFailed, err)
+	}
+	return nil
+}
+
+// setScalar sets the scalar at keys below root, creating the mappings
+// leading to it
+func setScalar(root *yaml.Node, keys []string, tag, value string, style yaml.Style) {
+	node := root
+	for _, key := range keys[:len(keys)-1] {
+		node = mappingChild(node, key, yaml.MappingNode)
+	}
+	leaf := mappingChild(node, keys[len(keys)-1], yaml.ScalarNode)
+	leaf.Kind, leaf.Tag, leaf.Value, leaf.Style = yaml.ScalarNode, tag, value, style
+}
+
+// deleteKey removes the last of keys from the mapping the others lead to,
+// if it is there
+func deleteKey(root *yaml.Node, keys []string) {
+	mapping := lookup(root, keys[:len(keys)-1]...)
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == keys[len(keys)-1] {
+			mapping.Content = slices.Delete(mapping.Content, i, i+2)
+			return
+		}
+	}
+}
+
+// lookup returns the value at keys below root, or nil when a key is missing
+func lookup(root *yaml.Node, keys ...string) *yaml.Node {
+	node := root
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var child *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				child = node.Content[i+1]
+				break
+			}
+		}
+		if child == nil {
+			return nil
+		}
+		node = child
+	}
+	return node
+}
+
+// mappingChild returns the value under key in a mapping node, adding one of
+// the given kind when the key is missing or its value is null
+func mappingChild(mapping *yaml.Node, key string, kind yaml.Kind) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+		child := mapping.Content[i+1]
+		if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+			*child = yaml.Node{Kind: kind}
+		}
+		return child
+	}
+
+	child := &yaml.Node{Kind: kind}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+	return child
+}
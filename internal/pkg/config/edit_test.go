@@ -0,0 +1,115 @@
+//go:build unit
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestSetValue_KeepsCommentsAndAddsMappings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# project settings
+project:
+  name: shop # the project name
+sharing:
+  enabled: true
+`), 0o644))
+
+	require.NoError(t, setValue(path, []string{"sharing", "instances", "postgres"}, "16"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# project settings")
+	assert.Contains(t, string(data), "# the project name")
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	require.NotNil(t, cfg.Sharing)
+	assert.True(t, cfg.Sharing.Enabled)
+	assert.Equal(t, map[string]string{"postgres": "16"}, cfg.Sharing.Instances)
+	assert.Equal(t, "shop", cfg.Project.Name)
+}
+
+func TestSetValue_ReplacesNullAndExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("sharing:\n  instances:\n"), 0o644))
+
+	require.NoError(t, setValue(path, []string{"sharing", "instances", "postgres"}, "16"))
+	require.NoError(t, setValue(path, []string{"sharing", "instances", "postgres"}, "shop"))
+
+	var cfg Config
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	assert.Equal(t, map[string]string{"postgres": "shop"}, cfg.Sharing.Instances)
+}
+
+func TestSetValue_MissingFile(t *testing.T) {
+	err := setValue(filepath.Join(t.TempDir(), "missing.yaml"), []string{"sharing", "instances", "postgres"}, "16")
+	assert.Error(t, err)
+}
+
+func TestUnshareService_StartsServicesMap(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`project:
+  name: shop
+sharing:
+  enabled: true # share by default
+  instances:
+    postgres: "16"
+`), 0o644))
+
+	require.NoError(t, UnshareService(dir, "postgres", []string{"redis"}, map[string]string{"/var/lib/postgresql/data": "shop_postgres_data"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# share by default")
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	assert.Equal(t, map[string]bool{"redis": true, "postgres": false}, cfg.Sharing.Services)
+	assert.Empty(t, cfg.Sharing.Instances)
+	assert.Equal(t, map[string]map[string]string{"postgres": {"/var/lib/postgresql/data": "shop_postgres_data"}}, cfg.Volumes)
+}
+
+func TestUnshareService_KeepsServicesMapAndUsesLocalSharing(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("project:\n  name: shop\n"), 0o644))
+	localPath := filepath.Join(dir, "config.local.yaml")
+	require.NoError(t, os.WriteFile(localPath, []byte("sharing:\n  enabled: true\n  services:\n    postgres: true\n"), 0o644))
+
+	require.NoError(t, UnshareService(dir, "postgres", []string{"redis"}, nil))
+
+	data, err := os.ReadFile(localPath)
+	require.NoError(t, err)
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	assert.Equal(t, map[string]bool{"postgres": false}, cfg.Sharing.Services)
+	assert.Nil(t, cfg.Volumes)
+}
+
+func TestAssignPorts_CreatesLocalConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, AssignPorts(dir, map[string]map[string]int{"postgres": {"POSTGRES_PORT": 5433}}))
+	require.NoError(t, AssignPorts(dir, map[string]map[string]int{"redis": {"REDIS_PORT": 6380}}))
+
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(dir, core.LocalConfigFileName))
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	require.NotNil(t, cfg.Ports)
+	assert.Equal(t, map[string]map[string]int{
+		"postgres": {"POSTGRES_PORT": 5433},
+		"redis":    {"REDIS_PORT": 6380},
+	}, cfg.Ports.Assigned)
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// JSONSchemaDialect is the JSON Schema draft exported schemas declare
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// kindNull lets a key be left empty, which keeps its default
+const kindNull = "null"
+
+// JSONSchema returns a JSON Schema for config.yaml and config.local.yaml. It
+// accepts what ValidateDocument accepts and carries the descriptions and
+// defaults documented in schema.yaml, for editors to offer as completions.
+func JSONSchema() map[string]any {
+	schema := jsonSchemaFor(configSpec(), &schemaProperty{Properties: schemaProperties()})
+	schema["$schema"] = JSONSchemaDialect
+	schema["title"] = messages.SchemaConfigTitle
+	return schema
+}
+
+func jsonSchemaFor(spec *fieldSpec, doc *schemaProperty) map[string]any {
+	schema := make(map[string]any)
+	if doc != nil {
+		if doc.Description != "" {
+			schema["description"] = doc.Description
+		}
+		if doc.Default != nil && !isPlaceholder(doc.Default) {
+			schema["default"] = doc.Default
+		}
+	}
+
+	switch spec.kind {
+	case kindAny:
+		return schema
+	case kindObject:
+		props := make(map[string]any, len(spec.fields))
+		for name, field := range spec.fields {
+			var fieldDoc *schemaProperty
+			if doc != nil {
+				if p, ok := doc.Properties[name]; ok {
+					fieldDoc = &p
+				}
+			}
+			props[name] = jsonSchemaFor(field, fieldDoc)
+		}
+		schema["properties"] = props
+		schema["additionalProperties"] = false
+	case kindMap:
+		schema["additionalProperties"] = jsonSchemaFor(spec.elem, nil)
+	case kindArray:
+		var items *schemaProperty
+		if doc != nil {
+			items = doc.Items
+		}
+		schema["items"] = jsonSchemaFor(spec.elem, items)
+	}
+	schema["type"] = jsonTypes(spec.kind)
+	return schema
+}
+
+// jsonTypes lists the JSON types a key of kind accepts. Like matchesKind, a
+// string accepts any scalar, since yaml.v3 decodes one into a string.
+func jsonTypes(kind string) []any {
+	if kind == kindString {
+		return []any{kindString, kindNumber, kindInteger, kindBoolean, kindNull}
+	}
+	return []any{describeKind(kind), kindNull}
+}
+
+// isPlaceholder reports whether a schema.yaml default is filled in by init,
+// such as the project name
+func isPlaceholder(value any) bool {
+	s, ok := value.(string)
+	return ok && strings.Contains(s, "{{")
+}
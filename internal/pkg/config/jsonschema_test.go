@@ -0,0 +1,77 @@
+//go:build unit
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+)
+
+func compileJSONSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+	compiler := jsonschema.NewCompiler()
+	require.NoError(t, compiler.AddResource("config.schema.json", JSONSchema()))
+	schema, err := compiler.Compile("config.schema.json")
+	require.NoError(t, err)
+	return schema
+}
+
+func decodeYAML(t *testing.T, data string) any {
+	t.Helper()
+	var value any
+	require.NoError(t, yaml.Unmarshal([]byte(data), &value))
+	return value
+}
+
+func TestJSONSchema_AcceptsWhatValidateDocumentAccepts(t *testing.T) {
+	schema := compileJSONSchema(t)
+
+	generated, err := GenerateConfig(clicontext.NewBuilder().
+		WithProject("app", "").
+		WithServices([]string{"postgres"}, nil).
+		Build())
+	require.NoError(t, err)
+	assert.NoError(t, schema.Validate(decodeYAML(t, string(generated))))
+
+	assert.NoError(t, schema.Validate(decodeYAML(t, "project:\n  name: app\nsharing:\nservice_configuration:\n  postgres:\n    anything: goes\n")))
+
+	// A string key takes any scalar, which yaml.v3 decodes into a string
+	numeric := "project:\n  name: 1.2\n"
+	assert.Empty(t, ValidateDocument("config.yaml", []byte(numeric)))
+	assert.NoError(t, schema.Validate(decodeYAML(t, numeric)))
+}
+
+func TestJSONSchema_RejectsWhatValidateDocumentRejects(t *testing.T) {
+	schema := compileJSONSchema(t)
+
+	assert.Error(t, schema.Validate(decodeYAML(t, "project:\n  name: app\nshareing:\n  enabled: true\n")))
+	assert.Error(t, schema.Validate(decodeYAML(t, "stack:\n  enabled: postgres\n")))
+	assert.Error(t, schema.Validate(decodeYAML(t, "logs:\n  max_files: three\n")))
+}
+
+func TestJSONSchema_CarriesDocumentation(t *testing.T) {
+	schema := JSONSchema()
+	assert.Equal(t, JSONSchemaDialect, schema["$schema"])
+
+	sharing := schema["properties"].(map[string]any)["sharing"].(map[string]any)
+	enabled := sharing["properties"].(map[string]any)["enabled"].(map[string]any)
+	assert.NotEmpty(t, enabled["description"])
+	assert.Equal(t, false, enabled["default"])
+
+	project := schema["properties"].(map[string]any)["project"].(map[string]any)
+	name := project["properties"].(map[string]any)["name"].(map[string]any)
+	assert.NotContains(t, name, "default", "placeholders filled in by init are not defaults")
+}
+
+func TestGenerateConfig_SchemaModeline(t *testing.T) {
+	data, err := GenerateConfig(clicontext.NewBuilder().WithProject("app", "").Build())
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# yaml-language-server: $schema=schemas/config.schema.json\n"))
+}
@@ -9,7 +9,43 @@ type Config struct {
 	Sharing    *SharingConfig    `yaml:"sharing,omitempty" json:"sharing,omitempty"`
 	Validation *ValidationConfig `yaml:"validation,omitempty" json:"validation,omitempty"`
 	Advanced   *AdvancedConfig   `yaml:"advanced,omitempty" json:"advanced,omitempty"`
+	Logs       *LogsConfig       `yaml:"logs,omitempty" json:"logs,omitempty"`
 	Version    *VersionConfig    `yaml:"version_config,omitempty" json:"version_config,omitempty"`
+	// Volumes mounts existing named Docker volumes into services, keyed by
+	// service and then container path, such as the volume shared move copies
+	// a shared container's data into
+	Volumes  map[string]map[string]string `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Ports    *PortsConfig                 `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Networks *NetworksConfig              `yaml:"networks,omitempty" json:"networks,omitempty"`
+}
+
+// NetworksConfig connects a project to other projects' networks. Attach
+// names the projects whose <project>-network every service of this project
+// joins, so containers on either side reach each other by service name.
+type NetworksConfig struct {
+	Attach []string `yaml:"attach,omitempty" json:"attach,omitempty"`
+}
+
+// AttachedProjects returns the projects whose networks cfg's services join
+func AttachedProjects(cfg *Config) []string {
+	if cfg == nil || cfg.Networks == nil {
+		return nil
+	}
+	return cfg.Networks.Attach
+}
+
+// PortsConfig moves the host ports project-local services publish, so that
+// projects running side by side do not collide. Offset is added to each
+// service's default port. With Auto, up picks the next free port for any
+// that is taken and records it in Assigned, in config.local.yaml, so the
+// project keeps it.
+type PortsConfig struct {
+	Offset int  `yaml:"offset,omitempty" json:"offset,omitempty"`
+	Auto   bool `yaml:"auto,omitempty" json:"auto,omitempty"`
+	// Assigned holds host ports by service and then the variable the port is
+	// read from, such as postgres: {POSTGRES_PORT: 5433}, or the container
+	// port for a port without one. It wins over Offset.
+	Assigned map[string]map[string]int `yaml:"assigned,omitempty" json:"assigned,omitempty"`
 }
 
 // ProjectConfig defines project-level configuration
@@ -40,6 +76,17 @@ type SharingConfig struct {
 	// true = run as a shared container; false/absent = run project-local.
 	// An empty map shares every service the catalog marks as shareable.
 	Services map[string]bool `yaml:"services,omitempty" json:"services,omitempty"`
+	// OnConflict is what up does when a shared container already runs with a
+	// different configuration: core.SharingOnConflictWarn (the default) or
+	// core.SharingOnConflictFail
+	OnConflict string `yaml:"on_conflict,omitempty" json:"on_conflict,omitempty"`
+	// Instances maps a service to the suffix of the second shared instance this
+	// project uses instead of the default one, e.g. postgres: "16" for
+	// otto-stack-postgres-16
+	Instances map[string]string `yaml:"instances,omitempty" json:"instances,omitempty"`
+	// IdleCheck is how long a shared container must go unused before up
+	// suggests shared gc, as a duration such as 72h. Empty turns the check off.
+	IdleCheck string `yaml:"idle_check,omitempty" json:"idle_check,omitempty"`
 }
 
 // ValidationConfig defines validation settings
@@ -54,6 +101,16 @@ type AdvancedConfig struct {
 	CleanupOnRecreate bool `yaml:"cleanup_on_recreate" json:"cleanup_on_recreate"`
 }
 
+// LogsConfig defines log recording settings. When Record is set, up starts a
+// background recorder that writes each service's output to .otto-stack/logs.
+type LogsConfig struct {
+	Record bool `yaml:"record" json:"record"`
+	// MaxSizeMB is the size at which a service's log file is rotated
+	MaxSizeMB int `yaml:"max_size_mb,omitempty" json:"max_size_mb,omitempty"`
+	// MaxFiles is the number of rotated files kept per service
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+}
+
 // VersionConfig defines version constraint settings
 type VersionConfig struct {
 	RequiredVersion string `yaml:"required_version,omitempty" json:"required_version,omitempty"`
@@ -0,0 +1,369 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	embeddedconfig "github.com/otto-nation/otto-stack/internal/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Value kinds used by schema validation; the names match schema.yaml types
+const (
+	kindObject  = "object"
+	kindArray   = "array"
+	kindString  = "string"
+	kindBoolean = "boolean"
+	kindInteger = "integer"
+	kindNumber  = "number"
+
+	// kindMap is an object whose keys are free-form, such as sharing.services
+	kindMap = "map"
+	// kindAny accepts any value
+	kindAny = ""
+)
+
+// yamlLinePattern finds the line number in yaml.v3 syntax errors
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// Issue is a problem found in a configuration file, located by line and column
+type Issue struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Path       string `json:"path,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// String formats the issue as file:line:col: message, compiler style
+func (i Issue) String() string {
+	s := fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+	if i.Suggestion != "" {
+		s += " (" + fmt.Sprintf(messages.ValidationSchemaDidYouMean, i.Suggestion) + ")"
+	}
+	return s
+}
+
+// fieldSpec describes the values a configuration key accepts
+type fieldSpec struct {
+	kind string
+	// fields holds the known keys of an object
+	fields map[string]*fieldSpec
+	// elem describes the items of an array or the values of a map
+	elem *fieldSpec
+}
+
+// configSpec is the accepted shape of config.yaml: the fields Config decodes,
+// plus keys schema.yaml documents that Config does not read
+var configSpec = sync.OnceValue(func() *fieldSpec {
+	spec := specFromType(reflect.TypeOf(Config{}))
+	mergeSchema(spec, schemaProperties())
+	return spec
+})
+
+// schemaProperties returns the top-level properties schema.yaml documents
+func schemaProperties() map[string]schemaProperty {
+	var schema struct {
+		Schema map[string]schemaProperty `yaml:"schema"`
+	}
+	if err := yaml.Unmarshal(embeddedconfig.EmbeddedSchemaYAML, &schema); err != nil {
+		return nil
+	}
+	return schema.Schema
+}
+
+// schemaProperty is a property definition in schema.yaml
+type schemaProperty struct {
+	Type        string                    `yaml:"type"`
+	Description string                    `yaml:"description"`
+	Default     any                       `yaml:"default"`
+	Properties  map[string]schemaProperty `yaml:"properties"`
+	Items       *schemaProperty           `yaml:"items"`
+}
+
+func specFromType(t reflect.Type) *fieldSpec {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return &fieldSpec{kind: kindAny}
+		}
+		spec := &fieldSpec{kind: kindObject, fields: make(map[string]*fieldSpec)}
+		for i := range t.NumField() {
+			field := t.Field(i)
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			spec.fields[name] = specFromType(field.Type)
+		}
+		return spec
+	case reflect.Map:
+		return &fieldSpec{kind: kindMap, elem: specFromType(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &fieldSpec{kind: kindArray, elem: specFromType(t.Elem())}
+	case reflect.String:
+		return &fieldSpec{kind: kindString}
+	case reflect.Bool:
+		return &fieldSpec{kind: kindBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &fieldSpec{kind: kindInteger}
+	case reflect.Float32, reflect.Float64:
+		return &fieldSpec{kind: kindNumber}
+	default:
+		return &fieldSpec{kind: kindAny}
+	}
+}
+
+func specFromSchema(prop schemaProperty) *fieldSpec {
+	switch prop.Type {
+	case kindObject:
+		if len(prop.Properties) == 0 {
+			return &fieldSpec{kind: kindMap, elem: &fieldSpec{kind: kindAny}}
+		}
+		spec := &fieldSpec{kind: kindObject, fields: make(map[string]*fieldSpec)}
+		mergeSchema(spec, prop.Properties)
+		return spec
+	case kindArray:
+		elem := &fieldSpec{kind: kindAny}
+		if prop.Items != nil {
+			elem = specFromSchema(*prop.Items)
+		}
+		return &fieldSpec{kind: kindArray, elem: elem}
+	case kindString, kindBoolean, kindInteger, kindNumber:
+		return &fieldSpec{kind: prop.Type}
+	default:
+		return &fieldSpec{kind: kindAny}
+	}
+}
+
+// mergeSchema adds the schema properties spec does not already know
+func mergeSchema(spec *fieldSpec, props map[string]schemaProperty) {
+	for name, prop := range props {
+		existing, ok := spec.fields[name]
+		if !ok {
+			spec.fields[name] = specFromSchema(prop)
+			continue
+		}
+		if existing.kind == kindObject && len(prop.Properties) > 0 {
+			mergeSchema(existing, prop.Properties)
+		}
+	}
+}
+
+// ValidateFiles checks config.yaml and, when present, config.local.yaml
+// against the configuration schema. An error means config.yaml could not be read.
+func ValidateFiles() ([]Issue, error) {
+	basePath := getConfigPath()
+	data, err := os.ReadFile(basePath)
+	if err != nil {
+		return nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeNotFound, basePath, messages.ErrorsConfigNotFound, basePath)
+	}
+	issues := ValidateDocument(basePath, data)
+
+	localPath := getLocalConfigPath()
+	if data, err := os.ReadFile(localPath); err == nil {
+		issues = append(issues, ValidateDocument(localPath, data)...)
+	}
+	return issues, nil
+}
+
+// ValidateDocument checks one configuration document, reporting unknown keys
+// and values of the wrong type with their position in file
+func ValidateDocument(file string, data []byte) []Issue {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Issue{SyntaxIssue(file, err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	v := &validator{file: file}
+	v.check(doc.Content[0], configSpec(), "")
+	return v.issues
+}
+
+// IssuesError turns schema issues into an error whose output lists each one
+func IssuesError(issues []Issue) error {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = issue.String()
+	}
+	return pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeInvalid, issues[0].File, messages.ErrorsConfigSchemaInvalid, len(issues)).
+		WithOutput(strings.Join(lines, "\n"))
+}
+
+// SyntaxIssue reports a YAML parse error in file, at its line when known
+func SyntaxIssue(file string, err error) Issue {
+	issue := Issue{File: file, Message: fmt.Sprintf(messages.ValidationSchemaSyntax, strings.TrimPrefix(err.Error(), "yaml: "))}
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		issue.Line, _ = strconv.Atoi(m[1])
+	}
+	return issue
+}
+
+type validator struct {
+	file   string
+	issues []Issue
+}
+
+func (v *validator) check(node *yaml.Node, spec *fieldSpec, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// An empty value leaves the field at its default
+	if spec.kind == kindAny || (node.Kind == yaml.ScalarNode && node.Tag == "!!null") {
+		return
+	}
+	if !matchesKind(node, spec.kind) {
+		v.add(node, path, fmt.Sprintf(messages.ValidationSchemaWrongType, path, describeKind(spec.kind), describeNode(node)), "")
+		return
+	}
+
+	switch spec.kind {
+	case kindObject:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			child, ok := spec.fields[key.Value]
+			if !ok {
+				v.add(key, joinPath(path, key.Value), fmt.Sprintf(messages.ValidationSchemaUnknownField, joinPath(path, key.Value)), closestKey(key.Value, spec.fields))
+				continue
+			}
+			v.check(value, child, joinPath(path, key.Value))
+		}
+	case kindMap:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v.check(node.Content[i+1], spec.elem, joinPath(path, node.Content[i].Value))
+		}
+	case kindArray:
+		for i, item := range node.Content {
+			v.check(item, spec.elem, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+func (v *validator) add(node *yaml.Node, path, message, suggestion string) {
+	v.issues = append(v.issues, Issue{
+		File:       v.file,
+		Line:       node.Line,
+		Column:     node.Column,
+		Path:       path,
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+func matchesKind(node *yaml.Node, kind string) bool {
+	switch kind {
+	case kindObject, kindMap:
+		return node.Kind == yaml.MappingNode
+	case kindArray:
+		return node.Kind == yaml.SequenceNode
+	case kindBoolean:
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!bool"
+	case kindInteger:
+		return node.Kind == yaml.ScalarNode && node.Tag == "!!int"
+	case kindNumber:
+		return node.Kind == yaml.ScalarNode && (node.Tag == "!!int" || node.Tag == "!!float")
+	case kindString:
+		// yaml.v3 decodes any scalar into a string
+		return node.Kind == yaml.ScalarNode
+	default:
+		return true
+	}
+}
+
+func describeKind(kind string) string {
+	if kind == kindMap {
+		return kindObject
+	}
+	return kind
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return kindObject
+	case yaml.SequenceNode:
+		return kindArray
+	}
+	switch node.Tag {
+	case "!!bool":
+		return kindBoolean
+	case "!!int":
+		return kindInteger
+	case "!!float":
+		return kindNumber
+	default:
+		return kindString
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closestKey returns the known key nearest to name, if one is close enough
+// to be a likely typo
+func closestKey(name string, fields map[string]*fieldSpec) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	return ClosestMatch(name, keys)
+}
+
+// ClosestMatch returns the candidate nearest to name, if one is close enough
+// to be a likely typo
+func ClosestMatch(name string, candidates []string) string {
+	keys := slices.Sorted(slices.Values(candidates))
+
+	best, bestDistance := "", len(name)/3+1
+	for _, key := range keys {
+		if d := editDistance(strings.ToLower(name), key); d <= bestDistance && (best == "" || d < bestDistance) {
+			best, bestDistance = key, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
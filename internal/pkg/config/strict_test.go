@@ -0,0 +1,125 @@
+//go:build unit
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	clicontext "github.com/otto-nation/otto-stack/internal/pkg/cli/context"
+)
+
+func TestValidateDocument_GeneratedConfigIsValid(t *testing.T) {
+	ctx := clicontext.NewBuilder().
+		WithProject("test-project", "").
+		WithServices([]string{"postgres", "redis"}, nil).
+		Build()
+	data, err := GenerateConfig(ctx)
+	require.NoError(t, err)
+
+	assert.Empty(t, ValidateDocument("config.yaml", data))
+}
+
+func TestValidateDocument_AcceptsSchemaOnlyKeys(t *testing.T) {
+	data := []byte(`version: "1.0.0"
+project:
+  name: app
+service_configuration:
+  postgres:
+    anything: goes
+`)
+	assert.Empty(t, ValidateDocument("config.yaml", data))
+}
+
+func TestValidateDocument_UnknownKeys(t *testing.T) {
+	data := []byte(`project:
+  name: app
+stack:
+  enable:
+    - postgres
+shareing:
+  enabled: true
+`)
+	issues := ValidateDocument("config.yaml", data)
+	require.Len(t, issues, 2)
+
+	assert.Equal(t, Issue{
+		File: "config.yaml", Line: 4, Column: 3, Path: "stack.enable",
+		Message: "unknown field stack.enable", Suggestion: "enabled",
+	}, issues[0])
+	assert.Equal(t, "shareing", issues[1].Path)
+	assert.Equal(t, 6, issues[1].Line)
+	assert.Equal(t, 1, issues[1].Column)
+	assert.Equal(t, "sharing", issues[1].Suggestion)
+	assert.Equal(t, `config.yaml:6:1: unknown field shareing (did you mean "sharing"?)`, issues[1].String())
+}
+
+func TestValidateDocument_NoSuggestionForUnrelatedKey(t *testing.T) {
+	issues := ValidateDocument("config.yaml", []byte("project:\n  name: app\nfrobnicate: true\n"))
+	require.Len(t, issues, 1)
+	assert.Empty(t, issues[0].Suggestion)
+}
+
+func TestValidateDocument_WrongTypes(t *testing.T) {
+	data := []byte(`project:
+  name: app
+stack:
+  enabled: postgres
+sharing:
+  enabled: "yes please"
+  services:
+    redis: 1
+logs:
+  max_files: three
+`)
+	issues := ValidateDocument("config.yaml", data)
+	require.Len(t, issues, 4)
+	assert.Equal(t, "stack.enabled must be array, got string", issues[0].Message)
+	assert.Equal(t, "sharing.enabled must be boolean, got string", issues[1].Message)
+	assert.Equal(t, "sharing.services.redis", issues[2].Path)
+	assert.Equal(t, 10, issues[3].Line)
+	assert.Equal(t, 14, issues[3].Column)
+}
+
+func TestValidateDocument_NullValuesAreAllowed(t *testing.T) {
+	assert.Empty(t, ValidateDocument("config.yaml", []byte("project:\n  name: app\nsharing:\nlogs:\n  record:\n")))
+}
+
+func TestValidateDocument_SyntaxError(t *testing.T) {
+	issues := ValidateDocument("config.yaml", []byte("project:\n  name: app\n stack: [\n"))
+	require.Len(t, issues, 1)
+	assert.Positive(t, issues[0].Line)
+	assert.Contains(t, issues[0].Message, "invalid YAML")
+}
+
+func TestValidateFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := ValidateFiles()
+	assert.Error(t, err, "config.yaml is required")
+
+	require.NoError(t, os.Mkdir(core.OttoStackDir, core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(filepath.Join(core.OttoStackDir, core.ConfigFileName), []byte("project:\n  name: app\n"), core.PermReadWrite))
+	require.NoError(t, os.WriteFile(filepath.Join(core.OttoStackDir, core.LocalConfigFileName), []byte("advanced:\n  autostart: true\n"), core.PermReadWrite))
+
+	issues, err := ValidateFiles()
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, filepath.Join(core.OttoStackDir, core.LocalConfigFileName), issues[0].File)
+	assert.Equal(t, "auto_start", issues[0].Suggestion)
+
+	err = IssuesError(issues)
+	assert.Contains(t, err.Error(), "1 problem")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("stack", "stack"))
+	assert.Equal(t, 1, editDistance("shareing", "sharing"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	assert.Equal(t, 5, editDistance("", "stack"))
+}
@@ -1,5 +1,7 @@
 package display
 
+import "time"
+
 const (
 	// Duration formatting
 	HoursPerDay = 24
@@ -17,6 +19,7 @@ const (
 	// State values
 	StateNotFound = "not found"
 	StateUnknown  = "unknown"
+	StateMissing  = "missing"
 
 	// Table headers - Status
 	HeaderService    = "SERVICE"
@@ -29,6 +32,14 @@ const (
 	HeaderPorts      = "PORTS"
 	HeaderUpdated    = "UPDATED"
 	HeaderUsedBy     = "USED BY"
+	HeaderHistory    = "HEALTH HISTORY"
+	HeaderCPU        = "CPU %"
+	HeaderMemory     = "MEM USAGE / LIMIT"
+	HeaderMemPercent = "MEM %"
+	HeaderNetIO      = "NET I/O"
+	HeaderBlockIO    = "BLOCK I/O"
+	HeaderPIDs       = "PIDS"
+	HeaderRestarts   = "RESTARTS"
 
 	// Table headers - Catalog
 	HeaderCategory    = "CATEGORY"
@@ -44,9 +55,60 @@ const (
 	// Table headers - Web Interfaces
 	HeaderInterface = "INTERFACE"
 	HeaderURL       = "URL"
+	HeaderHostname  = "HOSTNAME"
 	HeaderStatus    = "STATUS"
 
+	// Table headers - Projects
+	HeaderProject  = "PROJECT"
+	HeaderPath     = "PATH"
+	HeaderServices = "SERVICES"
+	HeaderLastUsed = "LAST USED"
+	HeaderDisk     = "DISK"
+
+	// Table headers - Shared containers
+	HeaderConfig = "CONFIG"
+	HeaderTime   = "TIME"
+	HeaderAction = "ACTION"
+	HeaderReason = "REASON"
+
+	// Table headers - Certificates
+	HeaderHosts   = "HOSTS"
+	HeaderExpires = "EXPIRES"
+
+	// ConfigHashDisplayLength is how much of a configuration hash tables show
+	ConfigHashDisplayLength = 12
+
+	// Shared inspect labels and sections
+	LabelService         = "Service"
+	LabelRuns            = "Runs"
+	LabelContainer       = "Container"
+	LabelState           = "State"
+	LabelImage           = "Image"
+	LabelConfigHash      = "Config hash"
+	LabelPorts           = "Ports"
+	LabelLastUsed        = "Last used"
+	SectionConfiguration = "Configuration:"
+	SectionVolumes       = "Volumes:"
+	SectionProjects      = "Projects:"
+
+	// Watch mode
+	HealthHistorySize      = 5
+	WatchHighlightDuration = 10 * time.Second
+	WatchChangedMarker     = "* "
+	WatchHistorySeparator  = "→"
+	WatchHeaderFormat      = "Last refresh %s · every %s · Ctrl+C to exit\n"
+
 	// Summary format strings
 	SummaryTotal = "Summary: %d total"
 	SummaryItem  = ", %d %s"
+	SummaryUsage = "Resources: %s CPU, %s memory\n"
+
+	// Stats formatting
+	StatsPercentFormat = "%.2f%%"
+	StatsPairFormat    = "%s / %s"
+	StatsHeaderFormat  = "Last sample %s · Ctrl+C to exit\n"
+
+	// Event formatting
+	EventLineFormat     = "%s  %-28s  %-36s  %s"
+	EventExitCodeFormat = "%s (exit %s)"
 )
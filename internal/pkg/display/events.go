@@ -0,0 +1,52 @@
+package display
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+)
+
+// FormatContainerEvent renders an event as a single human-readable line, e.g.
+// "12:03:11  my-app/kafka-broker  my-app-kafka-broker-1  die (exit 137)"
+func FormatContainerEvent(event docker.ContainerEvent, noColor bool) string {
+	source := event.Service
+	if event.Project != "" {
+		source = event.Project + "/" + event.Service
+	}
+	if source == "" {
+		source = NotApplicable
+	}
+
+	action := event.Action
+	if code := event.ExitCode(); code != "" {
+		action = fmt.Sprintf(EventExitCodeFormat, action, code)
+	}
+
+	return fmt.Sprintf(EventLineFormat,
+		event.Time.Format("15:04:05"),
+		source,
+		event.Container,
+		colorizeEvent(action, event, noColor))
+}
+
+// colorizeEvent colors failures red, recoveries green and health transitions by status
+func colorizeEvent(text string, event docker.ContainerEvent, noColor bool) string {
+	if noColor {
+		return text
+	}
+	if health := event.HealthStatus(); health != "" {
+		return ColorizeState(text, health, noColor)
+	}
+	switch events.Action(event.Action) {
+	case events.ActionDie, events.ActionOOM, events.ActionKill:
+		return ui.ColorRed + text + ui.ColorReset
+	case events.ActionStart, events.ActionUnPause:
+		return ui.ColorGreen + text + ui.ColorReset
+	case events.ActionRestart, events.ActionPause, events.ActionStop:
+		return ui.ColorYellow + text + ui.ColorReset
+	default:
+		return ui.ColorGray + text + ui.ColorReset
+	}
+}
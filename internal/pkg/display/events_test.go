@@ -0,0 +1,39 @@
+//go:build unit
+
+package display
+
+import (
+	"testing"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatContainerEvent(t *testing.T) {
+	event := docker.ContainerEvent{
+		Time:       time.Date(2026, 1, 1, 12, 3, 11, 0, time.Local),
+		Action:     "die",
+		Container:  "my-app-kafka-broker-1",
+		Service:    "kafka-broker",
+		Project:    "my-app",
+		Attributes: map[string]string{docker.EventAttrExitCode: "137"},
+	}
+
+	line := FormatContainerEvent(event, true)
+	assert.Contains(t, line, "12:03:11")
+	assert.Contains(t, line, "my-app/kafka-broker")
+	assert.Contains(t, line, "my-app-kafka-broker-1")
+	assert.Contains(t, line, "die (exit 137)")
+
+	assert.Contains(t, FormatContainerEvent(event, false), ui.ColorRed)
+}
+
+func TestFormatContainerEvent_Health(t *testing.T) {
+	event := docker.ContainerEvent{Action: "health_status: healthy", Container: "otto-stack-postgres"}
+
+	line := FormatContainerEvent(event, false)
+	assert.Contains(t, line, NotApplicable)
+	assert.Contains(t, line, ui.ColorGreen)
+}
@@ -0,0 +1,86 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+)
+
+// ProjectRow is one line of the `projects` table. Running and DiskUsage are
+// nil when Docker could not be queried.
+type ProjectRow struct {
+	Name      string    `json:"name" yaml:"name"`
+	Path      string    `json:"path" yaml:"path"`
+	Services  []string  `json:"services" yaml:"services"`
+	State     string    `json:"state" yaml:"state"`
+	Running   *int      `json:"running,omitempty" yaml:"running,omitempty"`
+	LastUsed  time.Time `json:"last_used" yaml:"last_used"`
+	DiskUsage *int64    `json:"disk_usage_bytes,omitempty" yaml:"disk_usage_bytes,omitempty"`
+}
+
+// ProjectState derives a project's state from whether its directory exists
+// and how many of its containers are running, if known
+func ProjectState(exists bool, running *int) string {
+	switch {
+	case !exists:
+		return StateMissing
+	case running == nil:
+		return StateUnknown
+	case *running > 0:
+		return docker.HealthStatusRunning
+	default:
+		return docker.HealthStatusStopped
+	}
+}
+
+// RenderProjectsTable draws the `projects` table
+func RenderProjectsTable(writer io.Writer, rows []ProjectRow, now time.Time, noColor bool) {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(writer)
+	tw.SetStyle(tableStyle)
+	tw.AppendHeader(table.Row{HeaderProject, HeaderPath, HeaderServices, HeaderState, HeaderLastUsed, HeaderDisk})
+	for _, row := range rows {
+		tw.AppendRow(table.Row{
+			row.Name,
+			row.Path,
+			formatServiceList(row.Services),
+			ColorizeState(formatProjectState(row), row.State, noColor),
+			formatLastUsed(row.LastUsed, now),
+			formatDiskUsage(row.DiskUsage),
+		})
+	}
+	tw.Render()
+}
+
+func formatServiceList(services []string) string {
+	if len(services) == 0 {
+		return "-"
+	}
+	return strings.Join(services, ", ")
+}
+
+func formatProjectState(row ProjectRow) string {
+	if row.State == docker.HealthStatusRunning && row.Running != nil {
+		return fmt.Sprintf("%s (%d)", row.State, *row.Running)
+	}
+	return row.State
+}
+
+func formatLastUsed(lastUsed, now time.Time) string {
+	if lastUsed.IsZero() {
+		return NotApplicable
+	}
+	return units.HumanDuration(now.Sub(lastUsed)) + " ago"
+}
+
+func formatDiskUsage(size *int64) string {
+	if size == nil {
+		return NotApplicable
+	}
+	return units.HumanSize(float64(*size))
+}
@@ -0,0 +1,43 @@
+//go:build unit
+
+package display
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+)
+
+func TestProjectState(t *testing.T) {
+	running, stopped := 2, 0
+	assert.Equal(t, StateMissing, ProjectState(false, &running))
+	assert.Equal(t, StateUnknown, ProjectState(true, nil))
+	assert.Equal(t, docker.HealthStatusRunning, ProjectState(true, &running))
+	assert.Equal(t, docker.HealthStatusStopped, ProjectState(true, &stopped))
+}
+
+func TestRenderProjectsTable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	running := 2
+	size := int64(1500000)
+	rows := []ProjectRow{
+		{Name: "api", Path: "/work/api", Services: []string{"postgres", "redis"}, State: docker.HealthStatusRunning,
+			Running: &running, LastUsed: now.Add(-3 * time.Hour), DiskUsage: &size},
+		{Name: "old", Path: "/work/old", State: StateUnknown},
+	}
+
+	var buf bytes.Buffer
+	RenderProjectsTable(&buf, rows, now, true)
+	out := buf.String()
+
+	assert.Contains(t, out, HeaderLastUsed)
+	assert.Contains(t, out, "postgres, redis")
+	assert.Contains(t, out, "running (2)")
+	assert.Contains(t, out, "3 hours ago")
+	assert.Contains(t, out, "1.5MB")
+	assert.Contains(t, out, NotApplicable)
+}
@@ -0,0 +1,167 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+// SharedRow is one line of the `shared list` table
+type SharedRow struct {
+	Service    string    `json:"service" yaml:"service"`
+	Container  string    `json:"container" yaml:"container"`
+	State      string    `json:"state" yaml:"state"`
+	Projects   []string  `json:"projects" yaml:"projects"`
+	ConfigHash string    `json:"config_hash,omitempty" yaml:"config_hash,omitempty"`
+	Ports      []string  `json:"ports" yaml:"ports"`
+	LastUsed   time.Time `json:"last_used" yaml:"last_used"`
+}
+
+// SharedDetails is what `shared inspect` shows about one shared container
+type SharedDetails struct {
+	SharedRow      `yaml:",inline"`
+	CatalogService string               `json:"catalog_service" yaml:"catalog_service"`
+	Image          string               `json:"image,omitempty" yaml:"image,omitempty"`
+	Config         map[string]string    `json:"config,omitempty" yaml:"config,omitempty"`
+	Volumes        []SharedVolume       `json:"volumes" yaml:"volumes"`
+	Usage          []SharedProjectUsage `json:"usage" yaml:"usage"`
+}
+
+// SharedVolume is a volume or host directory a shared container mounts
+type SharedVolume struct {
+	Source      string `json:"source" yaml:"source"`
+	Destination string `json:"destination" yaml:"destination"`
+	Type        string `json:"type" yaml:"type"`
+}
+
+// SharedProjectUsage is what one project has on a shared container
+type SharedProjectUsage struct {
+	Project   string    `json:"project" yaml:"project"`
+	ConfigDir string    `json:"config_dir,omitempty" yaml:"config_dir,omitempty"`
+	LastUsed  time.Time `json:"last_used" yaml:"last_used"`
+	Database  string    `json:"database,omitempty" yaml:"database,omitempty"`
+	User      string    `json:"user,omitempty" yaml:"user,omitempty"`
+	Namespace string    `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// SharedHistoryRow is one line of the `shared history` table
+type SharedHistoryRow struct {
+	Time      time.Time
+	Action    string
+	Service   string
+	Container string
+	Project   string
+	Reason    string
+}
+
+// RenderSharedHistory draws the `shared history` table, oldest first
+func RenderSharedHistory(writer io.Writer, rows []SharedHistoryRow) {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(writer)
+	tw.SetStyle(tableStyle)
+	tw.AppendHeader(table.Row{HeaderTime, HeaderAction, HeaderService, HeaderContainer, HeaderProject, HeaderReason})
+	for _, row := range rows {
+		reason := row.Reason
+		if reason == "" {
+			reason = NotApplicable
+		}
+		tw.AppendRow(table.Row{
+			row.Time.Local().Format(time.DateTime),
+			row.Action,
+			row.Service,
+			row.Container,
+			row.Project,
+			reason,
+		})
+	}
+	tw.Render()
+}
+
+// RenderSharedTable draws the `shared list` table
+func RenderSharedTable(writer io.Writer, rows []SharedRow, now time.Time, noColor bool) {
+	tw := table.NewWriter()
+	tw.SetOutputMirror(writer)
+	tw.SetStyle(tableStyle)
+	tw.AppendHeader(table.Row{HeaderService, HeaderContainer, HeaderState, HeaderUsedBy, HeaderConfig, HeaderPorts, HeaderLastUsed})
+	for _, row := range rows {
+		tw.AppendRow(table.Row{
+			row.Service,
+			row.Container,
+			ColorizeState(row.State, row.State, noColor),
+			formatServiceList(row.Projects),
+			shortHash(row.ConfigHash),
+			formatServiceList(row.Ports),
+			formatLastUsed(row.LastUsed, now),
+		})
+	}
+	tw.Render()
+}
+
+// RenderSharedDetails writes the `shared inspect` report
+func RenderSharedDetails(writer io.Writer, details SharedDetails, now time.Time) {
+	field := func(label, value string) {
+		if value == "" {
+			value = "-"
+		}
+		_, _ = fmt.Fprintf(writer, "%-12s %s\n", label+":", value)
+	}
+
+	field(LabelService, details.Service)
+	if details.CatalogService != details.Service {
+		field(LabelRuns, details.CatalogService)
+	}
+	field(LabelContainer, details.Container)
+	field(LabelState, details.State)
+	field(LabelImage, details.Image)
+	field(LabelConfigHash, details.ConfigHash)
+	field(LabelPorts, strings.Join(details.Ports, ", "))
+	field(LabelLastUsed, formatLastUsed(details.LastUsed, now))
+
+	if len(details.Config) > 0 {
+		_, _ = fmt.Fprintln(writer, "\n"+SectionConfiguration)
+		for _, key := range slices.Sorted(maps.Keys(details.Config)) {
+			_, _ = fmt.Fprintf(writer, "  %s: %s\n", key, details.Config[key])
+		}
+	}
+
+	_, _ = fmt.Fprintln(writer, "\n"+SectionVolumes)
+	if len(details.Volumes) == 0 {
+		_, _ = fmt.Fprintln(writer, "  -")
+	}
+	for _, vol := range details.Volumes {
+		_, _ = fmt.Fprintf(writer, "  %s → %s (%s)\n", vol.Source, vol.Destination, vol.Type)
+	}
+
+	_, _ = fmt.Fprintln(writer, "\n"+SectionProjects)
+	if len(details.Usage) == 0 {
+		_, _ = fmt.Fprintln(writer, "  -")
+	}
+	for _, usage := range details.Usage {
+		line := fmt.Sprintf("  %s, last used %s", usage.Project, formatLastUsed(usage.LastUsed, now))
+		if usage.Database != "" {
+			line += fmt.Sprintf(", database %s (user %s)", usage.Database, usage.User)
+		}
+		if usage.Namespace != "" {
+			line += ", " + usage.Namespace
+		}
+		if usage.ConfigDir != "" {
+			line += " — " + usage.ConfigDir
+		}
+		_, _ = fmt.Fprintln(writer, line)
+	}
+}
+
+func shortHash(hash string) string {
+	if hash == "" {
+		return NotApplicable
+	}
+	if len(hash) > ConfigHashDisplayLength {
+		return hash[:ConfigHashDisplayLength]
+	}
+	return hash
+}
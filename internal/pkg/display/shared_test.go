@@ -0,0 +1,70 @@
+//go:build unit
+
+package display
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSharedTable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rows := []SharedRow{
+		{Service: "postgres", Container: "otto-stack-postgres", State: "running", Projects: []string{"shop", "billing"},
+			ConfigHash: "0123456789abcdef0123", Ports: []string{"5432:5432"}, LastUsed: now.Add(-2 * time.Hour)},
+		{Service: "redis", Container: "otto-stack-redis", State: StateNotFound},
+	}
+
+	var buf bytes.Buffer
+	RenderSharedTable(&buf, rows, now, true)
+	out := buf.String()
+
+	assert.Contains(t, out, HeaderConfig)
+	assert.Contains(t, out, "shop, billing")
+	assert.Contains(t, out, "0123456789ab")
+	assert.NotContains(t, out, "0123456789abc")
+	assert.Contains(t, out, "2 hours ago")
+	assert.Contains(t, out, StateNotFound)
+}
+
+func TestRenderSharedHistory(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local)
+	rows := []SharedHistoryRow{
+		{Time: at, Action: "register", Service: "postgres", Container: "otto-stack-postgres", Project: "shop"},
+		{Time: at.Add(time.Hour), Action: "unregister", Service: "postgres", Container: "otto-stack-postgres", Project: "shop", Reason: "moved"},
+	}
+
+	var buf bytes.Buffer
+	RenderSharedHistory(&buf, rows)
+	out := buf.String()
+
+	assert.Contains(t, out, HeaderAction)
+	assert.Contains(t, out, "2026-03-01 12:00:00")
+	assert.Contains(t, out, "2026-03-01 13:00:00")
+	assert.Contains(t, out, "moved")
+	assert.Contains(t, out, NotApplicable)
+}
+
+func TestRenderSharedDetails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	details := SharedDetails{
+		SharedRow:      SharedRow{Service: "postgres-16", Container: "otto-stack-postgres-16", State: "running"},
+		CatalogService: "postgres",
+		Image:          "postgres:16",
+		Config:         map[string]string{"image": "postgres:16"},
+		Volumes:        []SharedVolume{{Source: "pgdata", Destination: "/var/lib/postgresql/data", Type: "volume"}},
+		Usage:          []SharedProjectUsage{{Project: "shop", LastUsed: now.Add(-time.Hour), Database: "shop", User: "shop"}},
+	}
+
+	var buf bytes.Buffer
+	RenderSharedDetails(&buf, details, now)
+	out := buf.String()
+
+	assert.Contains(t, out, "Runs:")
+	assert.Contains(t, out, "pgdata → /var/lib/postgresql/data (volume)")
+	assert.Contains(t, out, "shop, last used About an hour ago, database shop (user shop)")
+	assert.Contains(t, out, "image: postgres:16")
+}
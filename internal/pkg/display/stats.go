@@ -0,0 +1,109 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+)
+
+// ioSizePrecision matches the significant digits `docker stats` uses for I/O totals
+const ioSizePrecision = 3
+
+// statsHeaders are appended to the verbose status table when stats are available
+var statsHeaders = table.Row{HeaderCPU, HeaderMemory, HeaderNetIO, HeaderBlockIO, HeaderRestarts}
+
+// StatsRow is one line of the `stats` table
+type StatsRow struct {
+	Service   string                `json:"service" yaml:"service"`
+	Container string                `json:"container" yaml:"container"`
+	Stats     docker.ContainerStats `json:"stats" yaml:"stats"`
+}
+
+// RenderStatsTable draws a stats table. When clear is set the screen is cleared
+// first so successive calls redraw in place.
+func RenderStatsTable(writer io.Writer, rows []StatsRow, sampledAt time.Time, clear bool) {
+	if clear {
+		_, _ = fmt.Fprint(writer, ui.ClearScreen)
+		_, _ = fmt.Fprintf(writer, StatsHeaderFormat, sampledAt.Format("15:04:05"))
+		_, _ = fmt.Fprintln(writer)
+	}
+
+	tw := table.NewWriter()
+	tw.SetOutputMirror(writer)
+	tw.SetStyle(tableStyle)
+	tw.AppendHeader(table.Row{HeaderService, HeaderContainer, HeaderCPU, HeaderMemory, HeaderMemPercent, HeaderNetIO, HeaderBlockIO, HeaderPIDs})
+	for _, row := range rows {
+		s := row.Stats
+		tw.AppendRow(table.Row{
+			row.Service,
+			row.Container,
+			formatPercent(s.CPUPercent),
+			formatMemory(s),
+			formatPercent(s.MemoryPercent),
+			formatIOPair(s.NetworkRx, s.NetworkTx),
+			formatIOPair(s.BlockRead, s.BlockWrite),
+			strconv.FormatUint(s.PIDs, 10),
+		})
+	}
+	tw.Render()
+}
+
+// statsCells returns the stats columns of the verbose status table for one service
+func statsCells(service ServiceStatus) table.Row {
+	restarts := strconv.Itoa(service.Restarts)
+	s := service.Stats
+	if s == nil {
+		return table.Row{NotApplicable, NotApplicable, NotApplicable, NotApplicable, restarts}
+	}
+	return table.Row{
+		formatPercent(s.CPUPercent),
+		formatMemory(*s),
+		formatIOPair(s.NetworkRx, s.NetworkTx),
+		formatIOPair(s.BlockRead, s.BlockWrite),
+		restarts,
+	}
+}
+
+// hasStats reports whether any service carries a stats sample
+func hasStats(services []ServiceStatus) bool {
+	for _, service := range services {
+		if service.Stats != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// totalUsage sums CPU percentage and memory usage across services
+func totalUsage(services []ServiceStatus) (float64, uint64) {
+	var cpu float64
+	var memory uint64
+	for _, service := range services {
+		if service.Stats == nil {
+			continue
+		}
+		cpu += service.Stats.CPUPercent
+		memory += service.Stats.MemoryUsage
+	}
+	return cpu, memory
+}
+
+func formatPercent(value float64) string {
+	return fmt.Sprintf(StatsPercentFormat, value)
+}
+
+func formatMemory(s docker.ContainerStats) string {
+	return fmt.Sprintf(StatsPairFormat, units.BytesSize(float64(s.MemoryUsage)), units.BytesSize(float64(s.MemoryLimit)))
+}
+
+func formatIOPair(in, out uint64) string {
+	return fmt.Sprintf(StatsPairFormat,
+		units.HumanSizeWithPrecision(float64(in), ioSizePrecision),
+		units.HumanSizeWithPrecision(float64(out), ioSizePrecision))
+}
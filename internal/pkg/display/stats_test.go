@@ -0,0 +1,73 @@
+//go:build unit
+
+package display
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleStats() *docker.ContainerStats {
+	return &docker.ContainerStats{
+		CPUPercent:    12.345,
+		MemoryUsage:   64 * 1024 * 1024,
+		MemoryLimit:   512 * 1024 * 1024,
+		MemoryPercent: 12.5,
+		NetworkRx:     1500,
+		NetworkTx:     2500,
+		PIDs:          7,
+	}
+}
+
+func TestStatusFormatter_FormatTable_FullWithStats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter := NewStatusFormatter(buf)
+
+	err := formatter.FormatTable([]ServiceStatus{
+		{Name: "web", State: "running", Health: "healthy", Restarts: 2, Stats: sampleStats()},
+		{Name: "db", State: "exited", Health: "unknown"},
+	}, Options{Compact: false, NoColor: true})
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.Contains(t, output, HeaderCPU)
+	assert.Contains(t, output, HeaderRestarts)
+	assert.Contains(t, output, "12.35%")
+	assert.Contains(t, output, "64MiB / 512MiB")
+	assert.Contains(t, output, "1.5kB / 2.5kB")
+}
+
+func TestStatusFormatter_FormatTable_FullWithoutStats(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter := NewStatusFormatter(buf)
+
+	err := formatter.FormatTable([]ServiceStatus{{Name: "web", State: "running"}}, Options{NoColor: true})
+	assert.NoError(t, err)
+	assert.NotContains(t, buf.String(), HeaderCPU)
+}
+
+func TestStatusFormatter_FormatResourceSummary_Usage(t *testing.T) {
+	buf := &bytes.Buffer{}
+	formatter := NewStatusFormatter(buf)
+
+	formatter.formatResourceSummary([]ServiceStatus{
+		{Name: "a", State: "running", Stats: sampleStats()},
+		{Name: "b", State: "running", Stats: sampleStats()},
+	})
+	assert.Contains(t, buf.String(), "Resources: 24.69% CPU, 128MiB memory")
+}
+
+func TestRenderStatsTable(t *testing.T) {
+	buf := &bytes.Buffer{}
+	RenderStatsTable(buf, []StatsRow{{Service: "web", Container: "app-web-1", Stats: *sampleStats()}}, time.Now(), false)
+
+	output := buf.String()
+	assert.Contains(t, output, "app-web-1")
+	assert.Contains(t, output, HeaderPIDs)
+	assert.Contains(t, output, "12.50%")
+	assert.NotContains(t, output, "Ctrl+C")
+}
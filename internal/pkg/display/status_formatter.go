@@ -7,7 +7,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/core/docker"
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 	"github.com/otto-nation/otto-stack/internal/pkg/ui"
@@ -120,6 +122,8 @@ func buildServiceStatus(cfg types.ServiceConfig, serviceToContainer map[string]s
 		CreatedAt: cs.CreatedAt,
 		UpdatedAt: cs.StartedAt,
 		Uptime:    uptime,
+		Restarts:  cs.RestartCount,
+		Stats:     cs.Stats,
 	}
 }
 
@@ -151,15 +155,23 @@ func (sf *StatusFormatter) formatCompact(services []ServiceStatus) error {
 
 func (sf *StatusFormatter) formatFull(services []ServiceStatus, options Options) error {
 	hasProvider := sf.hasProviders(services)
+	hasStats := hasStats(services)
 	tw := table.NewWriter()
 	tw.SetOutputMirror(sf.writer)
 	tw.SetStyle(tableStyle)
 
 	headers := sf.buildFullHeaders(hasProvider)
+	if hasStats {
+		headers = append(headers, statsHeaders...)
+	}
 	tw.AppendHeader(headers)
 
 	for _, service := range services {
-		tw.AppendRow(sf.buildFullRow(service, hasProvider))
+		row := sf.buildFullRow(service, hasProvider)
+		if hasStats {
+			row = append(row, statsCells(service)...)
+		}
+		tw.AppendRow(row)
 	}
 
 	tw.Render()
@@ -257,6 +269,11 @@ func (sf *StatusFormatter) formatResourceSummary(services []ServiceStatus) {
 		}
 	}
 	_, _ = fmt.Fprintln(sf.writer)
+
+	if hasStats(services) {
+		cpu, memory := totalUsage(services)
+		_, _ = fmt.Fprintf(sf.writer, SummaryUsage, formatPercent(cpu), units.BytesSize(float64(memory)))
+	}
 }
 
 func (sf *StatusFormatter) createSummary(services []ServiceStatus) map[string]int {
@@ -275,6 +292,8 @@ func (sf *StatusFormatter) getIcon(state string) string {
 		return ui.IconFail + " "
 	case docker.HealthStarting:
 		return ui.IconWarn + " "
+	case docker.StatePaused:
+		return core.IconState_paused + " "
 	default:
 		return ui.IconUnknown + " "
 	}
@@ -293,6 +312,8 @@ func ColorizeState(text, state string, noColor bool) string {
 		return ui.ColorRed + text + ui.ColorReset
 	case docker.HealthStarting:
 		return ui.ColorYellow + text + ui.ColorReset
+	case docker.StatePaused:
+		return ui.ColorCyan + text + ui.ColorReset
 	default:
 		return ui.ColorGray + text + ui.ColorReset
 	}
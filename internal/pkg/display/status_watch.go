@@ -0,0 +1,167 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+	"github.com/otto-nation/otto-stack/internal/pkg/ui"
+)
+
+// HealthChange records a single health transition observed in watch mode
+type HealthChange struct {
+	At   time.Time
+	From string
+	To   string
+}
+
+// StatusWatch tracks successive status snapshots for `status --watch`. It detects
+// state and health transitions between refreshes and keeps a short health history
+// per service.
+type StatusWatch struct {
+	previous map[string]ServiceStatus
+	history  map[string][]HealthChange
+	changed  map[string]time.Time
+	primed   bool
+}
+
+// NewStatusWatch creates an empty watch tracker
+func NewStatusWatch() *StatusWatch {
+	return &StatusWatch{
+		previous: make(map[string]ServiceStatus),
+		history:  make(map[string][]HealthChange),
+		changed:  make(map[string]time.Time),
+	}
+}
+
+// Observe records a new snapshot and returns the names of services whose state or
+// health changed since the previous one. The first snapshot never reports changes.
+func (w *StatusWatch) Observe(statuses []ServiceStatus, now time.Time) []string {
+	first := !w.primed
+	w.primed = true
+
+	var changed []string
+	for _, s := range statuses {
+		prev, seen := w.previous[s.Name]
+		w.previous[s.Name] = s
+		if first || !seen {
+			continue
+		}
+		if prev.Health != s.Health {
+			w.recordHealth(s.Name, HealthChange{At: now, From: prev.Health, To: s.Health})
+		}
+		if prev.State != s.State || prev.Health != s.Health {
+			w.changed[s.Name] = now
+			changed = append(changed, s.Name)
+		}
+	}
+	return changed
+}
+
+// History returns the recent health transitions of a service, oldest first
+func (w *StatusWatch) History(service string) []HealthChange {
+	return w.history[service]
+}
+
+// Changed reports whether the service transitioned within WatchHighlightDuration of now
+func (w *StatusWatch) Changed(service string, now time.Time) bool {
+	at, ok := w.changed[service]
+	return ok && now.Sub(at) < WatchHighlightDuration
+}
+
+func (w *StatusWatch) recordHealth(service string, change HealthChange) {
+	entries := append(w.history[service], change)
+	if len(entries) > HealthHistorySize {
+		entries = entries[len(entries)-HealthHistorySize:]
+	}
+	w.history[service] = entries
+}
+
+// BuildServiceStatuses converts container statuses into display statuses for the given
+// service configs, skipping init containers and hidden services.
+func BuildServiceStatuses(containerStatuses []docker.ContainerStatus, serviceConfigs []types.ServiceConfig) []ServiceStatus {
+	return convertToServiceStatuses(containerStatuses, serviceConfigs, buildServiceContainerMap(serviceConfigs))
+}
+
+// SharedToServiceStatuses converts shared container statuses into display statuses
+func SharedToServiceStatuses(statuses []SharedContainerStatus) []ServiceStatus {
+	result := make([]ServiceStatus, 0, len(statuses))
+	for _, s := range statuses {
+		result = append(result, ServiceStatus{
+			Name:      s.Service,
+			Scope:     ScopeShared,
+			Container: s.Name,
+			State:     s.State,
+			Health:    s.Health,
+			Ports:     s.Ports,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: s.UpdatedAt,
+			Uptime:    s.Uptime,
+		})
+	}
+	return result
+}
+
+// RenderWatchFrame redraws the watch dashboard in place. When noColor is set the
+// terminal is not cleared; frames are separated by a timestamp line instead, so the
+// output stays readable when piped to a file.
+func RenderWatchFrame(writer io.Writer, statuses []ServiceStatus, watch *StatusWatch, refreshedAt time.Time, interval time.Duration, noColor bool) error {
+	if noColor {
+		_, _ = fmt.Fprintln(writer)
+	} else {
+		_, _ = fmt.Fprint(writer, ui.ClearScreen)
+	}
+	_, _ = fmt.Fprintf(writer, WatchHeaderFormat, refreshedAt.Format("15:04:05"), interval)
+	_, _ = fmt.Fprintln(writer)
+
+	sf := &StatusFormatter{writer: writer, noColor: noColor}
+	tw := table.NewWriter()
+	tw.SetOutputMirror(writer)
+	tw.SetStyle(tableStyle)
+	tw.AppendHeader(table.Row{HeaderService, HeaderScope, HeaderState, HeaderHealth, HeaderUptime, HeaderHistory})
+
+	for _, s := range statuses {
+		name := s.Name
+		if watch.Changed(s.Name, refreshedAt) {
+			name = sf.highlight(WatchChangedMarker + name)
+		}
+		stateText := sf.getIcon(s.State) + s.State
+		healthText := sf.getIcon(s.Health) + s.Health
+		tw.AppendRow(table.Row{
+			name,
+			s.Scope,
+			sf.colorizeState(stateText, s.State),
+			sf.colorizeState(healthText, s.Health),
+			sf.formatDuration(s.Uptime),
+			formatHealthHistory(watch.History(s.Name)),
+		})
+	}
+
+	tw.Render()
+	return nil
+}
+
+// highlight marks text that changed since the previous frame
+func (sf *StatusFormatter) highlight(text string) string {
+	if sf.noColor {
+		return text
+	}
+	return ui.ColorBold + ui.ColorYellow + text + ui.ColorReset
+}
+
+// formatHealthHistory renders transitions as a chain, e.g. "starting→healthy→unhealthy (12:03:11)"
+func formatHealthHistory(changes []HealthChange) string {
+	if len(changes) == 0 {
+		return NotApplicable
+	}
+	chain := []string{changes[0].From}
+	for _, c := range changes {
+		chain = append(chain, c.To)
+	}
+	last := changes[len(changes)-1].At.Format("15:04:05")
+	return fmt.Sprintf("%s (%s)", strings.Join(chain, WatchHistorySeparator), last)
+}
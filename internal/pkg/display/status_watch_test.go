@@ -0,0 +1,92 @@
+//go:build unit
+
+package display
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusWatch_Observe_FirstSnapshotHasNoChanges(t *testing.T) {
+	watch := NewStatusWatch()
+	changed := watch.Observe([]ServiceStatus{{Name: "postgres", State: docker.StateRunning, Health: docker.HealthStarting}}, time.Now())
+	assert.Empty(t, changed)
+	assert.Empty(t, watch.History("postgres"))
+}
+
+func TestStatusWatch_Observe_RecordsTransitions(t *testing.T) {
+	watch := NewStatusWatch()
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	watch.Observe([]ServiceStatus{{Name: "postgres", State: docker.StateRunning, Health: docker.HealthStarting}}, start)
+	changed := watch.Observe([]ServiceStatus{{Name: "postgres", State: docker.StateRunning, Health: docker.HealthHealthy}}, start.Add(time.Second))
+
+	assert.Equal(t, []string{"postgres"}, changed)
+	require.Len(t, watch.History("postgres"), 1)
+	assert.Equal(t, docker.HealthStarting, watch.History("postgres")[0].From)
+	assert.Equal(t, docker.HealthHealthy, watch.History("postgres")[0].To)
+	assert.True(t, watch.Changed("postgres", start.Add(2*time.Second)))
+	assert.False(t, watch.Changed("postgres", start.Add(time.Second+WatchHighlightDuration)))
+}
+
+func TestStatusWatch_Observe_StateChangeWithoutHealthChange(t *testing.T) {
+	watch := NewStatusWatch()
+	now := time.Now()
+
+	watch.Observe([]ServiceStatus{{Name: "redis", State: docker.StateRunning, Health: "n/a"}}, now)
+	changed := watch.Observe([]ServiceStatus{{Name: "redis", State: docker.StatePaused, Health: "n/a"}}, now)
+
+	assert.Equal(t, []string{"redis"}, changed)
+	assert.Empty(t, watch.History("redis"))
+}
+
+func TestStatusWatch_HistoryIsBounded(t *testing.T) {
+	watch := NewStatusWatch()
+	now := time.Now()
+	health := []string{docker.HealthHealthy, docker.HealthUnhealthy}
+
+	watch.Observe([]ServiceStatus{{Name: "kafka", Health: docker.HealthStarting}}, now)
+	for i := 0; i < HealthHistorySize+3; i++ {
+		watch.Observe([]ServiceStatus{{Name: "kafka", Health: health[i%2]}}, now)
+	}
+
+	assert.Len(t, watch.History("kafka"), HealthHistorySize)
+}
+
+func TestFormatHealthHistory(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 3, 11, 0, time.UTC)
+	changes := []HealthChange{
+		{At: at.Add(-time.Minute), From: "starting", To: "healthy"},
+		{At: at, From: "healthy", To: "unhealthy"},
+	}
+	assert.Equal(t, "starting→healthy→unhealthy (12:03:11)", formatHealthHistory(changes))
+	assert.Equal(t, NotApplicable, formatHealthHistory(nil))
+}
+
+func TestRenderWatchFrame_NoColor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	watch := NewStatusWatch()
+	now := time.Now()
+	statuses := []ServiceStatus{{Name: "postgres", Scope: ScopeLocal, State: docker.StateRunning, Health: docker.HealthHealthy}}
+	watch.Observe(statuses, now)
+
+	require.NoError(t, RenderWatchFrame(buf, statuses, watch, now, 2*time.Second, true))
+
+	out := buf.String()
+	assert.Contains(t, out, "postgres")
+	assert.Contains(t, out, HeaderHistory)
+	assert.NotContains(t, out, "\033[")
+}
+
+func TestSharedToServiceStatuses(t *testing.T) {
+	result := SharedToServiceStatuses([]SharedContainerStatus{{Name: "otto-stack-redis", Service: "redis", State: docker.StateRunning}})
+	require.Len(t, result, 1)
+	assert.Equal(t, "redis", result[0].Name)
+	assert.Equal(t, "otto-stack-redis", result[0].Container)
+	assert.Equal(t, ScopeShared, result[0].Scope)
+}
@@ -1,6 +1,10 @@
 package display
 
-import "time"
+import (
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+)
 
 // ServiceStatus represents the status of a service
 type ServiceStatus struct {
@@ -14,6 +18,9 @@ type ServiceStatus struct {
 	CreatedAt time.Time     `json:"created_at" yaml:"created_at"`
 	UpdatedAt time.Time     `json:"updated_at" yaml:"updated_at"`
 	Uptime    time.Duration `json:"uptime" yaml:"uptime"`
+	Restarts  int           `json:"restarts" yaml:"restarts"`
+
+	Stats *docker.ContainerStats `json:"stats,omitempty" yaml:"stats,omitempty"`
 }
 
 // SharedContainerStatus represents the status of a shared container with usage info
@@ -42,6 +49,22 @@ type ProjectSharedStatusResponse struct {
 	Count            int                     `json:"count" yaml:"count"`
 }
 
+// WorkspaceProjectStatus holds one project's services in a workspace status
+type WorkspaceProjectStatus struct {
+	Project  string                   `json:"project" yaml:"project"`
+	Path     string                   `json:"path" yaml:"path"`
+	Services []docker.ContainerStatus `json:"services" yaml:"services"`
+	Count    int                      `json:"count" yaml:"count"`
+}
+
+// WorkspaceStatusResponse represents the response for workspace status queries.
+// Shared containers are listed once rather than under every project using them.
+type WorkspaceStatusResponse struct {
+	Workspace        string                   `json:"workspace" yaml:"workspace"`
+	Projects         []WorkspaceProjectStatus `json:"projects" yaml:"projects"`
+	SharedContainers []SharedContainerStatus  `json:"shared_containers" yaml:"shared_containers"`
+}
+
 // ServiceStatusResponse represents the response for service status queries
 type ServiceStatusResponse struct {
 	Services []any `json:"services" yaml:"services"`
@@ -15,7 +15,7 @@ func TestEnv_GenerateEmptyServices(t *testing.T) {
 	defer os.RemoveAll(tempDir)
 
 	envFile := filepath.Join(tempDir, ".env")
-	err := GenerateFile("test-project", nil, envFile)
+	err := GenerateFile("test-project", nil, nil, envFile)
 	testhelpers.AssertNoError(t, err, "GenerateFile with empty services should not error")
 
 	if _, err := os.Stat(envFile); os.IsNotExist(err) {
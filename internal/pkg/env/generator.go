@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -12,8 +13,17 @@ import (
 	"github.com/otto-nation/otto-stack/internal/pkg/types"
 )
 
-// GenerateFile generates and writes the env file to disk from service configs
-func GenerateFile(projectName string, serviceConfigs []types.ServiceConfig, filePath string) error {
+// Overrides replaces environment variables of individual services, keyed by
+// service name and then variable name
+type Overrides map[string]map[string]string
+
+// varReference matches ${VAR} and ${VAR:-default} in an environment value
+var varReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::?-[^}]*)?\}`)
+
+// GenerateFile generates and writes the env file to disk from service configs.
+// An overridden variable is written with its override, which also replaces
+// references to it in the service's other values, such as DATABASE_URL.
+func GenerateFile(projectName string, serviceConfigs []types.ServiceConfig, overrides Overrides, filePath string) error {
 	var content strings.Builder
 
 	fmt.Fprintf(&content, core.EnvGeneratedHeader, time.Now().Format(time.RFC1123))
@@ -28,8 +38,9 @@ func GenerateFile(projectName string, serviceConfigs []types.ServiceConfig, file
 				keys = append(keys, key)
 			}
 			sort.Strings(keys)
+			vars := overrides[config.Name]
 			for _, key := range keys {
-				fmt.Fprintf(&content, "%s=%s\n", key, config.AllEnvironment[key])
+				fmt.Fprintf(&content, "%s=%s\n", key, resolveValue(key, config.AllEnvironment[key], vars))
 			}
 			content.WriteString("\n")
 		}
@@ -38,5 +49,28 @@ func GenerateFile(projectName string, serviceConfigs []types.ServiceConfig, file
 	if err := os.MkdirAll(filepath.Dir(filePath), core.PermReadWriteExec); err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, []byte(content.String()), core.PermReadWrite)
+	// Overrides can hold database passwords provisioned on shared containers,
+	// so only the owner may read the file. WriteFile keeps the mode of a file
+	// written before, hence the Chmod.
+	if err := os.WriteFile(filePath, []byte(content.String()), core.PermPrivate); err != nil {
+		return err
+	}
+	return os.Chmod(filePath, core.PermPrivate)
+}
+
+// resolveValue applies overrides to one environment value
+func resolveValue(key, value string, vars map[string]string) string {
+	if len(vars) == 0 {
+		return value
+	}
+	if override, ok := vars[key]; ok {
+		return override
+	}
+	return varReference.ReplaceAllStringFunc(value, func(ref string) string {
+		name := varReference.FindStringSubmatch(ref)[1]
+		if override, ok := vars[name]; ok {
+			return override
+		}
+		return ref
+	})
 }
@@ -35,7 +35,7 @@ func TestGenerateFile_Basic(t *testing.T) {
 	}
 
 	tempFile := filepath.Join(t.TempDir(), core.ExtENV)
-	err := GenerateFile(projectName, serviceConfigs, tempFile)
+	err := GenerateFile(projectName, serviceConfigs, nil, tempFile)
 	assert.NoError(t, err)
 
 	content, err := os.ReadFile(tempFile)
@@ -53,7 +53,7 @@ func TestGenerateFile_EmptyServices(t *testing.T) {
 	serviceConfigs := []types.ServiceConfig{}
 
 	tempFile := filepath.Join(t.TempDir(), core.ExtENV)
-	err := GenerateFile(projectName, serviceConfigs, tempFile)
+	err := GenerateFile(projectName, serviceConfigs, nil, tempFile)
 	assert.NoError(t, err)
 
 	content, err := os.ReadFile(tempFile)
@@ -77,7 +77,7 @@ func TestGenerateFile_ValidFormat(t *testing.T) {
 	}
 
 	tempFile := filepath.Join(t.TempDir(), core.ExtENV)
-	err := GenerateFile(projectName, serviceConfigs, tempFile)
+	err := GenerateFile(projectName, serviceConfigs, nil, tempFile)
 	assert.NoError(t, err)
 
 	content, err := os.ReadFile(tempFile)
@@ -105,6 +105,49 @@ func TestGenerateFile_DirectoryError(t *testing.T) {
 	assert.NoError(t, err)
 
 	invalidPath := filepath.Join(tempFile, "subdir", ".env")
-	err = GenerateFile("test-project", services, invalidPath)
+	err = GenerateFile("test-project", services, nil, invalidPath)
 	assert.Error(t, err)
 }
+
+func TestGenerateFile_Overrides(t *testing.T) {
+	serviceConfigs := []types.ServiceConfig{
+		{
+			Name: services.ServicePostgres,
+			AllEnvironment: map[string]string{
+				services.EnvKeyDATABASE_URL:  services.EnvPostgresDATABASE_URL,
+				services.EnvKeyPOSTGRES_USER: services.EnvPostgresPOSTGRES_USER,
+			},
+		},
+		{
+			Name:           services.ServiceMysql,
+			AllEnvironment: map[string]string{services.EnvKeyMYSQL_USER: services.EnvMysqlMYSQL_USER},
+		},
+	}
+	overrides := Overrides{services.ServicePostgres: {
+		services.EnvKeyPOSTGRES_USER:     "shop",
+		services.EnvKeyPOSTGRES_PASSWORD: "secret",
+		services.EnvKeyPOSTGRES_DB:       "shop",
+	}}
+
+	tempFile := filepath.Join(t.TempDir(), core.ExtENV)
+	assert.NoError(t, GenerateFile("shop", serviceConfigs, overrides, tempFile))
+
+	content, err := os.ReadFile(tempFile)
+	assert.NoError(t, err)
+	contentStr := string(content)
+	assert.Contains(t, contentStr, "POSTGRES_USER=shop\n")
+	assert.Contains(t, contentStr, "DATABASE_URL=postgresql://shop:secret@${POSTGRES_HOST:-localhost}:${POSTGRES_PORT:-5432}/shop\n")
+	assert.Contains(t, contentStr, "MYSQL_USER="+services.EnvMysqlMYSQL_USER+"\n", "other services are untouched")
+}
+
+func TestGenerateFile_OwnerOnly(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), core.ExtENV)
+	// A file written before secrets went into it is tightened too
+	assert.NoError(t, os.WriteFile(tempFile, nil, core.PermReadWrite))
+
+	assert.NoError(t, GenerateFile("shop", nil, nil, tempFile))
+
+	info, err := os.Stat(tempFile)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(core.PermPrivate), info.Mode().Perm())
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 )
 
@@ -42,10 +43,12 @@ const (
 
 // Common fields
 const (
-	FieldFlags       = "flags"
-	FieldProjectName = "project-name"
-	FieldProjectPath = "project-path"
-	FieldServiceName = "service-name"
+	FieldArgs           = "args"
+	FieldFlags          = "flags"
+	FieldProjectName    = "project-name"
+	FieldProjectPath    = "project-path"
+	FieldServiceName    = "service-name"
+	FieldNetworksAttach = "networks.attach"
 )
 
 // Error Context Guidelines
@@ -81,6 +84,9 @@ type Error struct {
 	Context string // service name, file path, field name, etc.
 	Message string
 	Cause   error
+	// Output holds captured command output that explains the failure, such as
+	// the last lines of a failed init script
+	Output string
 }
 
 func (e *Error) Error() string {
@@ -94,6 +100,27 @@ func (e *Error) Unwrap() error {
 	return e.Cause
 }
 
+// WithOutput attaches captured command output to the error
+func (e *Error) WithOutput(output string) *Error {
+	e.Output = output
+	return e
+}
+
+// OutputOf returns the captured output of the first Error in err's chain that has any
+func OutputOf(err error) string {
+	for err != nil {
+		var e *Error
+		if !stderrors.As(err, &e) {
+			return ""
+		}
+		if e.Output != "" {
+			return e.Output
+		}
+		err = e.Cause
+	}
+	return ""
+}
+
 // ErrSilentExit signals exit code 1 without printing an additional error
 // message. Use when the command has already displayed relevant output to the
 // user (e.g., conflict detection, inline validation results) and needs to
@@ -4,6 +4,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -88,3 +89,16 @@ func TestLegacyAliases(t *testing.T) {
 	err = NewConfigErrorf(ErrCodeInvalid, "/path", "invalid: %s", "yaml")
 	assert.Equal(t, "invalid: yaml", err.Message)
 }
+
+func TestOutputOf(t *testing.T) {
+	inner := NewServiceError(ErrCodeOperationFail, "postgres", "script failed", errors.New("exit status 1")).
+		WithOutput("ERROR: relation exists")
+	outer := NewServiceError(ErrCodeOperationFail, ComponentProject, "init scripts failed", inner)
+
+	assert.Equal(t, "ERROR: relation exists", OutputOf(inner))
+	assert.Equal(t, "ERROR: relation exists", OutputOf(outer))
+	assert.Equal(t, "ERROR: relation exists", OutputOf(fmt.Errorf("up: %w", outer)))
+	assert.Empty(t, OutputOf(NewSystemError(ErrCodeInternal, "no output", nil)))
+	assert.Empty(t, OutputOf(errors.New("plain")))
+	assert.Empty(t, OutputOf(nil))
+}
@@ -0,0 +1,80 @@
+package logrecorder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+// initScriptFilePrefix names init output files script-1.log, script-2.log, ...
+const initScriptFilePrefix = "script-"
+
+// InitDir returns the directory holding a service's init script output
+func InitDir(dir, service string) string {
+	return filepath.Join(dir, core.InitLogsDir, service)
+}
+
+// ClearInitLogs removes the init output of a service's previous run
+func ClearInitLogs(dir, service string) error {
+	if err := os.RemoveAll(InitDir(dir, service)); err != nil {
+		return fmt.Errorf("clear init output: %w", err)
+	}
+	return nil
+}
+
+// CreateInitLog creates the output file for the index-th (1-based) init script of service
+func CreateInitLog(dir, service string, index int) (*os.File, error) {
+	serviceDir := InitDir(dir, service)
+	if err := os.MkdirAll(serviceDir, core.PermReadWriteExec); err != nil {
+		return nil, fmt.Errorf("create init output directory: %w", err)
+	}
+	file, err := os.Create(filepath.Join(serviceDir, fmt.Sprintf("%s%d%s", initScriptFilePrefix, index, LogFileExt)))
+	if err != nil {
+		return nil, fmt.Errorf("create init output file: %w", err)
+	}
+	return file, nil
+}
+
+// InitLogs returns the init output files of service in script order
+func InitLogs(dir, service string) ([]string, error) {
+	entries, err := os.ReadDir(InitDir(dir, service))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read init output: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && strings.HasPrefix(entry.Name(), initScriptFilePrefix) {
+			paths = append(paths, filepath.Join(InitDir(dir, service), entry.Name()))
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool { return initScriptIndex(paths[i]) < initScriptIndex(paths[j]) })
+	return paths, nil
+}
+
+// Tail returns the last n lines of the file at path
+func Tail(path string, n int) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// initScriptIndex returns N for a file named script-N.log
+func initScriptIndex(path string) int {
+	n := 0
+	_, _ = fmt.Sscanf(filepath.Base(path), initScriptFilePrefix+"%d"+LogFileExt, &n)
+	return n
+}
@@ -0,0 +1,54 @@
+//go:build unit
+
+package logrecorder
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, index := range []int{10, 2, 1} {
+		file, err := CreateInitLog(dir, "postgres", index)
+		require.NoError(t, err)
+		_, _ = fmt.Fprintf(file, "script %d\n", index)
+		require.NoError(t, file.Close())
+	}
+
+	paths, err := InitLogs(dir, "postgres")
+	require.NoError(t, err)
+	require.Len(t, paths, 3)
+	assert.Equal(t, []string{"script-1.log", "script-2.log", "script-10.log"},
+		[]string{filepath.Base(paths[0]), filepath.Base(paths[1]), filepath.Base(paths[2])})
+
+	require.NoError(t, ClearInitLogs(dir, "postgres"))
+	paths, err = InitLogs(dir, "postgres")
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script-1.log")
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	writeFile(t, path, strings.Join(lines, "\n")+"\n")
+
+	tail, err := Tail(path, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "line 28\nline 29\nline 30", tail)
+
+	tail, err = Tail(path, 100)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(lines, "\n"), tail)
+
+	_, err = Tail(filepath.Join(t.TempDir(), "missing.log"), 3)
+	assert.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package logrecorder
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+// Line is one recorded log line split into Docker's timestamp and the message
+type Line struct {
+	Time    string
+	Message string
+}
+
+// ArchiveRun moves the logs of the last run into the previous directory,
+// replacing whatever was kept from the run before it. It is called when a new
+// run starts so crash output survives a down/up cycle.
+func ArchiveRun(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read log directory: %w", err)
+	}
+
+	var logs []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && isLogFile(entry.Name()) {
+			logs = append(logs, entry.Name())
+		}
+	}
+	if len(logs) == 0 {
+		return nil
+	}
+
+	previous := filepath.Join(dir, core.PreviousLogsDir)
+	if err := os.RemoveAll(previous); err != nil {
+		return fmt.Errorf("clear previous logs: %w", err)
+	}
+	if err := os.MkdirAll(previous, core.PermReadWriteExec); err != nil {
+		return fmt.Errorf("create previous log directory: %w", err)
+	}
+	for _, name := range logs {
+		if err := os.Rename(filepath.Join(dir, name), filepath.Join(previous, name)); err != nil {
+			return fmt.Errorf("archive %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// PreviousServices lists the services that have logs from the previous run
+func PreviousServices(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, core.PreviousLogsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read previous logs: %w", err)
+	}
+
+	var services []string
+	for _, entry := range entries {
+		if service, ok := strings.CutSuffix(entry.Name(), LogFileExt); ok && entry.Type().IsRegular() {
+			services = append(services, service)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+// ReadPrevious returns the previous run's lines for service, oldest first,
+// reading rotated files before the current one
+func ReadPrevious(dir, service string) ([]Line, error) {
+	base := filepath.Join(dir, core.PreviousLogsDir, service+LogFileExt)
+	rotated, _ := filepath.Glob(base + ".*")
+	sort.Slice(rotated, func(i, j int) bool { return rotationIndex(rotated[i]) > rotationIndex(rotated[j]) })
+
+	var lines []Line
+	for _, path := range append(rotated, base) {
+		fileLines, err := readLines(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", filepath.Base(path), err)
+		}
+		lines = append(lines, fileLines...)
+	}
+	return lines, nil
+}
+
+// ParseLine splits a recorded line into its timestamp and message. Lines
+// without a timestamp are returned as a message only.
+func ParseLine(raw string) Line {
+	stamp, message, ok := strings.Cut(raw, " ")
+	if !ok || !strings.Contains(stamp, "T") || !strings.HasSuffix(stamp, "Z") {
+		return Line{Message: raw}
+	}
+	return Line{Time: stamp, Message: message}
+}
+
+func readLines(path string) ([]Line, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	var lines []Line
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), bytesPerMB)
+	for scanner.Scan() {
+		lines = append(lines, ParseLine(scanner.Text()))
+	}
+	return lines, scanner.Err()
+}
+
+// isLogFile matches <service>.log and its rotated files <service>.log.N
+func isLogFile(name string) bool {
+	if strings.HasSuffix(name, LogFileExt) {
+		return true
+	}
+	i := strings.LastIndex(name, LogFileExt+".")
+	return i > 0 && isDigits(name[i+len(LogFileExt)+1:])
+}
+
+// rotationIndex returns N for a file named <service>.log.N
+func rotationIndex(path string) int {
+	n := 0
+	_, _ = fmt.Sscanf(filepath.Ext(path), ".%d", &n)
+	return n
+}
@@ -0,0 +1,81 @@
+//go:build unit
+
+package logrecorder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRun(t *testing.T) {
+	dir := t.TempDir()
+	previous := filepath.Join(dir, core.PreviousLogsDir)
+	require.NoError(t, os.MkdirAll(previous, 0o755))
+	writeFile(t, filepath.Join(previous, "stale.log"), "old run\n")
+	writeFile(t, filepath.Join(dir, "postgres.log"), "current\n")
+	writeFile(t, filepath.Join(dir, "postgres.log.1"), "rotated\n")
+	writeFile(t, filepath.Join(dir, pidFileName), "123")
+
+	require.NoError(t, ArchiveRun(dir))
+
+	assertFile(t, filepath.Join(previous, "postgres.log"), "current\n")
+	assertFile(t, filepath.Join(previous, "postgres.log.1"), "rotated\n")
+	assert.NoFileExists(t, filepath.Join(previous, "stale.log"))
+	assert.NoFileExists(t, filepath.Join(dir, "postgres.log"))
+	assert.FileExists(t, filepath.Join(dir, pidFileName))
+}
+
+func TestArchiveRun_KeepsPreviousWhenNothingRecorded(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ArchiveRun(filepath.Join(dir, "missing")))
+
+	previous := filepath.Join(dir, core.PreviousLogsDir)
+	require.NoError(t, os.MkdirAll(previous, 0o755))
+	writeFile(t, filepath.Join(previous, "redis.log"), "kept\n")
+
+	require.NoError(t, ArchiveRun(dir))
+	assert.FileExists(t, filepath.Join(previous, "redis.log"))
+}
+
+func TestReadPrevious(t *testing.T) {
+	dir := t.TempDir()
+	previous := filepath.Join(dir, core.PreviousLogsDir)
+	require.NoError(t, os.MkdirAll(previous, 0o755))
+	writeFile(t, filepath.Join(previous, "postgres.log.2"), "2026-01-02T03:04:01Z one\n")
+	writeFile(t, filepath.Join(previous, "postgres.log.1"), "2026-01-02T03:04:02Z two\n")
+	writeFile(t, filepath.Join(previous, "postgres.log"), "2026-01-02T03:04:03Z three\nno timestamp\n")
+	writeFile(t, filepath.Join(previous, "redis.log"), "")
+
+	services, err := PreviousServices(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"postgres", "redis"}, services)
+
+	lines, err := ReadPrevious(dir, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, []Line{
+		{Time: "2026-01-02T03:04:01Z", Message: "one"},
+		{Time: "2026-01-02T03:04:02Z", Message: "two"},
+		{Time: "2026-01-02T03:04:03Z", Message: "three"},
+		{Message: "no timestamp"},
+	}, lines)
+
+	lines, err = ReadPrevious(dir, "kafka")
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestPreviousServices_NoPreviousRun(t *testing.T) {
+	services, err := PreviousServices(t.TempDir())
+	assert.NoError(t, err)
+	assert.Empty(t, services)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
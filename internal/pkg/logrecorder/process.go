@@ -0,0 +1,74 @@
+package logrecorder
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+// pidFileName holds the process ID of the background recorder
+const pidFileName = "recorder.pid"
+
+// Running reports whether a recorder process is writing to dir
+func Running(dir string) bool {
+	pid, err := readPID(dir)
+	return err == nil && processAlive(pid)
+}
+
+// WritePID records the current process as the recorder for dir
+func WritePID(dir string) error {
+	if err := os.MkdirAll(dir, core.PermReadWriteExec); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, pidFileName), []byte(strconv.Itoa(os.Getpid())), core.PermReadWrite)
+}
+
+// RemovePID forgets the recorder for dir
+func RemovePID(dir string) error {
+	if err := os.Remove(filepath.Join(dir, pidFileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Stop terminates the recorder for dir, if one is running
+func Stop(dir string) error {
+	pid, err := readPID(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err == nil && processAlive(pid) {
+		if err := terminate(pid); err != nil {
+			return fmt.Errorf("stop log recorder: %w", err)
+		}
+	}
+	return RemovePID(dir)
+}
+
+// Spawn starts the current executable with args in the background, detached
+// from the terminal so it outlives the command that started it
+func Spawn(args ...string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate executable: %w", err)
+	}
+	cmd := exec.Command(exe, args...)
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start log recorder: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+func readPID(dir string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(dir, pidFileName))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(data)))
+}
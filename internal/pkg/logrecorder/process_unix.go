@@ -0,0 +1,32 @@
+//go:build !windows
+
+package logrecorder
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// detach starts the process in its own session so closing the terminal does not stop it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// processAlive reports whether pid is a running process
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return proc.Signal(syscall.Signal(0)) == nil
+}
+
+// terminate asks the process to stop so it can close its log files
+func terminate(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Signal(syscall.SIGTERM)
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package logrecorder
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const (
+	// detachedProcess starts the process without a console
+	detachedProcess = 0x00000008
+
+	// stillActive is the exit code Windows reports for a running process
+	stillActive = 259
+)
+
+// detach starts the process without a console so closing the terminal does not stop it
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP | detachedProcess}
+}
+
+// processAlive reports whether pid is a running process. os.FindProcess
+// succeeds for processes that have exited, so the exit code is checked.
+func processAlive(pid int) bool {
+	handle, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = syscall.CloseHandle(handle) }()
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+	return code == stillActive
+}
+
+// terminate stops the process
+func terminate(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
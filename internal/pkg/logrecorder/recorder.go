@@ -0,0 +1,198 @@
+package logrecorder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+)
+
+// Rotation defaults used when config.yaml leaves logs.max_size_mb or logs.max_files unset
+const (
+	DefaultMaxSizeMB = 10
+	DefaultMaxFiles  = 3
+)
+
+const (
+	// LogFileExt is the extension of a service's current log file
+	LogFileExt = ".log"
+	// InitLinePrefix marks lines written by init scripts rather than the container
+	InitLinePrefix = "[init] "
+	bytesPerMB     = 1024 * 1024
+)
+
+// Options controls rotation of each service's log file
+type Options struct {
+	MaxBytes int64
+	MaxFiles int
+}
+
+// Enabled reports whether the project has opted in to log recording
+func Enabled(cfg *config.Config) bool {
+	return cfg != nil && cfg.Logs != nil && cfg.Logs.Record
+}
+
+// OptionsFromConfig returns rotation options from the logs section, filling in defaults
+func OptionsFromConfig(cfg *config.LogsConfig) Options {
+	opts := Options{MaxBytes: DefaultMaxSizeMB * bytesPerMB, MaxFiles: DefaultMaxFiles}
+	if cfg == nil {
+		return opts
+	}
+	if cfg.MaxSizeMB > 0 {
+		opts.MaxBytes = int64(cfg.MaxSizeMB) * bytesPerMB
+	}
+	if cfg.MaxFiles > 0 {
+		opts.MaxFiles = cfg.MaxFiles
+	}
+	return opts
+}
+
+// Dir returns the log directory of the project in the working directory
+func Dir() string {
+	return filepath.Join(core.OttoStackDir, core.LogsDir)
+}
+
+// Recorder writes container output to one rotating file per service. It
+// implements the compose log consumer interface, so it can be handed straight
+// to a follow-mode logs call. Lines are expected to carry Docker's timestamp prefix.
+type Recorder struct {
+	dir     string
+	project string
+	opts    Options
+	now     func() time.Time
+	mu      sync.Mutex
+	writers map[string]*RotatingWriter
+}
+
+// New creates a recorder writing to dir for the containers of project
+func New(dir, project string, opts Options) *Recorder {
+	return &Recorder{
+		dir:     dir,
+		project: project,
+		opts:    opts,
+		now:     time.Now,
+		writers: make(map[string]*RotatingWriter),
+	}
+}
+
+// Log records a line the container wrote to stdout
+func (r *Recorder) Log(containerName, message string) {
+	r.record(r.serviceFor(containerName), message)
+}
+
+// Err records a line the container wrote to stderr
+func (r *Recorder) Err(containerName, message string) {
+	r.record(r.serviceFor(containerName), message)
+}
+
+// Status is called for container lifecycle messages, which are not recorded
+func (r *Recorder) Status(container, msg string) {
+	logger.GetLogger().Debug("Container status", "container", container, "status", msg)
+}
+
+// InitWriter returns a writer that records init script output for service.
+// Each line is timestamped and prefixed with InitLinePrefix; Close flushes a
+// trailing line without a newline.
+func (r *Recorder) InitWriter(service string) io.WriteCloser {
+	return &lineWriter{emit: func(line string) {
+		r.record(service, r.now().UTC().Format(time.RFC3339Nano)+" "+InitLinePrefix+line)
+	}}
+}
+
+// Close closes every open log file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var firstErr error
+	for service, w := range r.writers {
+		if err := w.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.writers, service)
+	}
+	return firstErr
+}
+
+func (r *Recorder) record(service, line string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.writers[service]
+	if !ok {
+		var err error
+		w, err = NewRotatingWriter(filepath.Join(r.dir, service+LogFileExt), r.opts.MaxBytes, r.opts.MaxFiles)
+		if err != nil {
+			logger.GetLogger().Debug("Failed to open service log", "service", service, "error", err)
+			return
+		}
+		r.writers[service] = w
+	}
+	if _, err := fmt.Fprintln(w, strings.TrimRight(line, "\n")); err != nil {
+		logger.GetLogger().Debug("Failed to record log line", "service", service, "error", err)
+	}
+}
+
+// serviceFor maps a compose container name such as "my-app-postgres-1" or
+// "postgres-1" to its service name
+func (r *Recorder) serviceFor(containerName string) string {
+	name := strings.TrimPrefix(filepath.Base(containerName), r.project+"-")
+	if i := strings.LastIndex(name, "-"); i > 0 && isDigits(name[i+1:]) {
+		name = name[:i]
+	}
+	return name
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// lineWriter splits a byte stream into lines
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	emit func(line string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+	for {
+		line, err := w.buf.ReadString('\n')
+		if err != nil {
+			// Keep the partial line until the rest of it arrives
+			w.buf.Reset()
+			w.buf.WriteString(line)
+			return len(p), nil
+		}
+		w.emit(strings.TrimRight(line, "\r\n"))
+	}
+}
+
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.emit(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
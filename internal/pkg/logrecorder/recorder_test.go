@@ -0,0 +1,77 @@
+//go:build unit
+
+package logrecorder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnabled(t *testing.T) {
+	assert.False(t, Enabled(nil))
+	assert.False(t, Enabled(&config.Config{}))
+	assert.False(t, Enabled(&config.Config{Logs: &config.LogsConfig{}}))
+	assert.True(t, Enabled(&config.Config{Logs: &config.LogsConfig{Record: true}}))
+}
+
+func TestOptionsFromConfig(t *testing.T) {
+	assert.Equal(t, Options{MaxBytes: DefaultMaxSizeMB * bytesPerMB, MaxFiles: DefaultMaxFiles}, OptionsFromConfig(nil))
+	assert.Equal(t, Options{MaxBytes: 2 * bytesPerMB, MaxFiles: 7}, OptionsFromConfig(&config.LogsConfig{MaxSizeMB: 2, MaxFiles: 7}))
+}
+
+func TestRecorder_WritesOneFilePerService(t *testing.T) {
+	dir := t.TempDir()
+	r := New(dir, "my-app", Options{})
+
+	r.Log("my-app-postgres-1", "2026-01-02T03:04:05.000000000Z ready\n")
+	r.Err("redis-1", "2026-01-02T03:04:06.000000000Z warning")
+	r.Log("otto-stack-kafka", "2026-01-02T03:04:07.000000000Z started")
+	require.NoError(t, r.Close())
+
+	assertFile(t, filepath.Join(dir, "postgres.log"), "2026-01-02T03:04:05.000000000Z ready\n")
+	assertFile(t, filepath.Join(dir, "redis.log"), "2026-01-02T03:04:06.000000000Z warning\n")
+	assertFile(t, filepath.Join(dir, "otto-stack-kafka.log"), "2026-01-02T03:04:07.000000000Z started\n")
+}
+
+func TestRecorder_InitWriter(t *testing.T) {
+	dir := t.TempDir()
+	r := New(dir, "my-app", Options{})
+	r.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	w := r.InitWriter("postgres")
+	_, _ = fmt.Fprint(w, "creating schema\nseed")
+	_, _ = fmt.Fprint(w, "ing done")
+	require.NoError(t, w.Close())
+	require.NoError(t, r.Close())
+
+	assertFile(t, filepath.Join(dir, "postgres.log"),
+		"2026-01-02T03:04:05Z [init] creating schema\n2026-01-02T03:04:05Z [init] seeding done\n")
+}
+
+func TestRecorder_ServiceFor(t *testing.T) {
+	r := New(t.TempDir(), "my-app", Options{})
+	assert.Equal(t, "postgres", r.serviceFor("my-app-postgres-1"))
+	assert.Equal(t, "kafka-broker", r.serviceFor("kafka-broker-2"))
+	assert.Equal(t, "localstack", r.serviceFor("localstack"))
+}
+
+func TestPIDFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	assert.False(t, Running(dir))
+	require.NoError(t, Stop(dir), "stopping without a recorder is a no-op")
+
+	require.NoError(t, WritePID(dir))
+	assert.True(t, Running(dir), "the current process is alive")
+
+	require.NoError(t, RemovePID(dir))
+	assert.False(t, Running(dir))
+	_, err := os.Stat(filepath.Join(dir, pidFileName))
+	assert.True(t, os.IsNotExist(err))
+}
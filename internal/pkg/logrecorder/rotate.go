@@ -0,0 +1,110 @@
+package logrecorder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+// RotatingWriter appends to a file and rotates it once it exceeds maxBytes.
+// Rotated files are named <path>.1 (newest) to <path>.<maxFiles> (oldest).
+type RotatingWriter struct {
+	path     string
+	maxBytes int64
+	maxFiles int
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+}
+
+// NewRotatingWriter opens path for appending, creating its directory if needed
+func NewRotatingWriter(path string, maxBytes int64, maxFiles int) (*RotatingWriter, error) {
+	w := &RotatingWriter{path: path, maxBytes: maxBytes, maxFiles: maxFiles}
+	if err := os.MkdirAll(filepath.Dir(path), core.PermReadWriteExec); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends p, rotating first when p would push the file past maxBytes.
+// A single write is never split across files.
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.maxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *RotatingWriter) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, core.PermReadWrite)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	w.file, w.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts <path>.N to <path>.N+1, dropping the oldest, and starts a new file
+func (w *RotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("close log file: %w", err)
+	}
+	w.file = nil
+
+	if w.maxFiles < 1 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove log file: %w", err)
+		}
+		return w.open()
+	}
+
+	_ = os.Remove(rotatedName(w.path, w.maxFiles))
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		if err := os.Rename(rotatedName(w.path, i), rotatedName(w.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotate log file: %w", err)
+		}
+	}
+	if err := os.Rename(w.path, rotatedName(w.path, 1)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+	return w.open()
+}
+
+// rotatedName returns the name of the n-th rotated file for path
+func rotatedName(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
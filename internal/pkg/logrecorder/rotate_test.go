@@ -0,0 +1,62 @@
+//go:build unit
+
+package logrecorder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter_RotatesAtMaxBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "postgres.log")
+	w, err := NewRotatingWriter(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	assertFile(t, path, "fourth\n")
+	assertFile(t, path+".1", "third\n")
+	assertFile(t, path+".2", "second\n")
+	assert.NoFileExists(t, path+".3", "the oldest file is dropped beyond max files")
+}
+
+func TestRotatingWriter_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "redis.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+	w, err := NewRotatingWriter(path, 10, 1)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new\n"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("newer\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assertFile(t, path+".1", "old\nnew\n")
+	assertFile(t, path, "newer\n")
+}
+
+func TestRotatingWriter_WriteAfterClose(t *testing.T) {
+	w, err := NewRotatingWriter(filepath.Join(t.TempDir(), "kafka.log"), 0, 1)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close())
+
+	_, err = w.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+}
@@ -0,0 +1,116 @@
+package provision
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+)
+
+const (
+	// redisDatabases is how many logical databases redis has by default.
+	// Database 0 is left to clients that do not select one.
+	redisDatabases = 16
+
+	// redisKeySeparator ends a project's key prefix on a shared redis
+	redisKeySeparator = ":"
+
+	// resourceSeparator ends a project's resource name prefix on a shared
+	// localstack, and joins the suffix that keeps a prefix unique
+	resourceSeparator = "-"
+)
+
+// Namespacer allocates each project its own logical database on a shared
+// redis and its own resource name prefix on a shared localstack
+type Namespacer struct{}
+
+var _ registry.Namespacer = Namespacer{}
+
+// Allocate picks the lowest redis database no other project holds, and a key
+// or resource name prefix derived from the project name that no other
+// project holds
+func (Namespacer) Allocate(service, projectName string, taken map[string]*registry.Namespace) (*registry.Namespace, error) {
+	switch service {
+	case services.ServiceRedis:
+		used := make(map[int]bool, len(taken))
+		for _, ns := range taken {
+			if ns != nil && ns.Database != nil {
+				used[*ns.Database] = true
+			}
+		}
+		for db := 1; db < redisDatabases; db++ {
+			if !used[db] {
+				return &registry.Namespace{Database: &db, Prefix: uniquePrefix(projectName, redisKeySeparator, taken)}, nil
+			}
+		}
+		return nil, pkgerrors.NewSystemErrorf(pkgerrors.ErrCodeOperationFail, messages.ErrorsNamespaceExhausted, service, redisDatabases-1)
+	case services.ServiceLocalstack:
+		return &registry.Namespace{Prefix: uniquePrefix(resourceName(projectName), resourceSeparator, taken)}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// NamespaceEnvOverrides returns the environment variables that confine a
+// project to its namespace on a shared service, for env.GenerateFile
+func NamespaceEnvOverrides(service string, ns *registry.Namespace) map[string]string {
+	if ns == nil {
+		return nil
+	}
+	switch service {
+	case services.ServiceRedis:
+		overrides := map[string]string{services.EnvKeyREDIS_KEY_PREFIX: ns.Prefix}
+		if ns.Database != nil {
+			overrides[services.EnvKeyREDIS_DB] = strconv.Itoa(*ns.Database)
+		}
+		return overrides
+	case services.ServiceLocalstack:
+		return map[string]string{services.EnvKeyAWS_RESOURCE_PREFIX: ns.Prefix}
+	default:
+		return nil
+	}
+}
+
+// DescribeNamespace summarises a namespace for display
+func DescribeNamespace(ns *registry.Namespace) string {
+	if ns.Database != nil {
+		return fmt.Sprintf(messages.SharedNamespaceDatabase, *ns.Database, ns.Prefix)
+	}
+	return fmt.Sprintf(messages.SharedNamespacePrefix, ns.Prefix)
+}
+
+// uniquePrefix returns name followed by separator, adding a numeric suffix to
+// name until no taken namespace has the prefix, since different project names
+// can map to the same one
+func uniquePrefix(name, separator string, taken map[string]*registry.Namespace) string {
+	used := make(map[string]bool, len(taken))
+	for _, ns := range taken {
+		if ns != nil {
+			used[ns.Prefix] = true
+		}
+	}
+
+	prefix := name + separator
+	for n := 2; used[prefix]; n++ {
+		prefix = name + resourceSeparator + strconv.Itoa(n) + separator
+	}
+	return prefix
+}
+
+// resourceName keeps the characters S3 bucket, SQS queue and SNS topic names
+// all accept: lower case letters, digits and hyphens
+func resourceName(projectName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(projectName) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
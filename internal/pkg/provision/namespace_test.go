@@ -0,0 +1,86 @@
+//go:build unit
+
+package provision
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestNamespacer_AllocateRedis(t *testing.T) {
+	taken := map[string]*registry.Namespace{
+		"billing": {Database: intPtr(1)},
+		"search":  {Database: intPtr(3)},
+	}
+
+	ns, err := Namespacer{}.Allocate(services.ServiceRedis, "shop", taken)
+	require.NoError(t, err)
+	require.NotNil(t, ns.Database)
+	assert.Equal(t, 2, *ns.Database, "lowest free database, leaving 0 alone")
+	assert.Equal(t, "shop:", ns.Prefix)
+}
+
+func TestNamespacer_AllocateRedisExhausted(t *testing.T) {
+	taken := make(map[string]*registry.Namespace)
+	for db := 1; db < redisDatabases; db++ {
+		taken[string(rune('a'+db))] = &registry.Namespace{Database: intPtr(db)}
+	}
+
+	_, err := Namespacer{}.Allocate(services.ServiceRedis, "shop", taken)
+	assert.Error(t, err)
+}
+
+func TestNamespacer_AllocateLocalstack(t *testing.T) {
+	ns, err := Namespacer{}.Allocate(services.ServiceLocalstack, "My_Shop", nil)
+	require.NoError(t, err)
+	assert.Nil(t, ns.Database)
+	assert.Equal(t, "my-shop-", ns.Prefix)
+}
+
+func TestNamespacer_AllocateOtherService(t *testing.T) {
+	ns, err := Namespacer{}.Allocate(services.ServicePostgres, "shop", nil)
+	require.NoError(t, err)
+	assert.Nil(t, ns)
+}
+
+func TestNamespaceEnvOverrides(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		services.EnvKeyREDIS_DB:         "4",
+		services.EnvKeyREDIS_KEY_PREFIX: "shop:",
+	}, NamespaceEnvOverrides(services.ServiceRedis, &registry.Namespace{Database: intPtr(4), Prefix: "shop:"}))
+
+	assert.Equal(t, map[string]string{services.EnvKeyAWS_RESOURCE_PREFIX: "shop-"},
+		NamespaceEnvOverrides(services.ServiceLocalstack, &registry.Namespace{Prefix: "shop-"}))
+
+	assert.Nil(t, NamespaceEnvOverrides(services.ServiceRedis, nil))
+	assert.Nil(t, NamespaceEnvOverrides(services.ServicePostgres, &registry.Namespace{Prefix: "shop"}))
+}
+
+func TestNamespacer_AllocateCollidingPrefixes(t *testing.T) {
+	taken := map[string]*registry.Namespace{"my-app": {Prefix: "my-app-"}}
+
+	ns, err := Namespacer{}.Allocate(services.ServiceLocalstack, "my_app", taken)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app-2-", ns.Prefix)
+	taken["my_app"] = ns
+
+	ns, err = Namespacer{}.Allocate(services.ServiceLocalstack, "My.App", taken)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app-3-", ns.Prefix)
+
+	ns, err = Namespacer{}.Allocate(services.ServiceRedis, "shop", map[string]*registry.Namespace{"other": {Database: intPtr(1), Prefix: "shop:"}})
+	require.NoError(t, err)
+	assert.Equal(t, "shop-2:", ns.Prefix)
+}
+
+func TestResourceName(t *testing.T) {
+	assert.Equal(t, "shop", resourceName("shop"))
+	assert.Equal(t, "my-app", resourceName("-My.App-"))
+}
@@ -0,0 +1,33 @@
+package provision
+
+import (
+	"context"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+)
+
+// NetworkAttacher connects shared containers to the networks of the projects
+// using them, so those projects' containers, including ones defined in
+// docker-compose.override.yml, reach them by service name
+type NetworkAttacher struct {
+	client *docker.Client
+}
+
+var _ registry.Networker = (*NetworkAttacher)(nil)
+
+// NewNetworkAttacher creates a networker that connects containers through client
+func NewNetworkAttacher(client *docker.Client) *NetworkAttacher {
+	return &NetworkAttacher{client: client}
+}
+
+// Attach connects the container to the project's network under aliases. A
+// project that has no network yet has no containers to reach it from.
+func (a *NetworkAttacher) Attach(ctx context.Context, containerName, projectName string, aliases []string) error {
+	return a.client.ConnectNetwork(ctx, docker.ProjectNetworkName(projectName), containerName, aliases)
+}
+
+// Detach disconnects the container from the project's network
+func (a *NetworkAttacher) Detach(ctx context.Context, containerName, projectName string) error {
+	return a.client.DisconnectNetwork(ctx, docker.ProjectNetworkName(projectName), containerName)
+}
@@ -0,0 +1,247 @@
+// Package provision gives each project its own resources on shared
+// containers, so projects sharing a container do not see each other's data.
+package provision
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+)
+
+const (
+	// DefaultReadyTimeout bounds how long provisioning waits for a freshly
+	// started container to accept connections
+	DefaultReadyTimeout = 60 * time.Second
+
+	// defaultRetryInterval is the pause between provisioning attempts
+	defaultRetryInterval = 2 * time.Second
+
+	// scriptEnvVar carries the SQL script into the container
+	scriptEnvVar = "OTTO_STACK_SQL"
+
+	// maxIdentifierLength fits the shortest limit, MySQL's 32-character user names
+	maxIdentifierLength = 32
+
+	// passwordBytes of randomness give a 24-character hex password
+	passwordBytes = 12
+
+	// reservedSuffix is appended to a project name that clashes with a name the
+	// database itself uses
+	reservedSuffix = "_app"
+)
+
+// engine describes how to provision one kind of database
+type engine struct {
+	// runner reads the script from scriptEnvVar and runs it as the superuser
+	runner string
+	// reserved names belong to the server or to the shared default database
+	reserved map[string]bool
+	// env names the service's environment variables for each credential
+	env       credentialEnv
+	provision func(creds *registry.Credentials) string
+	drop      func(creds *registry.Credentials) string
+}
+
+// credentialEnv names the environment variables a service reads credentials from
+type credentialEnv struct {
+	database, user, password string
+}
+
+var engines = map[string]engine{
+	services.ServicePostgres: {
+		runner: `printf '%s\n' "$` + scriptEnvVar + `" | psql -v ON_ERROR_STOP=1 -q -U "${POSTGRES_USER:-postgres}" -d postgres`,
+		reserved: map[string]bool{
+			"postgres": true, "template0": true, "template1": true, "public": true, "local_dev": true,
+		},
+		env: credentialEnv{database: services.EnvKeyPOSTGRES_DB, user: services.EnvKeyPOSTGRES_USER, password: services.EnvKeyPOSTGRES_PASSWORD},
+		provision: func(c *registry.Credentials) string {
+			return "SELECT 'CREATE ROLE " + pgQuote(c.User) + " LOGIN' WHERE NOT EXISTS (SELECT FROM pg_roles WHERE rolname = '" + c.User + "')\\gexec\n" +
+				"ALTER ROLE " + pgQuote(c.User) + " WITH LOGIN PASSWORD '" + c.Password + "';\n" +
+				"SELECT 'CREATE DATABASE " + pgQuote(c.Database) + " OWNER " + pgQuote(c.User) + "' WHERE NOT EXISTS (SELECT FROM pg_database WHERE datname = '" + c.Database + "')\\gexec\n"
+		},
+		drop: func(c *registry.Credentials) string {
+			return "DROP DATABASE IF EXISTS " + pgQuote(c.Database) + " WITH (FORCE);\n" +
+				"DROP ROLE IF EXISTS " + pgQuote(c.User) + ";\n"
+		},
+	},
+	services.ServiceMysql: {
+		runner: `printf '%s\n' "$` + scriptEnvVar + `" | MYSQL_PWD="$MYSQL_ROOT_PASSWORD" mysql -uroot`,
+		reserved: map[string]bool{
+			"root": true, "mysql": true, "sys": true, "information_schema": true, "performance_schema": true, "local_dev": true,
+		},
+		env: credentialEnv{database: services.EnvKeyMYSQL_DATABASE, user: services.EnvKeyMYSQL_USER, password: services.EnvKeyMYSQL_PASSWORD},
+		provision: func(c *registry.Credentials) string {
+			return "CREATE DATABASE IF NOT EXISTS `" + c.Database + "`;\n" +
+				"CREATE USER IF NOT EXISTS '" + c.User + "'@'%' IDENTIFIED BY '" + c.Password + "';\n" +
+				"ALTER USER '" + c.User + "'@'%' IDENTIFIED BY '" + c.Password + "';\n" +
+				"GRANT ALL PRIVILEGES ON `" + c.Database + "`.* TO '" + c.User + "'@'%';\n" +
+				"FLUSH PRIVILEGES;\n"
+		},
+		drop: func(c *registry.Credentials) string {
+			return "DROP DATABASE IF EXISTS `" + c.Database + "`;\n" +
+				"DROP USER IF EXISTS '" + c.User + "'@'%';\n"
+		},
+	},
+}
+
+// pgQuote quotes a postgres identifier so names such as user or order are not
+// read as keywords. Identifiers hold no quotes, so none need escaping.
+func pgQuote(name string) string {
+	return `"` + name + `"`
+}
+
+// Supports reports whether a service gets per-project credentials
+func Supports(service string) bool {
+	_, ok := engines[service]
+	return ok
+}
+
+// DatabaseProvisioner gives each project its own database and role on shared
+// postgres and mysql containers
+type DatabaseProvisioner struct {
+	client        *docker.Client
+	readyTimeout  time.Duration
+	retryInterval time.Duration
+}
+
+var _ registry.Provisioner = (*DatabaseProvisioner)(nil)
+
+// NewDatabaseProvisioner creates a provisioner that runs SQL in shared
+// containers through client
+func NewDatabaseProvisioner(client *docker.Client) *DatabaseProvisioner {
+	return &DatabaseProvisioner{client: client, readyTimeout: DefaultReadyTimeout, retryInterval: defaultRetryInterval}
+}
+
+// Provision creates the project's database and role if they are missing and
+// sets the role's password. New names do not clash with those taken by other
+// projects. The container may still be starting, so failed attempts are
+// retried until the ready timeout.
+func (p *DatabaseProvisioner) Provision(ctx context.Context, service, containerName, projectName string, existing *registry.Credentials, taken map[string]*registry.Credentials) (*registry.Credentials, error) {
+	eng, ok := engines[service]
+	if !ok {
+		return nil, nil
+	}
+
+	creds := existing
+	if creds == nil {
+		password, err := generatePassword()
+		if err != nil {
+			return nil, err
+		}
+		name := uniqueIdentifier(projectName, eng.reserved, taken)
+		creds = &registry.Credentials{Database: name, User: name, Password: password}
+	}
+
+	if err := p.runWithRetry(ctx, containerName, eng.runner, eng.provision(creds)); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+// Drop removes the project's database, with its data, and its role
+func (p *DatabaseProvisioner) Drop(ctx context.Context, service, containerName string, creds *registry.Credentials) error {
+	eng, ok := engines[service]
+	if !ok {
+		return nil
+	}
+	result, err := p.client.Exec(ctx, containerName, []string{"sh", "-c", eng.runner}, []string{scriptEnvVar + "=" + eng.drop(creds)})
+	if err != nil {
+		return err
+	}
+	return result.Err(containerName)
+}
+
+func (p *DatabaseProvisioner) runWithRetry(ctx context.Context, containerName, runner, script string) error {
+	deadline := time.Now().Add(p.readyTimeout)
+	for {
+		result, err := p.client.Exec(ctx, containerName, []string{"sh", "-c", runner}, []string{scriptEnvVar + "=" + script})
+		if err == nil {
+			err = result.Err(containerName)
+		}
+		if err == nil || !time.Now().Before(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.retryInterval):
+		}
+	}
+}
+
+// EnvOverrides returns the environment variables that point a project at its
+// own credentials on a shared service, for env.GenerateFile
+func EnvOverrides(service string, creds *registry.Credentials) map[string]string {
+	eng, ok := engines[service]
+	if !ok || creds == nil {
+		return nil
+	}
+	return map[string]string{
+		eng.env.database: creds.Database,
+		eng.env.user:     creds.User,
+		eng.env.password: creds.Password,
+	}
+}
+
+// identifier turns a project name into a database and role name: lower case
+// letters, digits and underscores, not starting with a digit, and not a name
+// the server reserves
+func identifier(projectName string, reserved map[string]bool) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(projectName) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "p_" + name
+	}
+	if len(name) > maxIdentifierLength-len(reservedSuffix) {
+		name = name[:maxIdentifierLength-len(reservedSuffix)]
+	}
+	if reserved[name] {
+		name += reservedSuffix
+	}
+	return name
+}
+
+// uniqueIdentifier is identifier with a numeric suffix added until the name
+// differs from every database and role other projects hold, since different
+// project names can map to the same identifier
+func uniqueIdentifier(projectName string, reserved map[string]bool, taken map[string]*registry.Credentials) string {
+	used := make(map[string]bool, 2*len(taken))
+	for _, creds := range taken {
+		if creds != nil {
+			used[creds.Database] = true
+			used[creds.User] = true
+		}
+	}
+
+	base := identifier(projectName, reserved)
+	name := base
+	for n := 2; used[name]; n++ {
+		suffix := "_" + strconv.Itoa(n)
+		name = base[:min(len(base), maxIdentifierLength-len(suffix))] + suffix
+	}
+	return name
+}
+
+func generatePassword() (string, error) {
+	buf := make([]byte, passwordBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,183 @@
+//go:build unit
+
+package provision
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/services"
+	"github.com/otto-nation/otto-stack/test/testhelpers"
+)
+
+// execRecorder fakes exec in a container, recording each script it is given
+// and exiting with the next of exitCodes
+type execRecorder struct {
+	scripts   []string
+	exitCodes []int
+}
+
+func (r *execRecorder) client(t *testing.T) *docker.Client {
+	mock := &testhelpers.MockDockerClient{
+		ExecCreateFunc: func(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+			for _, env := range options.Env {
+				if script, ok := strings.CutPrefix(env, scriptEnvVar+"="); ok {
+					r.scripts = append(r.scripts, script)
+				}
+			}
+			return container.ExecCreateResponse{ID: "exec"}, nil
+		},
+		ExecAttachFunc: func(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+			conn, peer := net.Pipe()
+			t.Cleanup(func() { _ = peer.Close() })
+			return types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&bytes.Buffer{})}, nil
+		},
+		ExecInspectFunc: func(ctx context.Context, execID string) (container.ExecInspect, error) {
+			code := 0
+			if len(r.exitCodes) > 0 {
+				code, r.exitCodes = r.exitCodes[0], r.exitCodes[1:]
+			}
+			return container.ExecInspect{ExitCode: code}, nil
+		},
+	}
+	return docker.NewClientWithDependencies(mock, nil, nil)
+}
+
+func TestIdentifier(t *testing.T) {
+	reserved := engines[services.ServicePostgres].reserved
+	assert.Equal(t, "my_app", identifier("My-App", reserved))
+	assert.Equal(t, "p_42shop", identifier("42shop", reserved))
+	assert.Equal(t, "postgres_app", identifier("postgres", reserved))
+	assert.Equal(t, "public_app", identifier("public", reserved))
+	assert.Len(t, identifier(strings.Repeat("a", 60), reserved), maxIdentifierLength-len(reservedSuffix))
+}
+
+func TestDatabaseProvisioner_Provision(t *testing.T) {
+	rec := &execRecorder{}
+	p := NewDatabaseProvisioner(rec.client(t))
+
+	creds, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "billing-api", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "billing_api", creds.Database)
+	assert.Equal(t, "billing_api", creds.User)
+	assert.Len(t, creds.Password, 2*passwordBytes)
+
+	require.Len(t, rec.scripts, 1)
+	assert.Contains(t, rec.scripts[0], `CREATE ROLE "billing_api" LOGIN`)
+	assert.Contains(t, rec.scripts[0], "PASSWORD '"+creds.Password+"'")
+	assert.Contains(t, rec.scripts[0], `CREATE DATABASE "billing_api" OWNER "billing_api"`)
+
+	again, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "billing-api", creds, nil)
+	require.NoError(t, err)
+	assert.Equal(t, creds, again, "existing credentials are reused")
+}
+
+func TestDatabaseProvisioner_ProvisionKeywordName(t *testing.T) {
+	rec := &execRecorder{}
+	creds, err := NewDatabaseProvisioner(rec.client(t)).Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "order", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "order", creds.Database)
+	require.Len(t, rec.scripts, 1)
+	assert.Contains(t, rec.scripts[0], `ALTER ROLE "order" WITH LOGIN`)
+	assert.Contains(t, rec.scripts[0], `CREATE DATABASE "order" OWNER "order"`)
+}
+
+func TestDatabaseProvisioner_ProvisionCollidingNames(t *testing.T) {
+	rec := &execRecorder{}
+	p := NewDatabaseProvisioner(rec.client(t))
+	taken := map[string]*registry.Credentials{}
+
+	first, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "my-app", nil, taken)
+	require.NoError(t, err)
+	taken["my-app"] = first
+
+	second, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "my_app", nil, taken)
+	require.NoError(t, err)
+	taken["my_app"] = second
+
+	third, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "my.app", nil, taken)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my_app", first.Database)
+	assert.Equal(t, "my_app_2", second.Database)
+	assert.Equal(t, "my_app_2", second.User)
+	assert.Equal(t, "my_app_3", third.Database)
+}
+
+func TestUniqueIdentifier_Truncated(t *testing.T) {
+	reserved := engines[services.ServicePostgres].reserved
+	long := strings.Repeat("a", 60)
+	first := identifier(long, reserved)
+	taken := map[string]*registry.Credentials{"other": {Database: first, User: first}}
+
+	name := uniqueIdentifier(long+"b", reserved, taken)
+	assert.NotEqual(t, first, name)
+	assert.LessOrEqual(t, len(name), maxIdentifierLength)
+}
+
+func TestDatabaseProvisioner_ProvisionMySQL(t *testing.T) {
+	rec := &execRecorder{}
+	creds, err := NewDatabaseProvisioner(rec.client(t)).Provision(context.Background(), services.ServiceMysql, "otto-stack-mysql", "shop", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, rec.scripts, 1)
+	assert.Contains(t, rec.scripts[0], "CREATE DATABASE IF NOT EXISTS `shop`")
+	assert.Contains(t, rec.scripts[0], "GRANT ALL PRIVILEGES ON `shop`.* TO 'shop'@'%'")
+	assert.Equal(t, "shop", creds.User)
+}
+
+func TestDatabaseProvisioner_SkipsOtherServices(t *testing.T) {
+	rec := &execRecorder{}
+	creds, err := NewDatabaseProvisioner(rec.client(t)).Provision(context.Background(), services.ServiceKafka, "otto-stack-kafka", "shop", nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+	assert.Empty(t, rec.scripts)
+}
+
+func TestDatabaseProvisioner_RetriesUntilReady(t *testing.T) {
+	rec := &execRecorder{exitCodes: []int{2, 0}}
+	p := NewDatabaseProvisioner(rec.client(t))
+	p.retryInterval = time.Millisecond
+
+	_, err := p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "shop", nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, rec.scripts, 2)
+
+	rec = &execRecorder{exitCodes: []int{2}}
+	p = NewDatabaseProvisioner(rec.client(t))
+	p.readyTimeout = 0
+	_, err = p.Provision(context.Background(), services.ServicePostgres, "otto-stack-postgres", "shop", nil, nil)
+	assert.Error(t, err, "gives up once the ready timeout passes")
+}
+
+func TestDatabaseProvisioner_Drop(t *testing.T) {
+	rec := &execRecorder{}
+	creds := &registry.Credentials{Database: "shop", User: "shop", Password: "secret"}
+	require.NoError(t, NewDatabaseProvisioner(rec.client(t)).Drop(context.Background(), services.ServicePostgres, "otto-stack-postgres", creds))
+	require.Len(t, rec.scripts, 1)
+	assert.Contains(t, rec.scripts[0], `DROP DATABASE IF EXISTS "shop" WITH (FORCE)`)
+	assert.Contains(t, rec.scripts[0], `DROP ROLE IF EXISTS "shop"`)
+}
+
+func TestEnvOverrides(t *testing.T) {
+	creds := &registry.Credentials{Database: "shop", User: "shop_user", Password: "secret"}
+	assert.Equal(t, map[string]string{
+		services.EnvKeyPOSTGRES_DB:       "shop",
+		services.EnvKeyPOSTGRES_USER:     "shop_user",
+		services.EnvKeyPOSTGRES_PASSWORD: "secret",
+	}, EnvOverrides(services.ServicePostgres, creds))
+	assert.Nil(t, EnvOverrides(services.ServiceRedis, creds))
+	assert.True(t, Supports(services.ServiceMysql))
+	assert.False(t, Supports(services.ServiceKafka))
+}
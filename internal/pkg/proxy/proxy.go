@@ -0,0 +1,160 @@
+// Package proxy routes <service>.<project>.localhost to the web interface of
+// a service in a project, whatever host port it is published on.
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+const (
+	// Domain is the top-level domain of the hostnames. Browsers and most
+	// resolvers send every *.localhost name to the loopback address.
+	Domain = "localhost"
+
+	// ListenHost is the address the proxy listens on
+	ListenHost = "127.0.0.1"
+
+	// DefaultPort is the port the proxy listens on until another is chosen
+	DefaultPort = 8780
+
+	httpPort = 80
+
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// Resolver finds the address, host:port, serving a service's web interface
+// in a project
+type Resolver interface {
+	Resolve(ctx context.Context, service, project string) (string, error)
+}
+
+// Hostname is the name the proxy serves a service of a project under
+func Hostname(service, project string) string {
+	return service + "." + project + "." + Domain
+}
+
+// URL is the address of a service's web interface through the proxy on
+// port, keeping the path of the interface's own URL
+func URL(service, project string, port int, interfaceURL string) string {
+	host := Hostname(service, project)
+	if port != httpPort {
+		host = net.JoinHostPort(host, strconv.Itoa(port))
+	}
+	proxied := url.URL{Scheme: "http", Host: host}
+	if parsed, err := url.Parse(interfaceURL); err == nil {
+		proxied.Path = parsed.Path
+		proxied.RawQuery = parsed.RawQuery
+	}
+	return proxied.String()
+}
+
+// ParseHost splits a <service>.<project>.localhost host, with or without a
+// port, into its service and project
+func ParseHost(host string) (service, project string, ok bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	name, found := strings.CutSuffix(strings.ToLower(host), "."+Domain)
+	if !found {
+		return "", "", false
+	}
+	service, project, found = strings.Cut(name, ".")
+	if !found || service == "" || project == "" || strings.Contains(project, ".") {
+		return "", "", false
+	}
+	return service, project, true
+}
+
+// WebPort returns the container port the proxy routes a service to: that of
+// its first web interface with one
+func WebPort(service types.ServiceConfig) (int, bool) {
+	for _, iface := range service.Documentation.WebInterfaces {
+		if iface.Port != 0 {
+			return iface.Port, true
+		}
+	}
+	return 0, false
+}
+
+// NewHandler returns a handler forwarding each request, websockets
+// included, to the address resolver gives the service and project in its
+// host. A host that names nothing is answered with 404, and a service that
+// cannot be reached with 502.
+func NewHandler(resolver Resolver) http.Handler {
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(&url.URL{Scheme: "http", Host: r.In.URL.Host})
+			r.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		},
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service, project, ok := ParseHost(r.Host)
+		if !ok {
+			http.Error(w, fmt.Sprintf(messages.ProxyUnknownHost, r.Host), http.StatusNotFound)
+			return
+		}
+		target, err := resolver.Resolve(r.Context(), service, project)
+		if err != nil {
+			http.Error(w, err.Error(), statusFor(err))
+			return
+		}
+
+		out := r.Clone(r.Context())
+		out.URL.Host = target
+		proxy.ServeHTTP(w, out)
+	})
+}
+
+// statusFor answers a service the proxy does not know with 404, and one it
+// cannot reach with 502
+func statusFor(err error) int {
+	var e *pkgerrors.Error
+	if errors.As(err, &e) && e.Code == pkgerrors.ErrCodeNotFound {
+		return http.StatusNotFound
+	}
+	return http.StatusBadGateway
+}
+
+// Addr is the address the proxy listens on for port
+func Addr(port int) string {
+	return net.JoinHostPort(ListenHost, strconv.Itoa(port))
+}
+
+// Serve answers requests on listener through resolver until ctx is done,
+// then lets requests in flight finish
+func Serve(ctx context.Context, listener net.Listener, resolver Resolver) error {
+	server := &http.Server{
+		Handler:           NewHandler(resolver),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- server.Serve(listener) }()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
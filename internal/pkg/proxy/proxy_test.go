@@ -0,0 +1,123 @@
+//go:build unit
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+type fakeResolver map[string]string
+
+func (f fakeResolver) Resolve(_ context.Context, service, project string) (string, error) {
+	if target, ok := f[service+"."+project]; ok {
+		return target, nil
+	}
+	return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, "%s is not running", service)
+}
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		service string
+		project string
+		ok      bool
+	}{
+		{"jaeger.shop.localhost", "jaeger", "shop", true},
+		{"Jaeger.Shop.localhost:8780", "jaeger", "shop", true},
+		{"shop.localhost", "", "", false},
+		{"a.jaeger.shop.localhost", "", "", false},
+		{"jaeger.shop.example.com", "", "", false},
+		{"127.0.0.1:8780", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			service, project, ok := ParseHost(tt.host)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.service, service)
+			assert.Equal(t, tt.project, project)
+		})
+	}
+}
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "http://jaeger.shop.localhost:8780", URL("jaeger", "shop", 8780, ""))
+	assert.Equal(t, "http://jaeger.shop.localhost", URL("jaeger", "shop", 80, "http://localhost:16686"))
+	assert.Equal(t, "http://grafana.shop.localhost:8780/api?x=1", URL("grafana", "shop", 8780, "http://localhost:3000/api?x=1"))
+}
+
+func TestWebPort(t *testing.T) {
+	svc := types.ServiceConfig{Documentation: types.DocumentationSpec{WebInterfaces: []types.WebInterface{
+		{Name: "Docs", URL: "https://example.com"},
+		{Name: "UI", URL: "http://localhost:16686", Port: 16686},
+	}}}
+	port, ok := WebPort(svc)
+	assert.True(t, ok)
+	assert.Equal(t, 16686, port)
+
+	_, ok = WebPort(types.ServiceConfig{})
+	assert.False(t, ok)
+}
+
+func TestNewHandler(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path+" via "+r.Header.Get("X-Forwarded-Host"))
+	}))
+	defer backend.Close()
+
+	handler := NewHandler(fakeResolver{"jaeger.shop": strings.TrimPrefix(backend.URL, "http://")})
+
+	get := func(host string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("jaeger.shop.localhost:8780")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/search via jaeger.shop.localhost:8780", rec.Body.String())
+
+	rec = get("jaeger.other.localhost:8780")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "jaeger is not running")
+
+	rec = get("localhost:8780")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestNewHandler_UnreachableTarget(t *testing.T) {
+	backend := httptest.NewServer(http.NotFoundHandler())
+	target := strings.TrimPrefix(backend.URL, "http://")
+	backend.Close()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "jaeger.shop.localhost"
+	rec := httptest.NewRecorder()
+	NewHandler(fakeResolver{"jaeger.shop": target}).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestSettings(t *testing.T) {
+	home := t.TempDir()
+
+	settings, err := LoadSettings(home)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPort, settings.Port)
+
+	require.NoError(t, SaveSettings(home, Settings{Port: 8081}))
+	settings, err = LoadSettings(home)
+	require.NoError(t, err)
+	assert.Equal(t, 8081, settings.Port)
+}
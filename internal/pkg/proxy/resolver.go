@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+
+	"github.com/otto-nation/otto-stack/internal/core/docker"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	"github.com/otto-nation/otto-stack/internal/pkg/registry"
+	"github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// DockerResolver finds a service among the running containers: the
+// project's own, or the shared container the project is registered with. It
+// asks Docker on every request, so offsets, reassigned ports and restarts
+// need no reload.
+type DockerResolver struct {
+	client   *docker.Client
+	catalog  map[string]types.ServiceConfig
+	registry *registry.Manager
+}
+
+// NewDockerResolver creates a resolver reading web interface ports from
+// catalog and shared containers from the registry in sharedRoot
+func NewDockerResolver(client *docker.Client, catalog map[string]types.ServiceConfig, sharedRoot string) *DockerResolver {
+	return &DockerResolver{
+		client:   client,
+		catalog:  catalog,
+		registry: registry.NewManager(sharedRoot),
+	}
+}
+
+// Resolve returns the loopback address a service's web interface is
+// published on
+func (r *DockerResolver) Resolve(ctx context.Context, service, project string) (string, error) {
+	svc, ok := r.catalog[service]
+	if !ok {
+		return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ErrorsServiceNotFound, service)
+	}
+	port, ok := WebPort(svc)
+	if !ok {
+		return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ProxyNoWebInterface, service)
+	}
+
+	name, err := r.containerName(ctx, service, project)
+	if err != nil {
+		return "", err
+	}
+	details, err := r.client.InspectDetails(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	published, ok := details.HostPort(port)
+	if !ok {
+		return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeUnavailable, pkgerrors.FieldServiceName, messages.ProxyNotPublished, name, port)
+	}
+	return net.JoinHostPort(ListenHost, strconv.Itoa(published)), nil
+}
+
+// containerName returns the running container serving service for project
+func (r *DockerResolver) containerName(ctx context.Context, service, project string) (string, error) {
+	containers, err := r.client.ListContainers(ctx, project)
+	if err != nil {
+		return "", err
+	}
+	for _, c := range containers {
+		if c.Service == service && c.State == docker.StateRunning {
+			return c.Name, nil
+		}
+	}
+
+	shared, err := r.registry.List()
+	if err != nil {
+		return "", err
+	}
+	for key, info := range shared {
+		if info.CatalogService(key) != service {
+			continue
+		}
+		if slices.ContainsFunc(info.Projects, func(ref registry.ProjectRef) bool { return ref.Name == project }) {
+			return info.Name, nil
+		}
+	}
+	return "", pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeNotFound, pkgerrors.FieldServiceName, messages.ProxyNotRunning, service, project)
+}
@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Settings is what the proxy remembers between runs in ~/.otto-stack, so
+// the hostnames web-interfaces prints stay the ones it serves
+type Settings struct {
+	Port int `yaml:"port"`
+}
+
+// LoadSettings reads the settings in ottoStackHome, normally ~/.otto-stack.
+// Missing settings are the defaults.
+func LoadSettings(ottoStackHome string) (Settings, error) {
+	settings := Settings{Port: DefaultPort}
+	data, err := os.ReadFile(filepath.Join(ottoStackHome, core.ProxySettingsFile))
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProxySettingsLoadFailed, err)
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return settings, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProxySettingsLoadFailed, err)
+	}
+	if settings.Port == 0 {
+		settings.Port = DefaultPort
+	}
+	return settings, nil
+}
+
+// SaveSettings writes the settings to ottoStackHome
+func SaveSettings(ottoStackHome string, settings Settings) error {
+	data, err := yaml.Marshal(settings)
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProxySettingsSaveFailed, err)
+	}
+	if err := os.MkdirAll(ottoStackHome, core.PermReadWriteExec); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDirectoryCreateFailed, err)
+	}
+	path := filepath.Join(ottoStackHome, core.ProxySettingsFile)
+	if err := os.WriteFile(path, append([]byte(core.ProxySettingsHeader), data...), core.PermReadWrite); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProxySettingsSaveFailed, err)
+	}
+	return nil
+}
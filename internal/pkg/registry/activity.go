@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Touch records that the project used its shared containers at now, as up,
+// status and logs do. Nothing is written for a project that uses none. The
+// read and the write happen under one lock, so a read-only command cannot
+// write back a stale copy over a registration another process just saved.
+func (m *Manager) Touch(projectName string, now time.Time) error {
+	return withLock(m.registryPath, func() error {
+		data, err := readIfExists(m.registryPath)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		data, _, err = migrate(data)
+		if err != nil {
+			return err
+		}
+		var registry Registry
+		if err := yaml.Unmarshal(data, &registry); err != nil {
+			// A corrupted registry is Load's to rebuild; activity can wait
+			return nil
+		}
+
+		touched := false
+		for _, container := range registry.Containers {
+			if slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == projectName }) {
+				container.touch(projectName, now)
+				touched = true
+			}
+		}
+		if !touched {
+			return nil
+		}
+
+		data, err = marshalRegistry(&registry)
+		if err != nil {
+			return err
+		}
+		return writeAtomic(m.registryPath, data, core.PermPrivate)
+	})
+}
+
+// touch records the project's activity and drops that of projects no longer
+// registered against the container
+func (c *ContainerInfo) touch(projectName string, now time.Time) {
+	if c.Activity == nil {
+		c.Activity = make(map[string]time.Time)
+	}
+	c.Activity[projectName] = now
+	maps.DeleteFunc(c.Activity, func(project string, _ time.Time) bool {
+		return !slices.ContainsFunc(c.Projects, func(r ProjectRef) bool { return r.Name == project })
+	})
+}
+
+// LastActive returns when a live project last used the container. Projects
+// registered before activity was tracked count from the container's last
+// update. It is zero when no live project uses the container.
+func (c *ContainerInfo) LastActive() time.Time {
+	var last time.Time
+	for _, ref := range filterLiveProjectRefs(c.Projects) {
+		active, ok := c.Activity[ref.Name]
+		if !ok {
+			active = c.UpdatedAt
+		}
+		if active.After(last) {
+			last = active
+		}
+	}
+	return last
+}
+
+// FindIdle returns the containers no live project has used since cutoff,
+// keyed by service
+func (m *Manager) FindIdle(cutoff time.Time) (map[string]*ContainerInfo, error) {
+	registry, err := m.Load()
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	idle := make(map[string]*ContainerInfo)
+	for service, container := range registry.Containers {
+		if container.LastActive().Before(cutoff) {
+			idle[service] = container
+		}
+	}
+	return idle, nil
+}
+
+// Forget removes a container from the registry once it no longer exists.
+// Credentials are kept, so projects get their databases back if it is
+// started again with its volumes.
+func (m *Manager) Forget(service string) error {
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	container, ok := registry.Containers[service]
+	if !ok {
+		return nil
+	}
+
+	delete(registry.Containers, service)
+	if err := m.Save(registry); err != nil {
+		return err
+	}
+	if err := m.appendHistory(removedEvents(time.Now(), service, container)); err != nil {
+		return err
+	}
+	return m.createOrUpdateSharedReadme(registry)
+}
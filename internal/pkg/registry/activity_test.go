@@ -0,0 +1,98 @@
+//go:build unit
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestManager_TouchRecordsActivity(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	shop := ProjectRef{Name: "shop", ConfigDir: t.TempDir()}
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", shop))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing", ConfigDir: t.TempDir()}))
+
+	later := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, manager.Touch("shop", later))
+	// A project with no shared containers changes nothing
+	require.NoError(t, manager.Touch("unknown", later))
+
+	postgres, err := manager.Get("postgres")
+	require.NoError(t, err)
+	assert.True(t, postgres.Activity["shop"].Equal(later))
+	assert.True(t, postgres.LastActive().Equal(later))
+
+	redis, err := manager.Get("redis")
+	require.NoError(t, err)
+	assert.NotContains(t, redis.Activity, "shop")
+}
+
+func TestManager_TouchHoldsTheRegistryLock(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop", ConfigDir: t.TempDir()}))
+
+	lock, err := os.OpenFile(filepath.Join(dir, core.SharedRegistryFile)+lockSuffix, os.O_RDWR, core.PermPrivate)
+	require.NoError(t, err)
+	defer func() { _ = lock.Close() }()
+	require.NoError(t, lockFile(lock))
+
+	assert.Error(t, manager.Touch("shop", time.Now()), "touch waits for a save in progress")
+	require.NoError(t, unlockFile(lock))
+	assert.NoError(t, manager.Touch("shop", time.Now()))
+}
+
+func TestManager_FindIdle(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop", ConfigDir: t.TempDir()}))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing", ConfigDir: t.TempDir()}))
+
+	now := time.Now()
+	require.NoError(t, manager.Touch("shop", now.Add(-100*time.Hour)))
+
+	idle, err := manager.FindIdle(now.Add(-72 * time.Hour))
+	require.NoError(t, err)
+	assert.Contains(t, idle, "postgres")
+	assert.NotContains(t, idle, "redis")
+}
+
+func TestContainerInfo_LastActive(t *testing.T) {
+	updated := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recent := updated.Add(48 * time.Hour)
+	live := t.TempDir()
+
+	// Projects registered before activity was tracked count from the last update
+	container := &ContainerInfo{UpdatedAt: updated, Projects: []ProjectRef{{Name: "shop", ConfigDir: live}}}
+	assert.True(t, container.LastActive().Equal(updated))
+
+	// Deleted projects do not keep a container alive
+	container = &ContainerInfo{
+		UpdatedAt: updated,
+		Projects:  []ProjectRef{{Name: "shop", ConfigDir: live}, {Name: "gone", ConfigDir: live + "/missing"}},
+		Activity:  map[string]time.Time{"shop": updated, "gone": recent},
+	}
+	assert.True(t, container.LastActive().Equal(updated))
+
+	container = &ContainerInfo{UpdatedAt: recent, Projects: []ProjectRef{{Name: "gone", ConfigDir: live + "/missing"}}}
+	assert.True(t, container.LastActive().IsZero())
+}
+
+func TestManager_Forget(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	require.NoError(t, manager.Forget("postgres"))
+	require.NoError(t, manager.Forget("postgres"))
+
+	info, err := manager.Get("postgres")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Provisioner creates and removes per-project resources on shared containers
+type Provisioner interface {
+	// Provision makes sure the project's resources exist on the container,
+	// reusing existing credentials when given. New resources do not clash
+	// with those taken by other projects. It returns nil for services it
+	// does not provision.
+	Provision(ctx context.Context, service, containerName, projectName string, existing *Credentials, taken map[string]*Credentials) (*Credentials, error)
+	// Drop removes the project's resources and their data from the container
+	Drop(ctx context.Context, service, containerName string, creds *Credentials) error
+}
+
+// SetProvisioner makes Register provision per-project resources, such as a
+// database and role for each project on a shared postgres
+func (m *Manager) SetProvisioner(p Provisioner) {
+	m.provisioner = p
+}
+
+// provision gives the project its resources on the container registered as
+// service, which runs catalogService, going by the credentials in snapshot.
+// Only recording the result reloads the registry, so registrations other
+// processes save while provisioning waits on the container are kept.
+func (m *Manager) provision(snapshot *Registry, service, catalogService, containerName, projectName string) error {
+	existing := snapshot.Credentials[service][projectName]
+	taken := make(map[string]*Credentials, len(snapshot.Credentials[service]))
+	for project, creds := range snapshot.Credentials[service] {
+		if project != projectName {
+			taken[project] = creds
+		}
+	}
+	creds, err := m.provisioner.Provision(context.Background(), catalogService, containerName, projectName, existing, taken)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry,
+			fmt.Sprintf(messages.ErrorsProvisionFailed, service, projectName), err)
+	}
+	if creds == nil || creds == existing {
+		return nil
+	}
+
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	if registry.Credentials[service] == nil {
+		registry.Credentials[service] = make(map[string]*Credentials)
+	}
+	registry.Credentials[service][projectName] = creds
+	return m.Save(registry)
+}
+
+// ProjectCredentials returns the credentials provisioned for a project,
+// keyed by service
+func (m *Manager) ProjectCredentials(projectName string) (map[string]*Credentials, error) {
+	registry, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make(map[string]*Credentials)
+	for service, byProject := range registry.Credentials {
+		if c, ok := byProject[projectName]; ok {
+			creds[service] = c
+		}
+	}
+	return creds, nil
+}
+
+// DropCredentials removes a project's resources from a shared container,
+// deleting their data, and forgets its credentials
+func (m *Manager) DropCredentials(ctx context.Context, service, projectName string) error {
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	creds := registry.Credentials[service][projectName]
+	if creds == nil {
+		return nil
+	}
+	if m.provisioner != nil {
+		containerName := core.SharedContainerPrefix + service
+		container := registry.Containers[service]
+		if container != nil {
+			containerName = container.Name
+		}
+		if err := m.provisioner.Drop(ctx, container.CatalogService(service), containerName, creds); err != nil {
+			return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry,
+				fmt.Sprintf(messages.ErrorsProvisionDropFailed, creds.Database, service), err)
+		}
+	}
+
+	delete(registry.Credentials[service], projectName)
+	if len(registry.Credentials[service]) == 0 {
+		delete(registry.Credentials, service)
+	}
+	return m.Save(registry)
+}
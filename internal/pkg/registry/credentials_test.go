@@ -0,0 +1,130 @@
+//go:build unit
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+// fakeProvisioner hands out credentials named after the project
+type fakeProvisioner struct {
+	err     error
+	calls   int
+	dropped []*Credentials
+	// during runs while provisioning, as another process would
+	during func()
+}
+
+func (f *fakeProvisioner) Provision(ctx context.Context, service, containerName, projectName string, existing *Credentials, taken map[string]*Credentials) (*Credentials, error) {
+	f.calls++
+	if f.during != nil {
+		f.during()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if service != "postgres" {
+		return nil, nil
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	return &Credentials{Database: projectName, User: projectName, Password: "pw-" + projectName}, nil
+}
+
+func (f *fakeProvisioner) Drop(ctx context.Context, service, containerName string, creds *Credentials) error {
+	f.dropped = append(f.dropped, creds)
+	return nil
+}
+
+func TestManager_RegisterProvisions(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	provisioner := &fakeProvisioner{}
+	manager.SetProvisioner(provisioner)
+
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+
+	creds, err := manager.ProjectCredentials("shop")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]*Credentials{"postgres": {Database: "shop", User: "shop", Password: "pw-shop"}}, creds)
+
+	// Credentials outlive the registration, so the next up reuses them
+	require.NoError(t, manager.Unregister("postgres", "shop"))
+	creds, err = manager.ProjectCredentials("shop")
+	require.NoError(t, err)
+	assert.Contains(t, creds, "postgres")
+
+	other, err := manager.ProjectCredentials("billing")
+	require.NoError(t, err)
+	assert.Empty(t, other)
+}
+
+func TestManager_RegisterKeepsRegistrationsMadeWhileProvisioning(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	other := NewManager(dir)
+	manager.SetProvisioner(&fakeProvisioner{during: func() {
+		require.NoError(t, other.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing"}))
+	}})
+
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	shared, err := manager.IsShared("redis")
+	require.NoError(t, err)
+	assert.True(t, shared, "the other registration survives")
+	creds, err := manager.ProjectCredentials("shop")
+	require.NoError(t, err)
+	assert.Contains(t, creds, "postgres")
+}
+
+func TestManager_SaveKeepsRegistryPrivate(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	manager.SetProvisioner(&fakeProvisioner{})
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	info, err := os.Stat(filepath.Join(dir, core.SharedRegistryFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(core.PermPrivate), info.Mode().Perm(), "the registry holds passwords")
+}
+
+func TestManager_RegisterProvisionFailureStillRegisters(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	manager.SetProvisioner(&fakeProvisioner{err: errors.New("not ready")})
+
+	err := manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not ready")
+
+	shared, err := manager.IsShared("postgres")
+	require.NoError(t, err)
+	assert.True(t, shared)
+}
+
+func TestManager_DropCredentials(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	provisioner := &fakeProvisioner{}
+	manager.SetProvisioner(provisioner)
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	require.NoError(t, manager.DropCredentials(context.Background(), "postgres", "shop"))
+	require.Len(t, provisioner.dropped, 1)
+	assert.Equal(t, "shop", provisioner.dropped[0].Database)
+
+	creds, err := manager.ProjectCredentials("shop")
+	require.NoError(t, err)
+	assert.Empty(t, creds)
+
+	require.NoError(t, manager.DropCredentials(context.Background(), "postgres", "shop"), "dropping twice is a no-op")
+	assert.Len(t, provisioner.dropped, 1)
+}
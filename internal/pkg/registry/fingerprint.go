@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// fingerprintImageKey is where a container definition names its image
+const fingerprintImageKey = "image"
+
+// fingerprintHashLength keeps hashes short enough to read in the registry
+const fingerprintHashLength = 16
+
+// Fingerprint is the effective configuration of a shared container: its
+// image, the definition flattened to dotted keys, and a hash of that
+type Fingerprint struct {
+	Image  string
+	Hash   string
+	Config map[string]string
+}
+
+// NewFingerprint flattens a container definition, such as a compose service,
+// and hashes it
+func NewFingerprint(definition map[string]any) Fingerprint {
+	config := make(map[string]string)
+	flatten("", definition, config)
+
+	hash := sha256.New()
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		fmt.Fprintf(hash, "%s=%s\n", key, config[key])
+	}
+	return Fingerprint{
+		Image:  config[fingerprintImageKey],
+		Hash:   hex.EncodeToString(hash.Sum(nil))[:fingerprintHashLength],
+		Config: config,
+	}
+}
+
+// flatten writes nested maps as dotted keys and lists as space-separated values
+func flatten(prefix string, value any, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			flatten(joinKey(prefix, key), child, out)
+		}
+	case map[string]string:
+		for key, child := range v {
+			out[joinKey(prefix, key)] = child
+		}
+	case []string:
+		out[prefix] = strings.Join(v, " ")
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		out[prefix] = strings.Join(items, " ")
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// ConfigDifference is a setting on which a registered shared container and
+// a project disagree. An empty value means the setting is absent.
+type ConfigDifference struct {
+	Key        string `json:"key"`
+	Registered string `json:"registered"`
+	Requested  string `json:"requested"`
+}
+
+// Diff lists the settings that differ between the registered fingerprint f
+// and a requested one, sorted by key
+func (f Fingerprint) Diff(requested Fingerprint) []ConfigDifference {
+	keys := make(map[string]bool)
+	for key := range f.Config {
+		keys[key] = true
+	}
+	for key := range requested.Config {
+		keys[key] = true
+	}
+
+	var diffs []ConfigDifference
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		if f.Config[key] != requested.Config[key] {
+			diffs = append(diffs, ConfigDifference{Key: key, Registered: f.Config[key], Requested: requested.Config[key]})
+		}
+	}
+	return diffs
+}
+
+// ContainerSpec is what a project asks of a shared container when it
+// registers. The first project's spec is recorded; later ones are compared
+// against it.
+type ContainerSpec struct {
+	// Service is the catalog service, which differs from the registry key
+	// for a second instance
+	Service     string
+	Fingerprint Fingerprint
+	// Ports are the host ports a second instance publishes, keyed by the
+	// environment variable that names them
+	Ports map[string]string
+}
+
+// record stores the spec on a container registered without one
+func (c *ContainerInfo) record(key string, spec *ContainerSpec) {
+	if spec.Service != "" && spec.Service != key {
+		c.Service = spec.Service
+	}
+	if c.ConfigHash == "" {
+		c.Image = spec.Fingerprint.Image
+		c.ConfigHash = spec.Fingerprint.Hash
+		c.Config = spec.Fingerprint.Config
+	}
+	if len(c.Ports) == 0 && len(spec.Ports) > 0 {
+		c.Ports = spec.Ports
+	}
+}
+
+// Compare returns how a registered shared container's configuration differs
+// from the one requested. Nothing is reported for a container that is not
+// registered or was registered before configurations were recorded.
+func (m *Manager) Compare(service string, requested Fingerprint) ([]ConfigDifference, error) {
+	registry, err := m.Load()
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	container := registry.Containers[service]
+	if container == nil || container.ConfigHash == "" || container.ConfigHash == requested.Hash {
+		return nil, nil
+	}
+	registered := Fingerprint{Image: container.Image, Hash: container.ConfigHash, Config: container.Config}
+	return registered.Diff(requested), nil
+}
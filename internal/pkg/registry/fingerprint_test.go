@@ -0,0 +1,106 @@
+//go:build unit
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFingerprint_FlattensAndHashes(t *testing.T) {
+	fingerprint := NewFingerprint(map[string]any{
+		"image":       "postgres:15-alpine",
+		"environment": map[string]any{"POSTGRES_USER": "postgres"},
+		"ports":       []any{"5432:5432"},
+		"command":     []string{"postgres", "-c", "max_connections=200"},
+	})
+
+	assert.Equal(t, "postgres:15-alpine", fingerprint.Image)
+	assert.Len(t, fingerprint.Hash, fingerprintHashLength)
+	assert.Equal(t, map[string]string{
+		"image":                     "postgres:15-alpine",
+		"environment.POSTGRES_USER": "postgres",
+		"ports":                     "5432:5432",
+		"command":                   "postgres -c max_connections=200",
+	}, fingerprint.Config)
+
+	same := NewFingerprint(map[string]any{
+		"command":     []string{"postgres", "-c", "max_connections=200"},
+		"ports":       []any{"5432:5432"},
+		"environment": map[string]any{"POSTGRES_USER": "postgres"},
+		"image":       "postgres:15-alpine",
+	})
+	assert.Equal(t, fingerprint.Hash, same.Hash)
+
+	other := NewFingerprint(map[string]any{"image": "postgres:16-alpine"})
+	assert.NotEqual(t, fingerprint.Hash, other.Hash)
+}
+
+func TestFingerprint_Diff(t *testing.T) {
+	registered := NewFingerprint(map[string]any{"image": "postgres:15", "environment": map[string]any{"A": "1", "B": "2"}})
+	requested := NewFingerprint(map[string]any{"image": "postgres:16", "environment": map[string]any{"A": "1", "C": "3"}})
+
+	assert.Equal(t, []ConfigDifference{
+		{Key: "environment.B", Registered: "2", Requested: ""},
+		{Key: "environment.C", Registered: "", Requested: "3"},
+		{Key: "image", Registered: "postgres:15", Requested: "postgres:16"},
+	}, registered.Diff(requested))
+	assert.Empty(t, registered.Diff(registered))
+}
+
+func TestManager_RegisterWithSpecRecordsFirstConfiguration(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	pg15 := NewFingerprint(map[string]any{"image": "postgres:15"})
+	pg16 := NewFingerprint(map[string]any{"image": "postgres:16"})
+
+	require.NoError(t, manager.RegisterWithSpec("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}, &ContainerSpec{Service: "postgres", Fingerprint: pg15}))
+	// A later project does not replace the recorded configuration
+	require.NoError(t, manager.RegisterWithSpec("postgres", "otto-stack-postgres", ProjectRef{Name: "billing"}, &ContainerSpec{Service: "postgres", Fingerprint: pg16}))
+
+	info, err := manager.Get("postgres")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres:15", info.Image)
+	assert.Equal(t, pg15.Hash, info.ConfigHash)
+	assert.Empty(t, info.Service)
+	assert.Equal(t, "postgres", info.CatalogService("postgres"))
+
+	diffs, err := manager.Compare("postgres", pg16)
+	require.NoError(t, err)
+	assert.Equal(t, []ConfigDifference{{Key: "image", Registered: "postgres:15", Requested: "postgres:16"}}, diffs)
+
+	diffs, err = manager.Compare("postgres", pg15)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
+
+func TestManager_RegisterWithSpecSecondInstance(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	spec := &ContainerSpec{
+		Service:     "postgres",
+		Fingerprint: NewFingerprint(map[string]any{"image": "postgres:16"}),
+		Ports:       map[string]string{"POSTGRES_PORT": "5433"},
+	}
+	require.NoError(t, manager.RegisterWithSpec("postgres-16", "otto-stack-postgres-16", ProjectRef{Name: "shop"}, spec))
+
+	info, err := manager.Get("postgres-16")
+	require.NoError(t, err)
+	assert.Equal(t, "postgres", info.CatalogService("postgres-16"))
+	assert.Equal(t, map[string]string{"POSTGRES_PORT": "5433"}, info.Ports)
+}
+
+func TestManager_CompareUnrecorded(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	requested := NewFingerprint(map[string]any{"image": "redis:7"})
+
+	diffs, err := manager.Compare("redis", requested)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	// Containers registered before configurations were recorded are not compared
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+	diffs, err = manager.Compare("redis", requested)
+	require.NoError(t, err)
+	assert.Empty(t, diffs)
+}
@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Actions recorded in the registry history
+const (
+	ActionRegister   = "register"
+	ActionUnregister = "unregister"
+)
+
+// Reasons a project was registered or unregistered other than up and down
+const (
+	ReasonAdopted  = "adopted"  // shared adopt
+	ReasonMoved    = "moved"    // shared move, to run the service itself
+	ReasonOrphaned = "orphaned" // the project's directory is gone
+	ReasonRemoved  = "removed"  // the container was removed, as by shared gc
+)
+
+// Event is one change to which projects use a shared container, as kept in
+// the append-only history next to the registry
+type Event struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	Service   string    `json:"service"`
+	Container string    `json:"container"`
+	Project   string    `json:"project"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// HistoryFilter narrows History to a service, a project or both. Empty
+// fields match everything.
+type HistoryFilter struct {
+	Service string
+	Project string
+}
+
+func (f HistoryFilter) matches(event Event) bool {
+	return (f.Service == "" || event.Service == f.Service) &&
+		(f.Project == "" || event.Project == f.Project)
+}
+
+// History returns the recorded events matching filter, oldest first. Lines
+// that cannot be read are skipped, so a torn write loses only itself.
+func (m *Manager) History(filter HistoryFilter) ([]Event, error) {
+	data, err := readLocked(m.historyPath())
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	events := []Event{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		if filter.matches(event) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// appendHistory adds events to the history under its lock
+func (m *Manager) appendHistory(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryHistoryFailed, err)
+		}
+	}
+
+	path := m.historyPath()
+	if err := os.MkdirAll(filepath.Dir(path), core.PermReadWriteExec); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDirectoryCreateFailed, err)
+	}
+	return withLock(path, func() error {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, core.PermReadWrite)
+		if err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryHistoryFailed, err)
+		}
+		defer func() { _ = f.Close() }()
+
+		if _, err := f.Write(buf.Bytes()); err != nil {
+			return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryHistoryFailed, err)
+		}
+		return nil
+	})
+}
+
+func (m *Manager) historyPath() string {
+	return filepath.Join(filepath.Dir(m.registryPath), core.SharedHistoryFile)
+}
+
+// newEvent describes a change to a container's projects at now
+func newEvent(now time.Time, action, service string, container *ContainerInfo, project, reason string) Event {
+	return Event{
+		Time:      now,
+		Action:    action,
+		Service:   service,
+		Container: container.Name,
+		Project:   project,
+		Reason:    reason,
+	}
+}
+
+// removedEvents unregisters every project of a container dropped from the
+// registry
+func removedEvents(now time.Time, service string, container *ContainerInfo) []Event {
+	events := make([]Event, 0, len(container.Projects))
+	for _, ref := range container.Projects {
+		events = append(events, newEvent(now, ActionUnregister, service, container, ref.Name, ReasonRemoved))
+	}
+	return events
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestManager_HistoryRecordsRegistrations(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing"}))
+	require.NoError(t, manager.Unregister("postgres", "shop"))
+	require.NoError(t, manager.Unregister("postgres", "shop"))
+
+	events, err := manager.History(HistoryFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, ActionRegister, events[0].Action)
+	assert.Equal(t, "otto-stack-postgres", events[0].Container)
+	assert.Equal(t, "shop", events[0].Project)
+	assert.Equal(t, ActionUnregister, events[2].Action)
+	assert.False(t, events[2].Time.Before(events[0].Time))
+
+	events, err = manager.History(HistoryFilter{Project: "billing"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "redis", events[0].Service)
+
+	events, err = manager.History(HistoryFilter{Service: "postgres", Project: "billing"})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestManager_HistoryRecordsOrphanCleanup(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	gone := ProjectRef{Name: "old", ConfigDir: filepath.Join(dir, "missing", core.OttoStackDir)}
+
+	reg := NewRegistry()
+	reg.Containers["postgres"] = &ContainerInfo{Name: "otto-stack-postgres", Projects: []ProjectRef{gone}}
+	require.NoError(t, manager.Save(reg))
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	events, err := manager.History(HistoryFilter{Project: "old"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, ActionUnregister, events[0].Action)
+	assert.Equal(t, ReasonOrphaned, events[0].Reason)
+}
+
+func TestManager_HistorySkipsUnreadableLines(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+
+	f, err := os.OpenFile(manager.historyPath(), os.O_WRONLY|os.O_APPEND, core.PermReadWrite)
+	require.NoError(t, err)
+	_, err = f.WriteString("{\"time\": \"torn\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	events, err := manager.History(HistoryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestManager_HistoryEmpty(t *testing.T) {
+	events, err := NewManager(t.TempDir()).History(HistoryFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
@@ -0,0 +1,150 @@
+package registry
+
+import (
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Adopt registers an existing container under service for the given
+// projects, for a container otto-stack did not start or lost track of. The
+// configuration it runs is recorded by the next up that uses it.
+func (m *Manager) Adopt(service, containerName, image string, projects []ProjectRef) error {
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	if existing, ok := registry.Containers[service]; ok {
+		return pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeAlreadyExists, pkgerrors.FieldServiceName,
+			messages.ErrorsSharedAlreadyRegistered, service, existing.Name)
+	}
+
+	now := time.Now()
+	container := &ContainerInfo{
+		Name:      containerName,
+		Projects:  []ProjectRef{},
+		Image:     image,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	var events []Event
+	for _, project := range projects {
+		if !slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == project.Name }) {
+			container.Projects = append(container.Projects, project)
+			events = append(events, newEvent(now, ActionRegister, service, container, project.Name, ReasonAdopted))
+		}
+		container.touch(project.Name, now)
+	}
+	registry.Containers[service] = container
+
+	if err := m.Save(registry); err != nil {
+		return err
+	}
+	if err := m.appendHistory(events); err != nil {
+		return err
+	}
+	return m.createOrUpdateSharedReadme(registry)
+}
+
+// MoveOut unregisters a project that now runs service itself. Its
+// credentials stay, filed under the catalog service, as they came along with
+// the data and the project-local container uses them.
+func (m *Manager) MoveOut(service, projectName string) error {
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	container := registry.Containers[service]
+	if catalog := container.CatalogService(service); catalog != service {
+		if creds := registry.Credentials[service][projectName]; creds != nil {
+			delete(registry.Credentials[service], projectName)
+			if len(registry.Credentials[service]) == 0 {
+				delete(registry.Credentials, service)
+			}
+			if registry.Credentials[catalog] == nil {
+				registry.Credentials[catalog] = make(map[string]*Credentials)
+			}
+			registry.Credentials[catalog][projectName] = creds
+		}
+	}
+	var events []Event
+	if container != nil {
+		now := time.Now()
+		if slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == projectName }) {
+			events = append(events, newEvent(now, ActionUnregister, service, container, projectName, ReasonMoved))
+		}
+		container.Projects = removeProject(container.Projects, projectName)
+		container.releaseNamespaces()
+		container.UpdatedAt = now
+		if len(container.Projects) == 0 {
+			delete(registry.Containers, service)
+		}
+	}
+
+	if err := m.Save(registry); err != nil {
+		return err
+	}
+	if err := m.appendHistory(events); err != nil {
+		return err
+	}
+	return m.createOrUpdateSharedReadme(registry)
+}
+
+// Export returns the registry as YAML, credentials included, for moving it
+// to another machine or keeping a copy
+func (m *Manager) Export() ([]byte, error) {
+	registry, err := m.Load()
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	data, err := yaml.Marshal(registry)
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryMarshalFailed, err)
+	}
+	return data, nil
+}
+
+// Import merges an exported registry, migrated to the current format, into
+// this one. Containers and credentials in data replace the entries of the
+// same name; others are kept. It returns the number of containers imported.
+func (m *Manager) Import(data []byte) (int, error) {
+	data, _, err := migrate(data)
+	if err != nil {
+		return 0, err
+	}
+	var imported Registry
+	if err := yaml.Unmarshal(data, &imported); err != nil {
+		return 0, pkgerrors.NewValidationError(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ErrorsRegistryImportInvalid, err)
+	}
+	for service, container := range imported.Containers {
+		if container == nil || container.Name == "" {
+			return 0, pkgerrors.NewValidationErrorf(pkgerrors.ErrCodeInvalid, pkgerrors.FieldArgs, messages.ErrorsRegistryImportNoName, service)
+		}
+	}
+
+	registry, err := m.Load()
+	if err != nil {
+		return 0, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+	for service, container := range imported.Containers {
+		registry.Containers[service] = container
+	}
+	for service, byProject := range imported.Credentials {
+		if registry.Credentials[service] == nil {
+			registry.Credentials[service] = make(map[string]*Credentials)
+		}
+		for project, creds := range byProject {
+			registry.Credentials[service][project] = creds
+		}
+	}
+
+	if err := m.Save(registry); err != nil {
+		return 0, err
+	}
+	return len(imported.Containers), m.createOrUpdateSharedReadme(registry)
+}
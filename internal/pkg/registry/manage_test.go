@@ -0,0 +1,85 @@
+//go:build unit
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Adopt(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	shop := ProjectRef{Name: "shop"}
+
+	require.NoError(t, manager.Adopt("postgres", "otto-stack-postgres", "postgres:16", []ProjectRef{shop, shop}))
+
+	postgres, err := manager.Get("postgres")
+	require.NoError(t, err)
+	assert.Equal(t, "otto-stack-postgres", postgres.Name)
+	assert.Equal(t, "postgres:16", postgres.Image)
+	assert.Equal(t, []ProjectRef{shop}, postgres.Projects)
+	assert.Contains(t, postgres.Activity, "shop")
+
+	err = manager.Adopt("postgres", "otto-stack-postgres", "postgres:16", []ProjectRef{shop})
+	assert.Error(t, err)
+}
+
+func TestManager_MoveOutKeepsCredentials(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	reg := NewRegistry()
+	reg.Containers["postgres-16"] = &ContainerInfo{
+		Name:     "otto-stack-postgres-16",
+		Service:  "postgres",
+		Projects: []ProjectRef{{Name: "shop"}, {Name: "billing"}},
+	}
+	reg.Credentials["postgres-16"] = map[string]*Credentials{
+		"shop":    {Database: "shop", User: "shop", Password: "secret"},
+		"billing": {Database: "billing", User: "billing", Password: "other"},
+	}
+	require.NoError(t, manager.Save(reg))
+
+	require.NoError(t, manager.MoveOut("postgres-16", "shop"))
+
+	loaded, err := manager.Load()
+	require.NoError(t, err)
+	assert.Equal(t, []ProjectRef{{Name: "billing"}}, loaded.Containers["postgres-16"].Projects)
+	assert.Equal(t, "secret", loaded.Credentials["postgres"]["shop"].Password)
+	assert.NotContains(t, loaded.Credentials["postgres-16"], "shop")
+	assert.Contains(t, loaded.Credentials["postgres-16"], "billing")
+}
+
+func TestManager_ExportImport(t *testing.T) {
+	source := NewManager(t.TempDir())
+	reg := NewRegistry()
+	reg.Containers["postgres"] = &ContainerInfo{Name: "otto-stack-postgres", Projects: []ProjectRef{{Name: "shop"}}}
+	reg.Credentials["postgres"] = map[string]*Credentials{"shop": {Database: "shop", User: "shop", Password: "secret"}}
+	require.NoError(t, source.Save(reg))
+
+	data, err := source.Export()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "secret")
+
+	target := NewManager(t.TempDir())
+	require.NoError(t, target.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing"}))
+	count, err := target.Import(data)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	loaded, err := target.Load()
+	require.NoError(t, err)
+	assert.Contains(t, loaded.Containers, "redis")
+	assert.Equal(t, "otto-stack-postgres", loaded.Containers["postgres"].Name)
+	assert.Equal(t, "secret", loaded.Credentials["postgres"]["shop"].Password)
+}
+
+func TestManager_ImportRejectsInvalid(t *testing.T) {
+	manager := NewManager(t.TempDir())
+
+	_, err := manager.Import([]byte("shared_containers: [not, a, map]"))
+	assert.Error(t, err)
+
+	_, err = manager.Import([]byte("shared_containers:\n  postgres:\n    projects: []\n"))
+	assert.Error(t, err)
+}
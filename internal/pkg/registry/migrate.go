@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// RegistryVersion is the registry format this otto-stack writes. Raising it
+// needs a migration from the previous version.
+const RegistryVersion = 1
+
+// migrations upgrade a registry one version at a time: migrations[i] turns a
+// version i document into version i+1. Registries written before versioning
+// are version 0.
+var migrations = []func(doc map[string]any){
+	migrateProjectRefs,
+}
+
+// readMigrated reads the registry at path like readLocked and, holding the
+// same lock, rewrites it in the current format when it is older. The rewrite
+// goes through writeAtomic, so a failure leaves the old registry in place. A
+// registry that is not valid YAML is returned as read, for Load to rebuild.
+func readMigrated(path string) ([]byte, error) {
+	var data []byte
+	err := withLock(path, func() error {
+		read, err := readIfExists(path)
+		if err != nil || len(read) == 0 {
+			data = read
+			return err
+		}
+		migrated, changed, err := migrate(read)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err := writeAtomic(path, migrated, core.PermPrivate); err != nil {
+				return err
+			}
+		}
+		data = migrated
+		return nil
+	})
+	return data, err
+}
+
+// migrate brings a registry document to RegistryVersion, reporting whether it
+// changed. It refuses a registry written by a newer otto-stack rather than
+// lose what that version added.
+func migrate(data []byte) ([]byte, bool, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil || doc == nil {
+		return data, false, nil
+	}
+
+	version, _ := doc["version"].(int)
+	if version > RegistryVersion {
+		return nil, false, pkgerrors.NewSystemError(pkgerrors.ErrCodeInvalid,
+			fmt.Sprintf(messages.ErrorsRegistryVersionNewer, version, RegistryVersion), nil)
+	}
+	if version == RegistryVersion {
+		return data, false, nil
+	}
+
+	for _, migration := range migrations[version:] {
+		migration(doc)
+	}
+	doc["version"] = RegistryVersion
+	migrated, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, false, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, fmt.Sprintf(messages.ErrorsRegistryMigrateFailed, RegistryVersion), err)
+	}
+	return append([]byte(core.RegistryHeader), migrated...), true, nil
+}
+
+// migrateProjectRefs turns the project names early registries listed into
+// project references. Their config directory is unknown, so orphan cleanup
+// keeps them until they unregister.
+func migrateProjectRefs(doc map[string]any) {
+	containers, _ := doc["shared_containers"].(map[string]any)
+	for _, entry := range containers {
+		container, _ := entry.(map[string]any)
+		projects, _ := container["projects"].([]any)
+		for i, project := range projects {
+			if name, ok := project.(string); ok {
+				projects[i] = map[string]any{"name": name, "config_dir": ""}
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+//go:build unit
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestMigrations_CoverEveryVersion(t *testing.T) {
+	assert.Len(t, migrations, RegistryVersion)
+}
+
+func TestManager_LoadMigratesUnversionedRegistry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, core.SharedRegistryFile)
+	legacy := `shared_containers:
+  postgres:
+    name: otto-stack-postgres
+    projects:
+      - shop
+      - name: billing
+        config_dir: /work/billing/.otto-stack
+`
+	require.NoError(t, os.WriteFile(path, []byte(legacy), core.PermReadWrite))
+
+	loaded, err := NewManager(dir).Load()
+	require.NoError(t, err)
+	assert.Equal(t, RegistryVersion, loaded.Version)
+	assert.Equal(t, []ProjectRef{
+		{Name: "shop"},
+		{Name: "billing", ConfigDir: "/work/billing/.otto-stack"},
+	}, loaded.Containers["postgres"].Projects)
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), "version: 1")
+	assert.Contains(t, string(written), "name: shop")
+}
+
+func TestManager_LoadKeepsRegistryWhenMigrationWriteFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, core.SharedRegistryFile)
+	legacy := []byte("shared_containers:\n  redis:\n    name: otto-stack-redis\n    projects:\n      - shop\n")
+	require.NoError(t, os.WriteFile(path, legacy, core.PermReadWrite))
+	// A directory in the temp file's place makes the rewrite fail
+	require.NoError(t, os.Mkdir(path+".tmp", core.PermReadWriteExec))
+
+	_, err := NewManager(dir).Load()
+	assert.Error(t, err)
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacy, written)
+}
+
+func TestManager_LoadSharesLockWithSave(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	require.NoError(t, manager.Save(NewRegistry()))
+
+	lock, err := os.OpenFile(filepath.Join(dir, core.SharedRegistryFile)+lockSuffix, os.O_RDWR, core.PermPrivate)
+	require.NoError(t, err)
+	defer func() { _ = lock.Close() }()
+	require.NoError(t, lockFile(lock))
+
+	_, err = manager.Load()
+	assert.Error(t, err, "load and save share one lock")
+
+	require.NoError(t, unlockFile(lock))
+	_, err = manager.Load()
+	assert.NoError(t, err)
+}
+
+func TestManager_LoadRefusesNewerRegistry(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, core.SharedRegistryFile)
+	newer := []byte("version: 99\nshared_containers: {}\n")
+	require.NoError(t, os.WriteFile(path, newer, core.PermReadWrite))
+
+	_, err := NewManager(dir).Load()
+	assert.Error(t, err)
+
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, newer, written)
+}
+
+func TestManager_SaveWritesVersion(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager(dir)
+	require.NoError(t, manager.Save(&Registry{Containers: map[string]*ContainerInfo{}}))
+
+	loaded, err := manager.Load()
+	require.NoError(t, err)
+	assert.Equal(t, RegistryVersion, loaded.Version)
+}
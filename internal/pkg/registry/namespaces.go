@@ -0,0 +1,82 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Namespacer decides which part of a shared container each project gets
+type Namespacer interface {
+	// Allocate returns a namespace for the project that does not clash with
+	// those taken by other projects. It returns nil for services it does not
+	// namespace.
+	Allocate(service, projectName string, taken map[string]*Namespace) (*Namespace, error)
+}
+
+// SetNamespacer makes Register allocate each project a namespace, such as
+// its own logical database on a shared redis
+func (m *Manager) SetNamespacer(n Namespacer) {
+	m.namespacer = n
+}
+
+// allocateNamespace gives the project a namespace on the container unless it
+// already holds one
+func (m *Manager) allocateNamespace(container *ContainerInfo, service, projectName string) error {
+	if container.Namespaces[projectName] != nil {
+		return nil
+	}
+
+	taken := make(map[string]*Namespace, len(container.Namespaces))
+	for project, ns := range container.Namespaces {
+		if project != projectName {
+			taken[project] = ns
+		}
+	}
+	ns, err := m.namespacer.Allocate(container.CatalogService(service), projectName, taken)
+	if err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry,
+			fmt.Sprintf(messages.ErrorsNamespaceAllocateFailed, service, projectName), err)
+	}
+	if ns == nil {
+		return nil
+	}
+
+	if container.Namespaces == nil {
+		container.Namespaces = make(map[string]*Namespace)
+	}
+	container.Namespaces[projectName] = ns
+	return nil
+}
+
+// releaseNamespaces frees the namespaces of projects no longer registered
+// against the container
+func (c *ContainerInfo) releaseNamespaces() {
+	for project := range c.Namespaces {
+		if !slices.ContainsFunc(c.Projects, func(r ProjectRef) bool { return r.Name == project }) {
+			delete(c.Namespaces, project)
+		}
+	}
+	if len(c.Namespaces) == 0 {
+		c.Namespaces = nil
+	}
+}
+
+// ProjectNamespaces returns the namespaces allocated to a project, keyed by
+// service
+func (m *Manager) ProjectNamespaces(projectName string) (map[string]*Namespace, error) {
+	registry, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make(map[string]*Namespace)
+	for service, info := range registry.Containers {
+		if ns, ok := info.Namespaces[projectName]; ok {
+			namespaces[service] = ns
+		}
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,94 @@
+//go:build unit
+
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNamespacer numbers projects in the order they register
+type fakeNamespacer struct {
+	err error
+}
+
+func (f fakeNamespacer) Allocate(service, projectName string, taken map[string]*Namespace) (*Namespace, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if service != "redis" {
+		return nil, nil
+	}
+	db := len(taken) + 1
+	return &Namespace{Database: &db, Prefix: projectName + ":"}, nil
+}
+
+func TestManager_RegisterAllocatesNamespaces(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	manager.SetNamespacer(fakeNamespacer{})
+
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing"}))
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	// Registering again keeps the namespace already held
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+
+	shop, err := manager.ProjectNamespaces("shop")
+	require.NoError(t, err)
+	require.Contains(t, shop, "redis")
+	assert.NotContains(t, shop, "postgres")
+	assert.Equal(t, 1, *shop["redis"].Database)
+	assert.Equal(t, "shop:", shop["redis"].Prefix)
+
+	billing, err := manager.ProjectNamespaces("billing")
+	require.NoError(t, err)
+	assert.Equal(t, 2, *billing["redis"].Database)
+}
+
+func TestManager_UnregisterReleasesNamespace(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	manager.SetNamespacer(fakeNamespacer{})
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "billing"}))
+
+	require.NoError(t, manager.Unregister("redis", "shop"))
+
+	shop, err := manager.ProjectNamespaces("shop")
+	require.NoError(t, err)
+	assert.Empty(t, shop)
+
+	info, err := manager.Get("redis")
+	require.NoError(t, err)
+	assert.Len(t, info.Namespaces, 1)
+	assert.Contains(t, info.Namespaces, "billing")
+}
+
+func TestManager_RegisterNamespaceFailureStillRegisters(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	manager.SetNamespacer(fakeNamespacer{err: errors.New("no free database")})
+
+	err := manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no free database")
+
+	shared, err := manager.IsShared("redis")
+	require.NoError(t, err)
+	assert.True(t, shared)
+}
+
+func TestContainerInfo_ReleaseNamespaces(t *testing.T) {
+	db := 1
+	info := &ContainerInfo{
+		Projects:   []ProjectRef{{Name: "shop"}},
+		Namespaces: map[string]*Namespace{"shop": {Database: &db}, "gone": {Prefix: "gone-"}},
+	}
+	info.releaseNamespaces()
+	assert.Equal(t, map[string]*Namespace{"shop": {Database: &db}}, info.Namespaces)
+
+	info.Projects = nil
+	info.releaseNamespaces()
+	assert.Nil(t, info.Namespaces)
+}
@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// Networker connects shared containers to the networks of the projects
+// registered against them, so the projects' containers reach them by
+// service name
+type Networker interface {
+	// Attach connects the container to the project's network, where it is
+	// also reached by aliases
+	Attach(ctx context.Context, containerName, projectName string, aliases []string) error
+	// Detach disconnects the container from the project's network
+	Detach(ctx context.Context, containerName, projectName string) error
+}
+
+// SetNetworker makes Register connect the shared container to the
+// project's network and Unregister disconnect it again
+func (m *Manager) SetNetworker(n Networker) {
+	m.networker = n
+}
+
+// DetachProject disconnects the shared containers the project is registered
+// against from its network, keeping the registrations, so that the network
+// can be removed when the project goes down
+func (m *Manager) DetachProject(ctx context.Context, projectName string) error {
+	if m.networker == nil {
+		return nil
+	}
+	registry, err := m.Load()
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
+	}
+
+	var errs []error
+	for _, service := range slices.Sorted(maps.Keys(registry.Containers)) {
+		container := registry.Containers[service]
+		if !slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == projectName }) {
+			continue
+		}
+		if err := m.detach(ctx, container, service, projectName); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// attach connects the container registered as service to the project's network
+func (m *Manager) attach(container *ContainerInfo, service, projectName string) error {
+	if err := m.networker.Attach(context.Background(), container.Name, projectName, networkAliases(container, service)); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry,
+			fmt.Sprintf(messages.ErrorsNetworkAttachFailed, service, projectName), err)
+	}
+	return nil
+}
+
+// detach disconnects the container registered as service from the
+// project's network
+func (m *Manager) detach(ctx context.Context, container *ContainerInfo, service, projectName string) error {
+	if err := m.networker.Detach(ctx, container.Name, projectName); err != nil {
+		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentRegistry,
+			fmt.Sprintf(messages.ErrorsNetworkDetachFailed, service, projectName), err)
+	}
+	return nil
+}
+
+// networkAliases are the names a project's containers reach the container
+// registered as service by: the service, and the catalog service it runs
+// when that differs, as for a second instance such as postgres-16
+func networkAliases(container *ContainerInfo, service string) []string {
+	aliases := []string{service}
+	if catalog := container.CatalogService(service); catalog != service {
+		aliases = append(aliases, catalog)
+	}
+	return aliases
+}
@@ -0,0 +1,82 @@
+//go:build unit
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNetworker records what it is asked to do
+type fakeNetworker struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeNetworker) Attach(_ context.Context, containerName, projectName string, aliases []string) error {
+	f.calls = append(f.calls, "attach "+containerName+" "+projectName+" "+strings.Join(aliases, ","))
+	return f.err
+}
+
+func (f *fakeNetworker) Detach(_ context.Context, containerName, projectName string) error {
+	f.calls = append(f.calls, "detach "+containerName+" "+projectName)
+	return f.err
+}
+
+func TestManager_RegisterAttachesAndUnregisterDetaches(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	networker := &fakeNetworker{}
+	manager.SetNetworker(networker)
+
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.RegisterWithSpec("postgres-16", "otto-stack-postgres-16", ProjectRef{Name: "billing"}, &ContainerSpec{Service: "postgres"}))
+	require.NoError(t, manager.Unregister("postgres", "shop"))
+	// a project that was not registered has nothing to detach
+	require.NoError(t, manager.Unregister("postgres-16", "shop"))
+
+	assert.Equal(t, []string{
+		"attach otto-stack-postgres shop postgres",
+		"attach otto-stack-postgres-16 billing postgres-16,postgres",
+		"detach otto-stack-postgres shop",
+	}, networker.calls)
+}
+
+func TestManager_NetworkErrorsKeepRegistrations(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	networker := &fakeNetworker{err: errors.New("no daemon")}
+	manager.SetNetworker(networker)
+
+	require.Error(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+	container, err := manager.Get("redis")
+	require.NoError(t, err)
+	require.NotNil(t, container)
+
+	require.Error(t, manager.Unregister("redis", "shop"))
+	container, err = manager.Get("redis")
+	require.NoError(t, err)
+	assert.Nil(t, container)
+}
+
+func TestManager_DetachProject(t *testing.T) {
+	manager := NewManager(t.TempDir())
+	require.NoError(t, manager.Register("redis", "otto-stack-redis", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("postgres", "otto-stack-postgres", ProjectRef{Name: "shop"}))
+	require.NoError(t, manager.Register("kafka", "otto-stack-kafka", ProjectRef{Name: "billing"}))
+
+	// without a networker there is nothing to do
+	require.NoError(t, manager.DetachProject(context.Background(), "shop"))
+
+	networker := &fakeNetworker{}
+	manager.SetNetworker(networker)
+	require.NoError(t, manager.DetachProject(context.Background(), "shop"))
+	assert.Equal(t, []string{"detach otto-stack-postgres shop", "detach otto-stack-redis shop"}, networker.calls)
+
+	container, err := manager.Get("redis")
+	require.NoError(t, err)
+	assert.Len(t, container.Projects, 1)
+}
@@ -0,0 +1,124 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+)
+
+// ProjectEntry records one otto-stack project on this machine
+type ProjectEntry struct {
+	Name     string    `yaml:"name" json:"name"`
+	Path     string    `yaml:"path" json:"path"`
+	Services []string  `yaml:"services" json:"services"`
+	LastUsed time.Time `yaml:"last_used" json:"last_used"`
+}
+
+// ProjectIndex lists every project init or up has seen, keyed by project path.
+// Unlike the shared registry it includes projects that share nothing.
+type ProjectIndex struct {
+	Projects map[string]*ProjectEntry `yaml:"projects" json:"projects"`
+}
+
+// ProjectIndexManager reads and updates the project index in ~/.otto-stack
+type ProjectIndexManager struct {
+	indexPath string
+}
+
+// NewProjectIndexManager creates a manager for the index in ottoStackHome,
+// normally ~/.otto-stack
+func NewProjectIndexManager(ottoStackHome string) *ProjectIndexManager {
+	return &ProjectIndexManager{indexPath: filepath.Join(ottoStackHome, core.ProjectIndexFile)}
+}
+
+// Load reads the index from disk. A missing index is empty.
+func (m *ProjectIndexManager) Load() (*ProjectIndex, error) {
+	data, err := readLocked(m.indexPath)
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProjectIndexLoadFailed, err)
+	}
+
+	index := &ProjectIndex{}
+	if err := yaml.Unmarshal(data, index); err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProjectIndexLoadFailed, err)
+	}
+	if index.Projects == nil {
+		index.Projects = make(map[string]*ProjectEntry)
+	}
+	return index, nil
+}
+
+// Save writes the index to disk
+func (m *ProjectIndexManager) Save(index *ProjectIndex) error {
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsProjectIndexSaveFailed, err)
+	}
+	return writeLocked(m.indexPath, append([]byte(core.ProjectIndexHeader), data...), core.PermReadWrite)
+}
+
+// Touch adds or updates a project and marks it used now
+func (m *ProjectIndexManager) Touch(name, path string, services []string) error {
+	index, err := m.Load()
+	if err != nil {
+		return err
+	}
+
+	index.Projects[path] = &ProjectEntry{
+		Name:     name,
+		Path:     path,
+		Services: services,
+		LastUsed: time.Now(),
+	}
+	return m.Save(index)
+}
+
+// List returns the indexed projects, most recently used first
+func (m *ProjectIndexManager) List() ([]*ProjectEntry, error) {
+	index, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*ProjectEntry, 0, len(index.Projects))
+	for _, entry := range index.Projects {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastUsed.Equal(entries[j].LastUsed) {
+			return entries[i].LastUsed.After(entries[j].LastUsed)
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
+
+// Prune drops the projects whose directories no longer exist and returns
+// them
+func (m *ProjectIndexManager) Prune() ([]*ProjectEntry, error) {
+	index, err := m.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []*ProjectEntry
+	for path, entry := range index.Projects {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			pruned = append(pruned, entry)
+			delete(index.Projects, path)
+		}
+	}
+	if len(pruned) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(pruned, func(i, j int) bool { return pruned[i].Path < pruned[j].Path })
+	return pruned, m.Save(index)
+}
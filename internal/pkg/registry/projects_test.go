@@ -0,0 +1,90 @@
+//go:build unit
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+)
+
+func TestProjectIndexManager_TouchAndList(t *testing.T) {
+	home := t.TempDir()
+	index := NewProjectIndexManager(home)
+
+	entries, err := index.List()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a missing index is empty")
+
+	require.NoError(t, index.Touch("api", "/work/api", []string{"postgres"}))
+	require.NoError(t, index.Touch("web", "/work/web", []string{"redis"}))
+
+	entries, err = index.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "web", entries[0].Name, "most recently used first")
+	assert.Equal(t, []string{"redis"}, entries[0].Services)
+
+	require.NoError(t, index.Touch("api", "/work/api", []string{"postgres", "kafka"}))
+	entries, err = index.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "touching a known path updates it")
+	assert.Equal(t, "api", entries[0].Name)
+	assert.Equal(t, []string{"postgres", "kafka"}, entries[0].Services)
+
+	data, err := os.ReadFile(filepath.Join(home, core.ProjectIndexFile))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), core.GeneratedFileHeader))
+}
+
+func TestProjectIndexManager_ListOrdersTiesByPath(t *testing.T) {
+	index := NewProjectIndexManager(t.TempDir())
+	used := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, index.Save(&ProjectIndex{Projects: map[string]*ProjectEntry{
+		"/b": {Name: "b", Path: "/b", LastUsed: used},
+		"/a": {Name: "a", Path: "/a", LastUsed: used},
+	}}))
+
+	entries, err := index.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "/a", entries[0].Path)
+	assert.True(t, used.Equal(entries[0].LastUsed))
+}
+
+func TestProjectIndexManager_Prune(t *testing.T) {
+	index := NewProjectIndexManager(t.TempDir())
+	kept := t.TempDir()
+	gone := filepath.Join(t.TempDir(), "deleted")
+	require.NoError(t, index.Touch("kept", kept, nil))
+	require.NoError(t, index.Touch("gone", gone, nil))
+
+	pruned, err := index.Prune()
+	require.NoError(t, err)
+	require.Len(t, pruned, 1)
+	assert.Equal(t, gone, pruned[0].Path)
+
+	entries, err := index.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, kept, entries[0].Path)
+
+	pruned, err = index.Prune()
+	require.NoError(t, err)
+	assert.Empty(t, pruned)
+}
+
+func TestProjectIndexManager_LoadCorrupt(t *testing.T) {
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, core.ProjectIndexFile), []byte("projects: [\n"), core.PermReadWrite))
+
+	_, err := NewProjectIndexManager(home).List()
+	assert.Error(t, err)
+}
@@ -3,7 +3,6 @@ package registry
 import (
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"slices"
@@ -23,6 +22,9 @@ import (
 type Manager struct {
 	registryPath   string
 	orphanDetector *OrphanDetector
+	provisioner    Provisioner
+	namespacer     Namespacer
+	networker      Networker
 }
 
 // NewManager creates a new registry manager
@@ -36,23 +38,7 @@ func NewManager(sharedDir string) *Manager {
 
 // Load reads the registry from disk
 func (m *Manager) Load() (*Registry, error) {
-	// Open file for reading with lock
-	f, err := os.OpenFile(m.registryPath, os.O_RDONLY|os.O_CREATE, core.PermReadWrite)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return NewRegistry(), nil
-		}
-		return nil, err
-	}
-	defer func() { _ = f.Close() }()
-
-	// Acquire shared lock for reading
-	if err := lockFile(f); err != nil {
-		return nil, err
-	}
-	defer func() { _ = unlockFile(f) }()
-
-	data, err := io.ReadAll(f)
+	data, err := readMigrated(m.registryPath)
 	if err != nil {
 		return nil, err
 	}
@@ -70,59 +56,117 @@ func (m *Manager) Load() (*Registry, error) {
 	if registry.Containers == nil {
 		registry.Containers = make(map[string]*ContainerInfo)
 	}
+	if registry.Credentials == nil {
+		registry.Credentials = make(map[string]map[string]*Credentials)
+	}
 
 	return &registry, nil
 }
 
+// lockSuffix names the file that guards a registry file. Writes replace the
+// registry file by renaming over it, so the lock is held on a sibling that
+// stays put.
+const lockSuffix = ".lock"
+
+// withLock runs fn holding the exclusive lock that guards path. A directory
+// that does not exist yet holds nothing to guard, so fn runs unlocked.
+func withLock(path string, fn func() error) error {
+	f, err := os.OpenFile(path+lockSuffix, os.O_RDWR|os.O_CREATE, core.PermPrivate)
+	if os.IsNotExist(err) {
+		return fn()
+	}
+	if err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLockFailed, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := lockFile(f); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLockFailed, err)
+	}
+	defer func() { _ = unlockFile(f) }()
+
+	return fn()
+}
+
+// readLocked reads path under its lock. A missing file reads as empty.
+func readLocked(path string) ([]byte, error) {
+	var data []byte
+	err := withLock(path, func() error {
+		var err error
+		data, err = readIfExists(path)
+		return err
+	})
+	return data, err
+}
+
+// readIfExists reads path, treating a missing file as empty
+func readIfExists(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
 // Save writes the registry to disk
 func (m *Manager) Save(registry *Registry) error {
-	data, err := yaml.Marshal(registry)
+	data, err := marshalRegistry(registry)
 	if err != nil {
-		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryMarshalFailed, err)
+		return err
 	}
+	// The registry holds database passwords, so only the owner may read it
+	return writeLocked(m.registryPath, data, core.PermPrivate)
+}
 
-	// Prepend header comment
-	dataWithHeader := append([]byte(core.RegistryHeader), data...)
+// marshalRegistry encodes the registry in the current format, behind the
+// header comment
+func marshalRegistry(registry *Registry) ([]byte, error) {
+	registry.Version = RegistryVersion
+	data, err := yaml.Marshal(registry)
+	if err != nil {
+		return nil, pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryMarshalFailed, err)
+	}
+	return append([]byte(core.RegistryHeader), data...), nil
+}
 
+// writeLocked writes data atomically under path's lock, through writeAtomic
+func writeLocked(path string, data []byte, perm os.FileMode) error {
 	// Ensure directory exists
-	dir := filepath.Dir(m.registryPath)
+	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, core.PermReadWriteExec); err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsDirectoryCreateFailed, err)
 	}
+	return withLock(path, func() error { return writeAtomic(path, data, perm) })
+}
 
-	// Atomic write: write to temp file, then rename
-	tempPath := m.registryPath + ".tmp"
-	f, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, core.PermReadWrite)
+// writeAtomic writes data to a temp file with permissions perm that is then
+// renamed over path, so readers see the old or the new content and never a
+// partial write. The caller holds path's lock.
+func writeAtomic(path string, data []byte, perm os.FileMode) error {
+	tempPath := path + ".tmp"
+	f, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
 	if err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsFileWriteFailed, err)
 	}
 	defer func() { _ = os.Remove(tempPath) }() // Clean up temp file on error
 
-	// Acquire exclusive lock
-	if err := lockFile(f); err != nil {
-		_ = f.Close()
-		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLockFailed, err)
-	}
-
 	// Write data
-	if _, err := f.Write(dataWithHeader); err != nil {
-		_ = unlockFile(f)
+	if _, err := f.Write(data); err != nil {
 		_ = f.Close()
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsFileWriteFailed, err)
 	}
 
 	// Sync to disk
 	if err := f.Sync(); err != nil {
-		_ = unlockFile(f)
 		_ = f.Close()
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistrySyncFailed, err)
 	}
-
-	_ = unlockFile(f)
-	_ = f.Close()
+	if err := f.Close(); err != nil {
+		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsFileWriteFailed, err)
+	}
 
 	// Atomic rename
-	if err := os.Rename(tempPath, m.registryPath); err != nil {
+	if err := os.Rename(tempPath, path); err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistrySaveFailed, err)
 	}
 	return nil
@@ -130,15 +174,21 @@ func (m *Manager) Save(registry *Registry) error {
 
 // Register adds or updates a container in the registry.
 func (m *Manager) Register(service, containerName string, project ProjectRef) error {
+	return m.RegisterWithSpec(service, containerName, project, nil)
+}
+
+// RegisterWithSpec registers the project like Register and records spec on a
+// container that has none yet
+func (m *Manager) RegisterWithSpec(service, containerName string, project ProjectRef, spec *ContainerSpec) error {
 	registry, err := m.Load()
 	if err != nil {
 		return pkgerrors.NewSystemError(pkgerrors.ErrCodeOperationFail, messages.ErrorsRegistryLoadFailed, err)
 	}
 
 	// Remove stale project references before registering
-	m.cleanupOrphans(registry)
-
 	now := time.Now()
+	events := m.cleanupOrphans(registry, now)
+
 	container, exists := registry.Containers[service]
 
 	if !exists {
@@ -149,18 +199,49 @@ func (m *Manager) Register(service, containerName string, project ProjectRef) er
 			UpdatedAt: now,
 		}
 		registry.Containers[service] = container
+		events = append(events, newEvent(now, ActionRegister, service, container, project.Name, ""))
 	} else {
 		if !slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == project.Name }) {
 			container.Projects = append(container.Projects, project)
+			events = append(events, newEvent(now, ActionRegister, service, container, project.Name, ""))
 		}
 		container.UpdatedAt = now
 	}
+	if spec != nil {
+		container.record(service, spec)
+	}
+	container.touch(project.Name, now)
+
+	// A failed provisioning still registers the project; the next up retries it
+	var provisionErr error
+	if m.namespacer != nil {
+		provisionErr = m.allocateNamespace(container, service, project.Name)
+	}
+	if m.networker != nil {
+		if err := m.attach(container, service, project.Name); err != nil && provisionErr == nil {
+			provisionErr = err
+		}
+	}
 
 	if err := m.Save(registry); err != nil {
 		return err
 	}
+	if err := m.appendHistory(events); err != nil {
+		return err
+	}
 
-	return m.createOrUpdateSharedReadme(registry)
+	if err := m.createOrUpdateSharedReadme(registry); err != nil {
+		return err
+	}
+
+	// Provisioning waits for the container to accept connections, so it runs
+	// after the registration is saved rather than between Load and Save
+	if m.provisioner != nil {
+		if err := m.provision(registry, service, container.CatalogService(service), containerName, project.Name); err != nil && provisionErr == nil {
+			provisionErr = err
+		}
+	}
+	return provisionErr
 }
 
 // Unregister removes a project from a container's usage list
@@ -175,8 +256,19 @@ func (m *Manager) Unregister(service, projectName string) error {
 		return nil
 	}
 
+	now := time.Now()
+	var events []Event
+	// A failed detach still unregisters the project
+	var detachErr error
+	if slices.ContainsFunc(container.Projects, func(r ProjectRef) bool { return r.Name == projectName }) {
+		events = append(events, newEvent(now, ActionUnregister, service, container, projectName, ""))
+		if m.networker != nil {
+			detachErr = m.detach(context.Background(), container, service, projectName)
+		}
+	}
 	container.Projects = removeProject(container.Projects, projectName)
-	container.UpdatedAt = time.Now()
+	container.releaseNamespaces()
+	container.UpdatedAt = now
 
 	if len(container.Projects) == 0 {
 		delete(registry.Containers, service)
@@ -185,8 +277,14 @@ func (m *Manager) Unregister(service, projectName string) error {
 	if err := m.Save(registry); err != nil {
 		return err
 	}
+	if err := m.appendHistory(events); err != nil {
+		return err
+	}
 
-	return m.createOrUpdateSharedReadme(registry)
+	if err := m.createOrUpdateSharedReadme(registry); err != nil {
+		return err
+	}
+	return detachErr
 }
 
 // Get retrieves container info for a service
@@ -284,8 +382,11 @@ func (m *Manager) PurgeNonShareable(shareableServices map[string]bool) error {
 	}
 
 	changed := false
-	for service := range registry.Containers {
+	var events []Event
+	now := time.Now()
+	for service, container := range registry.Containers {
 		if !shareableServices[service] {
+			events = append(events, removedEvents(now, service, container)...)
 			delete(registry.Containers, service)
 			changed = true
 		}
@@ -298,6 +399,9 @@ func (m *Manager) PurgeNonShareable(shareableServices map[string]bool) error {
 	if err := m.Save(registry); err != nil {
 		return err
 	}
+	if err := m.appendHistory(events); err != nil {
+		return err
+	}
 	return m.createOrUpdateSharedReadme(registry)
 }
 
@@ -354,8 +458,11 @@ func (m *Manager) Reconcile(ctx context.Context, dockerClient *docker.Client) (*
 	}
 
 	// Remove registry entries for containers that don't exist
-	for service := range registry.Containers {
+	var events []Event
+	now := time.Now()
+	for service, container := range registry.Containers {
 		if !existingContainers[service] {
+			events = append(events, removedEvents(now, service, container)...)
 			delete(registry.Containers, service)
 			result.Removed = append(result.Removed, service)
 		}
@@ -366,6 +473,9 @@ func (m *Manager) Reconcile(ctx context.Context, dockerClient *docker.Client) (*
 		if err := m.Save(registry); err != nil {
 			return nil, err
 		}
+		if err := m.appendHistory(events); err != nil {
+			return nil, err
+		}
 	}
 
 	return result, nil
@@ -443,6 +553,7 @@ func (m *Manager) buildReadmeContent(registry *Registry) string {
 	b.WriteString("This directory manages shared containers across multiple otto-stack projects.\n\n")
 	b.WriteString("## Files\n\n")
 	b.WriteString("- `containers.yaml` - Registry tracking which projects use which shared containers\n")
+	b.WriteString("- `history.jsonl` - Log of every project registering with or leaving a shared container\n")
 	b.WriteString("- `generated/docker-compose.yml` - Generated compose file for shared containers\n")
 	b.WriteString("- `generated/.env.generated` - Generated environment variables\n")
 	b.WriteString("- `services/` - Per-service configuration stubs\n\n")
@@ -466,6 +577,8 @@ func (m *Manager) buildReadmeContent(registry *Registry) string {
 
 	b.WriteString("## Management\n\n")
 	b.WriteString("- View status: `otto-stack status --shared`\n")
+	b.WriteString("- Stop containers no project has used lately: `otto-stack shared gc --idle 72h`\n")
+	b.WriteString("- See which projects joined or left each container: `otto-stack shared history`\n")
 	b.WriteString("- Shared containers are automatically managed by otto-stack\n")
 	b.WriteString("- Orphaned projects are automatically cleaned up\n\n")
 	b.WriteString("## Important\n\n")
@@ -488,13 +601,23 @@ func removeProject(projects []ProjectRef, name string) []ProjectRef {
 
 // cleanupOrphans removes registry entries for projects whose ConfigDir no longer exists on disk.
 // This is called inside Register to keep the registry accurate across project lifecycle events.
-func (m *Manager) cleanupOrphans(reg *Registry) {
+// It returns the unregistrations for the history.
+func (m *Manager) cleanupOrphans(reg *Registry, now time.Time) []Event {
+	var events []Event
 	for service, info := range reg.Containers {
-		info.Projects = filterLiveProjectRefs(info.Projects)
+		live := filterLiveProjectRefs(info.Projects)
+		for _, ref := range info.Projects {
+			if !slices.Contains(live, ref) {
+				events = append(events, newEvent(now, ActionUnregister, service, info, ref.Name, ReasonOrphaned))
+			}
+		}
+		info.Projects = live
+		info.releaseNamespaces()
 		if len(info.Projects) == 0 {
 			delete(reg.Containers, service)
 		}
 	}
+	return events
 }
 
 // filterLiveProjectRefs returns only refs whose ConfigDir still exists on disk.
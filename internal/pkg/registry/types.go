@@ -14,23 +14,71 @@ type ProjectRef struct {
 	ConfigDir string `yaml:"config_dir" json:"config_dir"`
 }
 
-// ContainerInfo represents a shared container in the registry
+// ContainerInfo represents a shared container in the registry. Namespaces
+// are keyed by project and released when the project unregisters, as is
+// Activity. Image,
+// ConfigHash and Config record the effective configuration of the project
+// that first registered, so later projects asking for a different one can be
+// told what differs.
 type ContainerInfo struct {
-	Name      string       `yaml:"name" json:"name"`
-	Projects  []ProjectRef `yaml:"projects" json:"projects"`
-	CreatedAt time.Time    `yaml:"created_at" json:"created_at"`
-	UpdatedAt time.Time    `yaml:"updated_at" json:"updated_at"`
+	Name string `yaml:"name" json:"name"`
+	// Service is the catalog service when the registry key names a second
+	// instance, such as postgres for postgres-16
+	Service    string                `yaml:"service,omitempty" json:"service,omitempty"`
+	Projects   []ProjectRef          `yaml:"projects" json:"projects"`
+	Namespaces map[string]*Namespace `yaml:"namespaces,omitempty" json:"namespaces,omitempty"`
+	// Activity is when each project last ran up, status or logs, for shared gc
+	Activity   map[string]time.Time `yaml:"activity,omitempty" json:"activity,omitempty"`
+	Image      string               `yaml:"image,omitempty" json:"image,omitempty"`
+	ConfigHash string               `yaml:"config_hash,omitempty" json:"config_hash,omitempty"`
+	Config     map[string]string    `yaml:"config,omitempty" json:"config,omitempty"`
+	// Ports are the host ports a second instance publishes, keyed by the
+	// environment variable that names them
+	Ports     map[string]string `yaml:"ports,omitempty" json:"ports,omitempty"`
+	CreatedAt time.Time         `yaml:"created_at" json:"created_at"`
+	UpdatedAt time.Time         `yaml:"updated_at" json:"updated_at"`
 }
 
-// Registry represents the shared container registry
+// CatalogService returns the catalog service the container runs
+func (c *ContainerInfo) CatalogService(key string) string {
+	if c != nil && c.Service != "" {
+		return c.Service
+	}
+	return key
+}
+
+// Namespace is the part of a shared container set aside for one project,
+// such as a logical database on a shared redis or a resource name prefix on
+// a shared localstack
+type Namespace struct {
+	Database *int   `yaml:"database,omitempty" json:"database,omitempty"`
+	Prefix   string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+}
+
+// Credentials is what a project was given on a shared container, such as
+// its own database and role on a shared postgres
+type Credentials struct {
+	Database string `yaml:"database" json:"database"`
+	User     string `yaml:"user" json:"user"`
+	Password string `yaml:"password" json:"-"`
+}
+
+// Registry represents the shared container registry. Credentials are keyed
+// by service, then project, and outlive the project's registration so that
+// stopping and starting a project keeps its database. Version is the format
+// it was written in; see RegistryVersion.
 type Registry struct {
-	Containers map[string]*ContainerInfo `yaml:"shared_containers" json:"shared_containers"`
+	Version     int                                `yaml:"version" json:"version"`
+	Containers  map[string]*ContainerInfo          `yaml:"shared_containers" json:"shared_containers"`
+	Credentials map[string]map[string]*Credentials `yaml:"credentials,omitempty" json:"credentials,omitempty"`
 }
 
 // NewRegistry creates a new empty registry
 func NewRegistry() *Registry {
 	return &Registry{
-		Containers: make(map[string]*ContainerInfo),
+		Version:     RegistryVersion,
+		Containers:  make(map[string]*ContainerInfo),
+		Credentials: make(map[string]map[string]*Credentials),
 	}
 }
 
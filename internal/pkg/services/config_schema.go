@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"gopkg.in/yaml.v3"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
+	"github.com/otto-nation/otto-stack/internal/pkg/messages"
+	servicetypes "github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+// serviceSchemaURL names the resource a service's configuration_schema is
+// compiled under; it is never fetched
+const serviceSchemaURL = "otto-stack:///services/%s.json"
+
+// serviceConfigFileKeys are written into every service config file by init
+// and are accepted whatever the service's configuration_schema says
+var serviceConfigFileKeys = []string{"name", "description"}
+
+// schemaPrinter renders validator messages
+var schemaPrinter = message.NewPrinter(language.English)
+
+// ServiceConfigFilePath returns the path of a service's user config file
+func ServiceConfigFilePath(serviceName string) string {
+	return filepath.Join(core.OttoStackDir, core.ServiceConfigsDir, serviceName+core.YMLFileExtension)
+}
+
+// LoadServiceSettings reads a service's config file, validates it against the
+// service's configuration_schema and returns its settings with schema defaults
+// applied. A service without a config file has no settings and no issues.
+func LoadServiceSettings(service servicetypes.ServiceConfig) (map[string]any, []config.Issue, error) {
+	data, err := loadServiceConfigFile(service.Name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		path := ServiceConfigFilePath(service.Name)
+		return nil, nil, pkgerrors.NewConfigErrorf(pkgerrors.ErrCodeOperationFail, path, messages.ErrorsServiceConfigReadFailed, path)
+	}
+	settings, issues := ValidateServiceSettings(service, ServiceConfigFilePath(service.Name), data)
+	return settings, issues, nil
+}
+
+// ValidateServiceConfigFiles checks the config file of each service that has
+// one. An error means a file could not be read.
+func ValidateServiceConfigFiles(serviceConfigs []servicetypes.ServiceConfig) ([]config.Issue, error) {
+	var issues []config.Issue
+	for _, service := range serviceConfigs {
+		_, serviceIssues, err := LoadServiceSettings(service)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, serviceIssues...)
+	}
+	return issues, nil
+}
+
+// ValidateServiceSettings checks the contents of a service config file against
+// the service's configuration_schema. Unknown keys are reported rather than
+// ignored. When there are no issues the settings are returned with schema
+// defaults filled in, such as partitions for a kafka topic.
+func ValidateServiceSettings(service servicetypes.ServiceConfig, file string, data []byte) (map[string]any, []config.Issue) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, []config.Issue{config.SyntaxIssue(file, err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+
+	var value any
+	if err := root.Decode(&value); err != nil {
+		return nil, []config.Issue{config.SyntaxIssue(file, err)}
+	}
+
+	schema := strictSchema(service.ConfigurationSchema)
+	compiled, err := compileServiceSchema(service.Name, schema)
+	if err != nil {
+		return nil, []config.Issue{{
+			File: file, Line: root.Line, Column: root.Column,
+			Message: fmt.Sprintf(messages.ErrorsServiceSchemaInvalid, service.Name, err),
+		}}
+	}
+	if err := compiled.Validate(value); err != nil {
+		return nil, schemaIssues(file, root, schema, err)
+	}
+
+	settings, _ := value.(map[string]any)
+	applyDefaults(schema, settings)
+	for _, key := range serviceConfigFileKeys {
+		delete(settings, key)
+	}
+	return settings, nil
+}
+
+// ServiceJSONSchema returns the JSON Schema for a service's config file: its
+// configuration_schema closed against unknown keys, exactly as
+// ValidateServiceSettings applies it
+func ServiceJSONSchema(service servicetypes.ServiceConfig) map[string]any {
+	schema := strictSchema(service.ConfigurationSchema)
+	schema["$schema"] = config.JSONSchemaDialect
+	schema["title"] = fmt.Sprintf(messages.SchemaServiceTitle, service.Name)
+	if service.Description != "" {
+		schema["description"] = service.Description
+	}
+	return schema
+}
+
+func compileServiceSchema(serviceName string, schema map[string]any) (*jsonschema.Schema, error) {
+	url := fmt.Sprintf(serviceSchemaURL, serviceName)
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(url, schema); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(url)
+}
+
+// strictSchema copies a configuration_schema, closing every object that lists
+// its properties so unknown keys are reported, and accepting the keys init
+// writes at the top level
+func strictSchema(schema map[string]any) map[string]any {
+	strict, _ := closeObjects(schema).(map[string]any)
+	if strict == nil {
+		strict = map[string]any{"type": "object"}
+	}
+	props, _ := strict["properties"].(map[string]any)
+	if props == nil {
+		props = make(map[string]any)
+		strict["properties"] = props
+	}
+	for _, key := range serviceConfigFileKeys {
+		if _, ok := props[key]; !ok {
+			props[key] = map[string]any{"type": "string"}
+		}
+	}
+	if _, ok := strict["additionalProperties"]; !ok {
+		strict["additionalProperties"] = false
+	}
+	return strict
+}
+
+func closeObjects(node any) any {
+	switch v := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v)+1)
+		for key, child := range v {
+			out[key] = closeObjects(child)
+		}
+		_, hasProperties := v["properties"]
+		if _, set := v["additionalProperties"]; hasProperties && !set {
+			out["additionalProperties"] = false
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = closeObjects(child)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// applyDefaults fills in the schema defaults of keys value leaves out,
+// descending into nested objects and array items
+func applyDefaults(schema map[string]any, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		for name, p := range props {
+			prop, ok := p.(map[string]any)
+			if !ok {
+				continue
+			}
+			if child, exists := v[name]; exists {
+				applyDefaults(prop, child)
+			} else if def, ok := prop["default"]; ok {
+				v[name] = def
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for _, item := range v {
+				applyDefaults(items, item)
+			}
+		}
+	}
+}
+
+// schemaIssues turns a validation failure into one issue per failed keyword,
+// located at the offending value in the file
+func schemaIssues(file string, root *yaml.Node, schema map[string]any, err error) []config.Issue {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []config.Issue{{File: file, Line: root.Line, Column: root.Column, Message: err.Error()}}
+	}
+
+	var issues []config.Issue
+	for _, leaf := range leafErrors(validationErr) {
+		node, path := locate(root, leaf.InstanceLocation)
+
+		if extra, ok := leaf.ErrorKind.(*kind.AdditionalProperties); ok {
+			known := propertyNames(subschema(schema, leaf.InstanceLocation))
+			for _, key := range extra.Properties {
+				keyPath := joinSettingPath(path, key)
+				at := node
+				if keyNode, _ := mappingEntry(node, key); keyNode != nil {
+					at = keyNode
+				}
+				issues = append(issues, config.Issue{
+					File: file, Line: at.Line, Column: at.Column, Path: keyPath,
+					Message:    fmt.Sprintf(messages.ValidationSchemaUnknownField, keyPath),
+					Suggestion: config.ClosestMatch(key, known),
+				})
+			}
+			continue
+		}
+
+		msg := leaf.ErrorKind.LocalizedString(schemaPrinter)
+		if path != "" {
+			msg = fmt.Sprintf(messages.ValidationSchemaConstraint, path, msg)
+		}
+		issues = append(issues, config.Issue{File: file, Line: node.Line, Column: node.Column, Path: path, Message: msg})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues
+}
+
+func leafErrors(err *jsonschema.ValidationError) []*jsonschema.ValidationError {
+	if len(err.Causes) == 0 {
+		return []*jsonschema.ValidationError{err}
+	}
+	var leaves []*jsonschema.ValidationError
+	for _, cause := range err.Causes {
+		leaves = append(leaves, leafErrors(cause)...)
+	}
+	return leaves
+}
+
+// locate follows an instance location through the document, returning the
+// deepest node it reaches and its path in config-file notation
+func locate(root *yaml.Node, location []string) (*yaml.Node, string) {
+	node, path := root, ""
+	for _, token := range location {
+		if node.Kind == yaml.AliasNode {
+			node = node.Alias
+		}
+		switch node.Kind {
+		case yaml.MappingNode:
+			keyNode, value := mappingEntry(node, token)
+			if keyNode == nil {
+				return node, path
+			}
+			node, path = value, joinSettingPath(path, token)
+		case yaml.SequenceNode:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				return node, path
+			}
+			node, path = node.Content[i], fmt.Sprintf("%s[%d]", path, i)
+		default:
+			return node, path
+		}
+	}
+	return node, path
+}
+
+// mappingEntry returns the key and value nodes of key in a mapping
+func mappingEntry(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+// subschema returns the schema that applies at an instance location
+func subschema(schema map[string]any, location []string) map[string]any {
+	for _, token := range location {
+		if props, ok := schema["properties"].(map[string]any); ok {
+			if prop, ok := props[token].(map[string]any); ok {
+				schema = prop
+				continue
+			}
+		}
+		items, ok := schema["items"].(map[string]any)
+		if !ok {
+			return nil
+		}
+		schema = items
+	}
+	return schema
+}
+
+func propertyNames(schema map[string]any) []string {
+	props, _ := schema["properties"].(map[string]any)
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	return names
+}
+
+func joinSettingPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// loadServiceConfigFile reads a service config file from .otto-stack/services/
+func loadServiceConfigFile(serviceName string) ([]byte, error) {
+	return os.ReadFile(ServiceConfigFilePath(serviceName))
+}
@@ -0,0 +1,159 @@
+//go:build unit
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/otto-nation/otto-stack/internal/core"
+	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	servicetypes "github.com/otto-nation/otto-stack/internal/pkg/types"
+)
+
+func catalogService(t *testing.T, name string) servicetypes.ServiceConfig {
+	t.Helper()
+	manager, err := New()
+	require.NoError(t, err)
+	service, err := manager.GetService(name)
+	require.NoError(t, err)
+	require.NotEmpty(t, service.ConfigurationSchema)
+	return *service
+}
+
+func writeServiceConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Join(core.OttoStackDir, core.ServiceConfigsDir), core.PermReadWriteExec))
+	require.NoError(t, os.WriteFile(ServiceConfigFilePath(name), []byte(content), core.PermReadWrite))
+}
+
+func TestValidateServiceSettings_AppliesDefaults(t *testing.T) {
+	kafka := catalogService(t, "kafka")
+
+	settings, issues := ValidateServiceSettings(kafka, "kafka.yml", []byte(`name: kafka
+description: Configuration for kafka
+topics:
+  - name: orders
+  - name: events
+    partitions: 6
+`))
+	require.Empty(t, issues)
+
+	topics := settings["topics"].([]any)
+	assert.Equal(t, map[string]any{"name": "orders", "partitions": 3, "replication_factor": 1}, topics[0])
+	assert.Equal(t, 6, topics[1].(map[string]any)["partitions"])
+	assert.NotContains(t, settings, "name", "keys written by init are not settings")
+}
+
+func TestValidateServiceSettings_ReportsIssuesWithPaths(t *testing.T) {
+	kafka := catalogService(t, "kafka")
+
+	_, issues := ValidateServiceSettings(kafka, "kafka.yml", []byte(`name: kafka
+topics:
+  - name: orders
+    partitons: 6
+  - partitions: many
+`))
+	require.Len(t, issues, 3)
+
+	assert.Equal(t, "topics[0].partitons", issues[0].Path)
+	assert.Equal(t, "unknown field topics[0].partitons", issues[0].Message)
+	assert.Equal(t, "partitions", issues[0].Suggestion)
+	assert.Equal(t, 4, issues[0].Line)
+	assert.Equal(t, 5, issues[0].Column)
+
+	assert.Equal(t, "topics[1]", issues[1].Path)
+	assert.Contains(t, issues[1].Message, "name")
+	assert.Equal(t, 5, issues[1].Line)
+
+	assert.Equal(t, "topics[1].partitions", issues[2].Path)
+	assert.Contains(t, issues[2].Message, "want integer")
+	assert.Equal(t, `kafka.yml:5:17: topics[1].partitions: got string, want integer`, issues[2].String())
+}
+
+func TestValidateServiceSettings_UnknownTopLevelKey(t *testing.T) {
+	_, issues := ValidateServiceSettings(catalogService(t, "postgres"), "postgres.yml", []byte("name: postgres\ndatabse: app\n"))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "database", issues[0].Suggestion)
+	assert.Equal(t, 2, issues[0].Line)
+}
+
+func TestValidateServiceSettings_ServiceWithoutSchema(t *testing.T) {
+	service := servicetypes.ServiceConfig{Name: "zookeeper"}
+
+	settings, issues := ValidateServiceSettings(service, "zookeeper.yml", []byte("name: zookeeper\ndescription: Configuration for zookeeper\n"))
+	assert.Empty(t, issues)
+	assert.Empty(t, settings)
+
+	_, issues = ValidateServiceSettings(service, "zookeeper.yml", []byte("name: zookeeper\ntick_time: 2000\n"))
+	require.Len(t, issues, 1)
+	assert.Equal(t, "tick_time", issues[0].Path)
+}
+
+func TestValidateServiceSettings_SyntaxError(t *testing.T) {
+	_, issues := ValidateServiceSettings(catalogService(t, "kafka"), "kafka.yml", []byte("topics: [\n"))
+	require.Len(t, issues, 1)
+	assert.Contains(t, issues[0].Message, "invalid YAML")
+}
+
+func TestValidateServiceConfigFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	kafka, postgres := catalogService(t, "kafka"), catalogService(t, "postgres")
+
+	issues, err := ValidateServiceConfigFiles([]servicetypes.ServiceConfig{kafka, postgres})
+	require.NoError(t, err)
+	assert.Empty(t, issues, "services without config files are valid")
+
+	writeServiceConfigFile(t, "kafka", "topics:\n  - name: orders\n")
+	writeServiceConfigFile(t, "postgres", "user: 42\n")
+
+	issues, err = ValidateServiceConfigFiles([]servicetypes.ServiceConfig{kafka, postgres})
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, ServiceConfigFilePath("postgres"), issues[0].File)
+	assert.Equal(t, "user", issues[0].Path)
+}
+
+func TestLoadAndValidateServiceConfigs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	kafka := catalogService(t, "kafka")
+	s := &Service{logger: logger.GetLogger()}
+
+	writeServiceConfigFile(t, "kafka", "topics:\n  - name: orders\n")
+	configs, err := s.loadAndValidateServiceConfigs([]servicetypes.ServiceConfig{kafka})
+	require.NoError(t, err)
+	topics := configs[0].Settings["topics"].([]any)
+	assert.Equal(t, 3, topics[0].(map[string]any)["partitions"])
+
+	writeServiceConfigFile(t, "kafka", "topic:\n  - name: orders\n")
+	_, err = s.loadAndValidateServiceConfigs([]servicetypes.ServiceConfig{kafka})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 problem")
+}
+
+func TestTemplateProcessor_UsesValidatedSettings(t *testing.T) {
+	broker := servicetypes.ServiceConfig{Name: "kafka-broker"}
+	kafka := catalogService(t, "kafka")
+	settings, issues := ValidateServiceSettings(kafka, "kafka.yml", []byte("topics:\n  - name: orders\n"))
+	require.Empty(t, issues)
+	kafka.Settings = settings
+
+	result, err := NewTemplateProcessor().Process(
+		"{{range .topics}}{{.name}}:{{.partitions}}:{{.replication_factor}}{{end}}", broker, []servicetypes.ServiceConfig{kafka})
+	require.NoError(t, err)
+	assert.Equal(t, "orders:3:1", result)
+}
+
+func TestServiceJSONSchema_MatchesValidation(t *testing.T) {
+	schema := ServiceJSONSchema(catalogService(t, "kafka"))
+	assert.Equal(t, "otto-stack kafka service configuration", schema["title"])
+
+	compiled, err := compileServiceSchema("kafka", schema)
+	require.NoError(t, err)
+	assert.NoError(t, compiled.Validate(map[string]any{"name": "kafka", "topics": []any{map[string]any{"name": "orders"}}}))
+	assert.Error(t, compiled.Validate(map[string]any{"topics": []any{map[string]any{"name": "orders", "partitons": 3}}}))
+}
@@ -2,30 +2,33 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"reflect"
 	"strings"
 	"time"
 
-	"gopkg.in/yaml.v3"
-
-	"github.com/otto-nation/otto-stack/internal/core"
 	"github.com/otto-nation/otto-stack/internal/core/docker"
+	"github.com/otto-nation/otto-stack/internal/pkg/config"
 	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/otto-nation/otto-stack/internal/pkg/messages"
 	servicetypes "github.com/otto-nation/otto-stack/internal/pkg/types"
 )
 
-func (s *Service) executeLocalInitScripts(ctx context.Context, serviceConfigs []servicetypes.ServiceConfig, projectName string) error {
+// initExcerptLines is how much of a failed init script's output is attached to its error
+const initExcerptLines = 20
+
+func (s *Service) executeLocalInitScripts(ctx context.Context, serviceConfigs []servicetypes.ServiceConfig, projectName string, initLog func(string) io.WriteCloser) error {
 	s.logger.Debug("Executing local init scripts for services")
 	for _, config := range serviceConfigs {
 		if s.hasLocalInitScripts(config) {
-			if err := s.executeServiceInitScripts(ctx, config, serviceConfigs, projectName); err != nil {
+			if err := s.executeServiceInitScripts(ctx, config, serviceConfigs, projectName, initLog); err != nil {
 				return err
 			}
 		}
@@ -55,9 +58,16 @@ func (s *Service) hasLocalInitScripts(config servicetypes.ServiceConfig) bool {
 	return s.hasInitScripts(config) && config.InitService.Mode == docker.InitServiceModeLocal
 }
 
-// executeServiceInitScripts executes all init scripts for a single service
-func (s *Service) executeServiceInitScripts(ctx context.Context, config servicetypes.ServiceConfig, allConfigs []servicetypes.ServiceConfig, projectName string) error {
-	for _, script := range config.InitService.Scripts {
+// executeServiceInitScripts executes all init scripts for a single service. Each
+// script's output is captured to .otto-stack/logs/init/<service>/script-N.log;
+// a failure carries the last lines of that output.
+func (s *Service) executeServiceInitScripts(ctx context.Context, config servicetypes.ServiceConfig, allConfigs []servicetypes.ServiceConfig, projectName string, initLog func(string) io.WriteCloser) error {
+	logDir := logrecorder.Dir()
+	if err := logrecorder.ClearInitLogs(logDir, config.Name); err != nil {
+		s.logger.Debug("Failed to clear init output", "service", config.Name, "error", err)
+	}
+
+	for i, script := range config.InitService.Scripts {
 		// Process template variables in script content
 		processor := NewTemplateProcessor()
 		processedScript, err := processor.Process(script.Content, config, allConfigs)
@@ -65,67 +75,89 @@ func (s *Service) executeServiceInitScripts(ctx context.Context, config servicet
 			return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, config.Name, messages.InitTemplateProcessFailed, err)
 		}
 
-		// Execute based on mode
-		if config.InitService.Mode == docker.InitServiceModeContainer {
-			if err := s.executeScriptInContainer(ctx, processedScript, config, projectName); err != nil {
-				return err
-			}
-		} else {
-			// local mode
-			env := make(map[string]string)
-			if config.InitService.Environment != nil {
-				maps.Copy(env, config.InitService.Environment)
-			}
-			env["DOCKER_IMAGE"] = config.InitService.Image
-			env["DOCKER_NETWORK"] = projectName + docker.NetworkNameSuffix
+		capture, err := logrecorder.CreateInitLog(logDir, config.Name, i+1)
+		if err != nil {
+			return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, config.Name, messages.InitOutputCaptureFailed, err)
+		}
+		var output io.Writer = capture
+		var tee io.WriteCloser
+		if initLog != nil {
+			tee = initLog(config.Name)
+			output = io.MultiWriter(capture, tee)
+		}
 
-			if err := s.executeScript(ctx, processedScript, env, config.Name); err != nil {
-				return err
-			}
+		err = s.executeInitScript(ctx, processedScript, config, projectName, output)
+		if tee != nil {
+			_ = tee.Close()
+		}
+		_ = capture.Close()
+		if err != nil {
+			return withInitExcerpt(err, capture.Name())
 		}
 	}
 	return nil
 }
 
-// loadAndValidateServiceConfigs loads user service config files and validates them
-func (s *Service) loadAndValidateServiceConfigs(serviceConfigs []servicetypes.ServiceConfig) []servicetypes.ServiceConfig {
+// executeInitScript runs one processed script in the service's init mode
+func (s *Service) executeInitScript(ctx context.Context, script string, config servicetypes.ServiceConfig, projectName string, output io.Writer) error {
+	if config.InitService.Mode == docker.InitServiceModeContainer {
+		return s.executeScriptInContainer(ctx, script, config, projectName, output)
+	}
+
+	// local mode
+	env := make(map[string]string)
+	if config.InitService.Environment != nil {
+		maps.Copy(env, config.InitService.Environment)
+	}
+	env["DOCKER_IMAGE"] = config.InitService.Image
+	env["DOCKER_NETWORK"] = projectName + docker.NetworkNameSuffix
+
+	return s.executeScript(ctx, script, env, config.Name, output)
+}
+
+// withInitExcerpt attaches the tail of a failed script's captured output to err
+func withInitExcerpt(err error, capturePath string) error {
+	var appErr *pkgerrors.Error
+	if !errors.As(err, &appErr) {
+		return err
+	}
+	if excerpt, readErr := logrecorder.Tail(capturePath, initExcerptLines); readErr == nil && excerpt != "" {
+		appErr.WithOutput(excerpt)
+	}
+	return err
+}
+
+// loadAndValidateServiceConfigs loads user service config files, validates them
+// against each service's configuration_schema and attaches the settings with
+// schema defaults applied. Any invalid file fails the whole load.
+func (s *Service) loadAndValidateServiceConfigs(serviceConfigs []servicetypes.ServiceConfig) ([]servicetypes.ServiceConfig, error) {
 	enrichedConfigs := make([]servicetypes.ServiceConfig, 0, len(serviceConfigs))
+	var issues []config.Issue
 
-	for _, config := range serviceConfigs {
-		configData, err := loadServiceConfigFile(config.Name)
+	for _, serviceConfig := range serviceConfigs {
+		settings, serviceIssues, err := LoadServiceSettings(serviceConfig)
 		if err != nil {
-			// File doesn't exist - that's OK, not all services need config files
-			s.logger.Debug("No config file for service", "service", config.Name)
-			enrichedConfigs = append(enrichedConfigs, config)
+			return nil, err
+		}
+		issues = append(issues, serviceIssues...)
+		if settings == nil {
+			// Not all services need config files
+			s.logger.Debug("No config file for service", "service", serviceConfig.Name)
+			enrichedConfigs = append(enrichedConfigs, serviceConfig)
 			continue
 		}
 
-		s.logger.Debug("Loaded config file", "service", config.Name, "data", configData)
+		s.logger.Debug("Loaded config file", "service", serviceConfig.Name, "settings", settings)
 
-		// Merge config data into ServiceConfig struct
-		enrichedConfig := mergeConfigIntoStruct(config, configData)
-		s.logger.Debug("Merged config", "service", config.Name, "enrichedConfig", enrichedConfig)
+		enrichedConfig := mergeConfigIntoStruct(serviceConfig, settings)
+		enrichedConfig.Settings = settings
 		enrichedConfigs = append(enrichedConfigs, enrichedConfig)
 	}
 
-	return enrichedConfigs
-}
-
-// loadServiceConfigFile loads a service config file from .otto-stack/service-configs/
-func loadServiceConfigFile(serviceName string) (map[string]any, error) {
-	configPath := filepath.Join(core.OttoStackDir, core.ServiceConfigsDir, serviceName+core.YMLFileExtension)
-
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, err
-	}
-
-	var configData map[string]any
-	if err := yaml.Unmarshal(data, &configData); err != nil {
-		return nil, pkgerrors.NewConfigError(pkgerrors.ErrCodeOperationFail, configPath, messages.InitConfigParseFailed, err)
+	if len(issues) > 0 {
+		return nil, config.IssuesError(issues)
 	}
-
-	return configData, nil
+	return enrichedConfigs, nil
 }
 
 // mergeConfigIntoStruct merges config file data into the ServiceConfig struct using reflection
@@ -206,8 +238,9 @@ func convertToMapSlice(data []any) []map[string]any {
 	return result
 }
 
-// executeScript executes a single script with environment variables on the host
-func (s *Service) executeScript(ctx context.Context, scriptContent string, env map[string]string, serviceName string) error {
+// executeScript executes a single script with environment variables on the host,
+// writing its stdout and stderr to output
+func (s *Service) executeScript(ctx context.Context, scriptContent string, env map[string]string, serviceName string, output io.Writer) error {
 	cmd := exec.CommandContext(ctx, docker.ShellSh, docker.ShellC, scriptContent)
 
 	// Start with parent environment
@@ -218,8 +251,8 @@ func (s *Service) executeScript(ctx context.Context, scriptContent string, env m
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = output
+	cmd.Stderr = output
 
 	if err := cmd.Run(); err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, serviceName, messages.InitScriptExecuteFailed, err)
@@ -227,14 +260,16 @@ func (s *Service) executeScript(ctx context.Context, scriptContent string, env m
 	return nil
 }
 
-// executeScriptInContainer executes a script inside a Docker container
-func (s *Service) executeScriptInContainer(ctx context.Context, scriptContent string, config servicetypes.ServiceConfig, projectName string) error {
+// executeScriptInContainer executes a script inside a Docker container, writing
+// the container's output to output once it exits
+func (s *Service) executeScriptInContainer(ctx context.Context, scriptContent string, config servicetypes.ServiceConfig, projectName string, output io.Writer) error {
 	// Build init container config
 	initConfig := docker.InitContainerConfig{
 		Image:       config.InitService.Image,
 		Command:     []string{docker.ShellSh, docker.ShellC, scriptContent},
 		Environment: config.InitService.Environment,
 		Networks:    []string{projectName + docker.NetworkNameSuffix},
+		Output:      output,
 	}
 
 	// Use docker client to run init container
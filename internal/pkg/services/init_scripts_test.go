@@ -3,13 +3,21 @@
 package services
 
 import (
+	"context"
+	"io"
+	"os"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/otto-nation/otto-stack/internal/core/docker"
+	pkgerrors "github.com/otto-nation/otto-stack/internal/pkg/errors"
 	"github.com/otto-nation/otto-stack/internal/pkg/logger"
+	"github.com/otto-nation/otto-stack/internal/pkg/logrecorder"
 	"github.com/otto-nation/otto-stack/test/fixtures"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHasInitScripts(t *testing.T) {
@@ -108,3 +116,73 @@ func TestConvertToMapSlice(t *testing.T) {
 	result = convertToMapSlice(input)
 	assert.Len(t, result, 0)
 }
+
+// closingBuffer is a WriteCloser safe for the concurrent stdout and stderr copies of exec
+type closingBuffer struct {
+	mu     sync.Mutex
+	buf    strings.Builder
+	closed bool
+}
+
+func (b *closingBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *closingBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestExecuteServiceInitScripts_CapturesOutput(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s := &Service{logger: logger.GetLogger()}
+	tee := &closingBuffer{}
+	initLog := func(service string) io.WriteCloser {
+		assert.Equal(t, "postgres", service)
+		return tee
+	}
+
+	config := fixtures.NewServiceConfig("postgres").Build()
+	config.InitService = &docker.InitServiceSpec{
+		Enabled: true,
+		Mode:    docker.InitServiceModeLocal,
+		Scripts: []docker.InitScript{{Content: "echo ready"}, {Content: "echo out; echo failed >&2"}},
+	}
+
+	err := s.executeServiceInitScripts(context.Background(), config, nil, "my-app", initLog)
+	assert.NoError(t, err)
+
+	paths, err := logrecorder.InitLogs(logrecorder.Dir(), "postgres")
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	data, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "out\n")
+	assert.Contains(t, string(data), "failed\n")
+	assert.Contains(t, tee.String(), "ready\n")
+	assert.True(t, tee.closed)
+}
+
+func TestExecuteServiceInitScripts_AttachesExcerptOnFailure(t *testing.T) {
+	t.Chdir(t.TempDir())
+	s := &Service{logger: logger.GetLogger()}
+
+	config := fixtures.NewServiceConfig("kafka").Build()
+	config.InitService = &docker.InitServiceSpec{
+		Enabled: true,
+		Mode:    docker.InitServiceModeLocal,
+		Scripts: []docker.InitScript{{Content: "echo creating topic; echo topic exists >&2; exit 3"}},
+	}
+
+	err := s.executeServiceInitScripts(context.Background(), config, nil, "my-app", nil)
+	require.Error(t, err)
+	assert.Equal(t, "creating topic\ntopic exists", pkgerrors.OutputOf(err))
+}
@@ -124,3 +124,23 @@ func TestServiceResolver_MultipleServices(t *testing.T) {
 		assert.GreaterOrEqual(t, len(configs), 2)
 	}
 }
+
+func TestApplyVolumes(t *testing.T) {
+	configs := []servicetypes.ServiceConfig{
+		{Name: ServicePostgres, Container: servicetypes.ContainerSpec{Volumes: []servicetypes.VolumeSpec{
+			{Name: "pgdata", Mount: "/var/lib/postgresql/data"},
+			{Name: "./init", Mount: "/docker-entrypoint-initdb.d"},
+		}}},
+		{Name: ServiceRedis},
+	}
+
+	applied := applyVolumes(configs, map[string]map[string]string{
+		ServicePostgres: {"/var/lib/postgresql/data": "shop_postgres_data"},
+	})
+
+	assert.Equal(t, []servicetypes.VolumeSpec{
+		{Name: "./init", Mount: "/docker-entrypoint-initdb.d"},
+		{Name: "shop_postgres_data", Mount: "/var/lib/postgresql/data", External: true},
+	}, applied[0].Container.Volumes)
+	assert.Empty(t, applied[1].Container.Volumes)
+}
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"io"
+	"strings"
 
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/compose/v5/pkg/api"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
@@ -72,6 +74,14 @@ func (m *mockDockerClient) ContainerRestart(ctx context.Context, containerID str
 	return nil
 }
 
+func (m *mockDockerClient) ContainerPause(ctx context.Context, containerID string) error {
+	return nil
+}
+
+func (m *mockDockerClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	return nil
+}
+
 func (m *mockDockerClient) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
 	respChan := make(chan container.WaitResponse, 1)
 	errChan := make(chan error, 1)
@@ -84,6 +94,10 @@ func (m *mockDockerClient) ContainerLogs(ctx context.Context, container string,
 	return io.NopCloser(nil), nil
 }
 
+func (m *mockDockerClient) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	return container.StatsResponseReader{Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
 func (m *mockDockerClient) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
 	return volume.ListResponse{}, nil
 }
@@ -96,10 +110,22 @@ func (m *mockDockerClient) NetworkList(ctx context.Context, options network.List
 	return []network.Summary{}, nil
 }
 
+func (m *mockDockerClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	return network.CreateResponse{}, nil
+}
+
 func (m *mockDockerClient) NetworkRemove(ctx context.Context, networkID string) error {
 	return nil
 }
 
+func (m *mockDockerClient) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	return nil
+}
+
+func (m *mockDockerClient) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	return nil
+}
+
 func (m *mockDockerClient) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	return []image.Summary{}, nil
 }
@@ -108,10 +134,34 @@ func (m *mockDockerClient) ImageRemove(ctx context.Context, imageID string, opti
 	return []image.DeleteResponse{}, nil
 }
 
+func (m *mockDockerClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	msgCh := make(chan events.Message)
+	errCh := make(chan error)
+	close(msgCh)
+	close(errCh)
+	return msgCh, errCh
+}
+
 func (m *mockDockerClient) Info(ctx context.Context) (system.Info, error) {
 	return system.Info{}, nil
 }
 
+func (m *mockDockerClient) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	return container.ExecCreateResponse{}, nil
+}
+
+func (m *mockDockerClient) ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+	return types.HijackedResponse{}, nil
+}
+
+func (m *mockDockerClient) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	return container.ExecInspect{}, nil
+}
+
+func (m *mockDockerClient) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	return types.DiskUsage{}, nil
+}
+
 func (m *mockDockerClient) Ping(ctx context.Context) (types.Ping, error) {
 	return types.Ping{}, nil
 }
@@ -323,7 +373,7 @@ func TestService_GenerateComposeFileWithMocks(t *testing.T) {
 		fixtures.NewServiceConfig(ServiceRedis).Build(),
 	}
 
-	err := service.GenerateComposeFile("test-project", serviceConfigs)
+	err := service.GenerateComposeFile("test-project", serviceConfigs, nil)
 	assert.NoError(t, err)
 }
 
@@ -541,7 +591,7 @@ func TestService_GenerateComposeFile(t *testing.T) {
 			{Name: "postgres", Description: "PostgreSQL"},
 		}
 
-		err = service.GenerateComposeFile("test-project", configs)
+		err = service.GenerateComposeFile("test-project", configs, nil)
 		assert.NoError(t, err)
 	})
 }
@@ -623,3 +673,62 @@ func TestService_ExecWithOptions(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestService_PauseWithMocks(t *testing.T) {
+	var paused []string
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "1", Names: []string{"/test-project-postgres-1"}, State: docker.StateRunning, Labels: map[string]string{docker.ComposeServiceLabel: ServicePostgres}},
+				{ID: "2", Names: []string{"/test-project-redis-1"}, State: docker.StateStopped, Labels: map[string]string{docker.ComposeServiceLabel: ServiceRedis}},
+				{ID: "3", Names: []string{"/test-project-kafka-1"}, State: docker.StateRunning, Labels: map[string]string{docker.ComposeServiceLabel: "kafka"}},
+			}, nil
+		},
+		ContainerPauseFunc: func(ctx context.Context, containerID string) error {
+			paused = append(paused, containerID)
+			return nil
+		},
+	}
+	dockerClient := docker.NewClientWithDependencies(mockDocker, nil, nil)
+	service := NewServiceWithClient(testhelpers.NewMockCompose(), &mockResolver{}, testhelpers.NewMockProjectLoader(), dockerClient)
+
+	affected, err := service.Pause(context.Background(), PauseRequest{
+		Project: "test-project",
+		ServiceConfigs: []servicetypes.ServiceConfig{
+			fixtures.NewServiceConfig(ServicePostgres).Build(),
+			fixtures.NewServiceConfig(ServiceRedis).Build(),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-project-postgres-1"}, affected)
+	assert.Equal(t, affected, paused)
+}
+
+func TestService_UnpauseWithMocks(t *testing.T) {
+	var unpaused []string
+	mockDocker := &testhelpers.MockDockerClient{
+		ContainerListFunc: func(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
+			return []container.Summary{
+				{ID: "1", Names: []string{"/test-project-postgres-1"}, State: docker.StatePaused, Labels: map[string]string{docker.ComposeServiceLabel: ServicePostgres}},
+				{ID: "2", Names: []string{"/test-project-redis-1"}, State: docker.StateRunning, Labels: map[string]string{docker.ComposeServiceLabel: ServiceRedis}},
+			}, nil
+		},
+		ContainerUnpauseFunc: func(ctx context.Context, containerID string) error {
+			unpaused = append(unpaused, containerID)
+			return nil
+		},
+	}
+	dockerClient := docker.NewClientWithDependencies(mockDocker, nil, nil)
+	service := NewServiceWithClient(testhelpers.NewMockCompose(), &mockResolver{}, testhelpers.NewMockProjectLoader(), dockerClient)
+
+	affected, err := service.Unpause(context.Background(), PauseRequest{
+		Project: "test-project",
+		ServiceConfigs: []servicetypes.ServiceConfig{
+			fixtures.NewServiceConfig(ServicePostgres).Build(),
+			fixtures.NewServiceConfig(ServiceRedis).Build(),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"test-project-postgres-1"}, affected)
+	assert.Equal(t, affected, unpaused)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"io"
 	"log/slog"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/docker/compose/v5/pkg/api"
@@ -46,7 +48,32 @@ func ResolveUpServices(args []string, cfg *config.Config) ([]servicetypes.Servic
 	}
 
 	resolver := NewServiceResolver(manager)
-	return resolver.ResolveServices(serviceNames)
+	configs, err := resolver.ResolveServices(serviceNames)
+	if err != nil || cfg == nil {
+		return configs, err
+	}
+	return applyVolumes(configs, cfg.Volumes), nil
+}
+
+// applyVolumes mounts the named volumes the project config lists for each
+// service, keyed by container path. A volume replaces any the service
+// definition mounts at the same path.
+func applyVolumes(configs []servicetypes.ServiceConfig, volumes map[string]map[string]string) []servicetypes.ServiceConfig {
+	for i := range configs {
+		mounts := volumes[configs[i].Name]
+		if len(mounts) == 0 {
+			continue
+		}
+		kept := slices.DeleteFunc(slices.Clone(configs[i].Container.Volumes), func(v servicetypes.VolumeSpec) bool {
+			_, replaced := mounts[v.Mount]
+			return replaced
+		})
+		for _, mount := range slices.Sorted(maps.Keys(mounts)) {
+			kept = append(kept, servicetypes.VolumeSpec{Name: mounts[mount], Mount: mount, External: true})
+		}
+		configs[i].Container.Volumes = kept
+	}
+	return configs
 }
 
 // StartRequest defines parameters for starting a stack
@@ -61,6 +88,11 @@ type StartRequest struct {
 	CleanupOnRecreate bool
 	Timeout           time.Duration
 	Characteristics   []string
+	// AttachProjects are the projects whose networks every service also joins
+	AttachProjects []string
+	// InitLog, when set, returns a writer that receives a copy of a service's
+	// init script output; it is closed once the script finishes
+	InitLog func(service string) io.WriteCloser
 }
 
 // StopRequest defines parameters for stopping a stack
@@ -74,10 +106,18 @@ type StopRequest struct {
 	Characteristics []string
 }
 
+// PauseRequest defines parameters for pausing or unpausing services
+type PauseRequest struct {
+	Project        string
+	ServiceConfigs []servicetypes.ServiceConfig
+}
+
 // StatusRequest defines parameters for getting service status
 type StatusRequest struct {
 	Project  string
 	Services []string
+	// Stats samples the resource usage of running containers
+	Stats bool
 }
 
 // CleanupRequest defines parameters for cleanup operations
@@ -109,6 +149,10 @@ type LogRequest struct {
 	Until          string
 	NoColor        bool
 	Writer         io.Writer
+	// Filter selects and reformats lines; nil prints every line unchanged
+	Filter *docker.LogFilter
+	// Consumer, when set, receives the log lines instead of Writer
+	Consumer api.LogConsumer
 }
 
 // NewService creates a new stack service
@@ -140,11 +184,15 @@ func (s *Service) Start(ctx context.Context, req StartRequest) error {
 		"build", req.Build,
 		"forceRecreate", req.ForceRecreate)
 
-	// Load and validate service configs from .otto-stack/service-configs/
-	req.ServiceConfigs = s.loadAndValidateServiceConfigs(req.ServiceConfigs)
+	// Load and validate service configs from .otto-stack/services/
+	serviceConfigs, err := s.loadAndValidateServiceConfigs(req.ServiceConfigs)
+	if err != nil {
+		return err
+	}
+	req.ServiceConfigs = serviceConfigs
 
 	// Generate docker-compose.yml from service configs
-	if err := s.GenerateComposeFile(req.Project, req.ServiceConfigs); err != nil {
+	if err := s.GenerateComposeFile(req.Project, req.ServiceConfigs, req.AttachProjects); err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentProject, messages.ErrorsStackComposeGenerateFailed, err)
 	}
 
@@ -190,7 +238,7 @@ func (s *Service) Start(ctx context.Context, req StartRequest) error {
 	// Execute local init scripts for services that have them.
 	// On failure, tear down the containers so the system is left in a clean state
 	// rather than partially running with a failed init.
-	if err := s.executeLocalInitScripts(ctx, req.ServiceConfigs, req.Project); err != nil {
+	if err := s.executeLocalInitScripts(ctx, req.ServiceConfigs, req.Project, req.InitLog); err != nil {
 		_ = s.compose.Down(ctx, req.Project, api.DownOptions{RemoveOrphans: true})
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentProject, messages.ErrorsStackInitScriptsFailed, err)
 	}
@@ -240,6 +288,45 @@ func (s *Service) Stop(ctx context.Context, req StopRequest) error {
 	return nil
 }
 
+// Pause suspends the running containers of the requested services.
+// It returns the names of the containers that were paused; containers in any
+// other state are left untouched.
+func (s *Service) Pause(ctx context.Context, req PauseRequest) ([]string, error) {
+	return s.applyToContainers(ctx, req, docker.StateRunning, s.DockerClient.PauseContainer)
+}
+
+// Unpause resumes the paused containers of the requested services.
+// It returns the names of the containers that were unpaused.
+func (s *Service) Unpause(ctx context.Context, req PauseRequest) ([]string, error) {
+	return s.applyToContainers(ctx, req, docker.StatePaused, s.DockerClient.UnpauseContainer)
+}
+
+// applyToContainers runs action against every project container that belongs to one of the
+// requested services and is currently in the given state.
+func (s *Service) applyToContainers(ctx context.Context, req PauseRequest, state string, action func(context.Context, string) error) ([]string, error) {
+	containers, err := s.DockerClient.ListContainers(ctx, req.Project)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(req.ServiceConfigs))
+	for _, name := range ExtractServiceNames(req.ServiceConfigs) {
+		wanted[name] = true
+	}
+
+	var affected []string
+	for _, cont := range containers {
+		if !wanted[cont.Service] || cont.State != state {
+			continue
+		}
+		if err := action(ctx, cont.Name); err != nil {
+			return affected, err
+		}
+		affected = append(affected, cont.Name)
+	}
+	return affected, nil
+}
+
 // Logs retrieves logs from services
 func (s *Service) Logs(ctx context.Context, req LogRequest) error {
 	serviceNames := ExtractServiceNames(req.ServiceConfigs)
@@ -252,7 +339,10 @@ func (s *Service) Logs(ctx context.Context, req LogRequest) error {
 		Since:      req.Since,
 		Until:      req.Until,
 	}
-	consumer := docker.NewServiceLogConsumer(req.Writer, req.NoColor, len(serviceNames))
+	var consumer api.LogConsumer = req.Consumer
+	if consumer == nil {
+		consumer = docker.NewServiceLogConsumer(req.Writer, req.NoColor, len(serviceNames)).WithFilter(req.Filter)
+	}
 	err := s.compose.Logs(ctx, req.Project, consumer, options.ToSDK())
 	if err != nil {
 		return pkgerrors.NewServiceError(pkgerrors.ErrCodeOperationFail, pkgerrors.ComponentProject, messages.ErrorsStackGetLogsFailed, err)
@@ -289,6 +379,9 @@ func (s *Service) Exec(ctx context.Context, req ExecRequest) error {
 
 // Status retrieves status of services
 func (s *Service) Status(ctx context.Context, req StatusRequest) ([]docker.ContainerStatus, error) {
+	if req.Stats {
+		return s.DockerClient.GetServiceStatusWithStats(ctx, req.Project, req.Services)
+	}
 	return s.DockerClient.GetServiceStatus(ctx, req.Project, req.Services)
 }
 
@@ -340,12 +433,14 @@ func (s *Service) CheckDockerHealth(ctx context.Context) error {
 	return nil
 }
 
-// GenerateComposeFile generates docker-compose.yml from service configs
-func (s *Service) GenerateComposeFile(projectName string, serviceConfigs []servicetypes.ServiceConfig) error {
+// GenerateComposeFile generates docker-compose.yml from service configs, with
+// every service also joining the networks of attachProjects
+func (s *Service) GenerateComposeFile(projectName string, serviceConfigs []servicetypes.ServiceConfig, attachProjects []string) error {
 	generator, err := compose.NewGenerator(projectName)
 	if err != nil {
 		return err
 	}
+	generator.AttachProjects(attachProjects)
 
 	return generator.GenerateFromServiceConfigs(serviceConfigs, projectName)
 }
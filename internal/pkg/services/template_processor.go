@@ -2,6 +2,7 @@ package services
 
 import (
 	"bytes"
+	"maps"
 	"reflect"
 	"slices"
 	"strings"
@@ -36,8 +37,11 @@ func (tp *TemplateProcessor) Process(scriptContent string, config servicetypes.S
 	return buf.String(), nil
 }
 
+// collectTemplateData gathers the service's own settings, then those of the
+// services that depend on it, which take precedence
 func (tp *TemplateProcessor) collectTemplateData(config servicetypes.ServiceConfig, allConfigs []servicetypes.ServiceConfig) map[string]any {
 	templateData := make(map[string]any)
+	maps.Copy(templateData, config.Settings)
 
 	for _, serviceConfig := range allConfigs {
 		if tp.serviceDependsOn(serviceConfig, config.Name) {
@@ -58,6 +62,8 @@ func (tp *TemplateProcessor) serviceDependsOn(serviceConfig servicetypes.Service
 }
 
 func (tp *TemplateProcessor) addConfigData(templateData map[string]any, serviceConfig servicetypes.ServiceConfig) {
+	maps.Copy(templateData, serviceConfig.Settings)
+
 	v := reflect.ValueOf(serviceConfig)
 
 	for _, field := range v.Fields() {
@@ -39,6 +39,28 @@ type VolumeSpec struct {
 	Name     string `yaml:"name,omitempty"`
 	Mount    string `yaml:"mount,omitempty"`
 	ReadOnly bool   `yaml:"read_only,omitempty"`
+	// External marks a named volume that exists outside the project, such as
+	// one shared move copied a shared container's data into
+	External bool `yaml:"external,omitempty"`
+}
+
+// TLSSpec says how a service serves TLS with a certificate from the local CA,
+// for projects whose service config file sets tls: true. The certificate,
+// its key and the CA certificate are mounted read-only in Mount as tls.crt,
+// tls.key and ca.crt.
+type TLSSpec struct {
+	Mount string `yaml:"mount"`
+	// Entrypoint replaces the container's, such as to copy the key to where
+	// the server accepts its owner and permissions
+	Entrypoint []string `yaml:"entrypoint,omitempty"`
+	// Command is appended to the container's command
+	Command []string `yaml:"command,omitempty"`
+	// HealthCheck replaces the test of the container's health check, for
+	// servers that no longer accept plain connections
+	HealthCheck []string `yaml:"health_check,omitempty"`
+	// Environment is added to the project's env file. {{.CA}}, {{.Cert}} and
+	// {{.Key}} stand for the paths of the files on the host.
+	Environment map[string]string `yaml:"environment,omitempty"`
 }
 
 // ServiceSpec defines service integration
@@ -102,6 +124,9 @@ type WebInterface struct {
 	Name        string `yaml:"name"`
 	URL         string `yaml:"url"`
 	Description string `yaml:"description"`
+	// Port is the container port serving the interface. The proxy routes
+	// <service>.<project>.localhost to the first interface that has one.
+	Port int `yaml:"port,omitempty"`
 }
 
 // ParametersSpec defines service parameters
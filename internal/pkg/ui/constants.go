@@ -17,6 +17,9 @@ const (
 	ColorBold    = "\033[1m"
 	ColorReset   = "\033[0m"
 
+	// ClearScreen moves the cursor home and clears the terminal
+	ClearScreen = "\033[H\033[2J"
+
 	SpinnerIntervalMilliseconds = 100
 )
 
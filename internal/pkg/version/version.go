@@ -30,6 +30,22 @@ type BuildInfo struct {
 }
 
 // GetBuildInfo returns comprehensive build information
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   GetAppVersion(),
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		BuildBy:   BuildBy,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS,
+		Arch:      runtime.GOARCH,
+	}
+	if t, err := time.Parse(time.RFC3339, BuildDate); err == nil {
+		info.BuildTime = t
+	}
+	return info
+}
+
 // GetAppVersion returns the application version string
 func GetAppVersion() string {
 	if AppVersion != DefaultVersion {
@@ -28,3 +28,11 @@ func TestGetUserAgent(t *testing.T) {
 	assert.NotEmpty(t, userAgent)
 	assert.Contains(t, userAgent, "otto-stack")
 }
+
+func TestGetBuildInfo(t *testing.T) {
+	info := GetBuildInfo()
+	assert.Equal(t, GetAppVersion(), info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.NotEmpty(t, info.Platform)
+	assert.True(t, info.BuildTime.IsZero()) // BuildDate is not set by ldflags in tests
+}
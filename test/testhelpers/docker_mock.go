@@ -3,9 +3,11 @@ package testhelpers
 import (
 	"context"
 	"io"
+	"strings"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/events"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/system"
@@ -15,24 +17,35 @@ import (
 
 // MockDockerClient is a mock implementation of docker.DockerClient for testing
 type MockDockerClient struct {
-	ContainerListFunc    func(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
-	ContainerRemoveFunc  func(ctx context.Context, containerID string, options container.RemoveOptions) error
-	ContainerInspectFunc func(ctx context.Context, containerID string) (container.InspectResponse, error)
-	ContainerCreateFunc  func(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error)
-	ContainerStartFunc   func(ctx context.Context, containerID string, options container.StartOptions) error
-	ContainerStopFunc    func(ctx context.Context, containerID string, options container.StopOptions) error
-	ContainerRestartFunc func(ctx context.Context, containerID string, options container.StopOptions) error
-	ContainerWaitFunc    func(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
-	ContainerLogsFunc    func(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
-	VolumeListFunc       func(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
-	VolumeRemoveFunc     func(ctx context.Context, volumeID string, force bool) error
-	NetworkListFunc      func(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
-	NetworkRemoveFunc    func(ctx context.Context, networkID string) error
-	ImageListFunc        func(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
-	ImageRemoveFunc      func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
-	InfoFunc             func(ctx context.Context) (system.Info, error)
-	PingFunc             func(ctx context.Context) (types.Ping, error)
-	CloseFunc            func() error
+	ContainerListFunc     func(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
+	ContainerRemoveFunc   func(ctx context.Context, containerID string, options container.RemoveOptions) error
+	ContainerInspectFunc  func(ctx context.Context, containerID string) (container.InspectResponse, error)
+	ContainerCreateFunc   func(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, platform *ocispec.Platform, containerName string) (container.CreateResponse, error)
+	ContainerStartFunc    func(ctx context.Context, containerID string, options container.StartOptions) error
+	ContainerStopFunc     func(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerRestartFunc  func(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerPauseFunc    func(ctx context.Context, containerID string) error
+	ContainerUnpauseFunc  func(ctx context.Context, containerID string) error
+	ContainerWaitFunc     func(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
+	ContainerLogsFunc     func(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
+	ContainerStatsFunc    func(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error)
+	VolumeListFunc        func(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error)
+	VolumeRemoveFunc      func(ctx context.Context, volumeID string, force bool) error
+	NetworkListFunc       func(ctx context.Context, options network.ListOptions) ([]network.Summary, error)
+	NetworkCreateFunc     func(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error)
+	NetworkRemoveFunc     func(ctx context.Context, networkID string) error
+	NetworkConnectFunc    func(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error
+	NetworkDisconnectFunc func(ctx context.Context, networkID, containerID string, force bool) error
+	ImageListFunc         func(ctx context.Context, options image.ListOptions) ([]image.Summary, error)
+	ImageRemoveFunc       func(ctx context.Context, imageID string, options image.RemoveOptions) ([]image.DeleteResponse, error)
+	EventsFunc            func(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error)
+	InfoFunc              func(ctx context.Context) (system.Info, error)
+	PingFunc              func(ctx context.Context) (types.Ping, error)
+	ExecCreateFunc        func(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error)
+	ExecAttachFunc        func(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error)
+	ExecInspectFunc       func(ctx context.Context, execID string) (container.ExecInspect, error)
+	DiskUsageFunc         func(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error)
+	CloseFunc             func() error
 }
 
 func (m *MockDockerClient) ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error) {
@@ -84,6 +97,20 @@ func (m *MockDockerClient) ContainerRestart(ctx context.Context, containerID str
 	return nil
 }
 
+func (m *MockDockerClient) ContainerPause(ctx context.Context, containerID string) error {
+	if m.ContainerPauseFunc != nil {
+		return m.ContainerPauseFunc(ctx, containerID)
+	}
+	return nil
+}
+
+func (m *MockDockerClient) ContainerUnpause(ctx context.Context, containerID string) error {
+	if m.ContainerUnpauseFunc != nil {
+		return m.ContainerUnpauseFunc(ctx, containerID)
+	}
+	return nil
+}
+
 func (m *MockDockerClient) ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
 	if m.ContainerWaitFunc != nil {
 		return m.ContainerWaitFunc(ctx, containerID, condition)
@@ -103,6 +130,13 @@ func (m *MockDockerClient) ContainerLogs(ctx context.Context, container string,
 	return io.NopCloser(nil), nil
 }
 
+func (m *MockDockerClient) ContainerStats(ctx context.Context, containerID string, stream bool) (container.StatsResponseReader, error) {
+	if m.ContainerStatsFunc != nil {
+		return m.ContainerStatsFunc(ctx, containerID, stream)
+	}
+	return container.StatsResponseReader{Body: io.NopCloser(strings.NewReader("{}"))}, nil
+}
+
 func (m *MockDockerClient) VolumeList(ctx context.Context, options volume.ListOptions) (volume.ListResponse, error) {
 	if m.VolumeListFunc != nil {
 		return m.VolumeListFunc(ctx, options)
@@ -124,6 +158,13 @@ func (m *MockDockerClient) NetworkList(ctx context.Context, options network.List
 	return []network.Summary{}, nil
 }
 
+func (m *MockDockerClient) NetworkCreate(ctx context.Context, name string, options network.CreateOptions) (network.CreateResponse, error) {
+	if m.NetworkCreateFunc != nil {
+		return m.NetworkCreateFunc(ctx, name, options)
+	}
+	return network.CreateResponse{ID: name}, nil
+}
+
 func (m *MockDockerClient) NetworkRemove(ctx context.Context, networkID string) error {
 	if m.NetworkRemoveFunc != nil {
 		return m.NetworkRemoveFunc(ctx, networkID)
@@ -131,6 +172,20 @@ func (m *MockDockerClient) NetworkRemove(ctx context.Context, networkID string)
 	return nil
 }
 
+func (m *MockDockerClient) NetworkConnect(ctx context.Context, networkID, containerID string, config *network.EndpointSettings) error {
+	if m.NetworkConnectFunc != nil {
+		return m.NetworkConnectFunc(ctx, networkID, containerID, config)
+	}
+	return nil
+}
+
+func (m *MockDockerClient) NetworkDisconnect(ctx context.Context, networkID, containerID string, force bool) error {
+	if m.NetworkDisconnectFunc != nil {
+		return m.NetworkDisconnectFunc(ctx, networkID, containerID, force)
+	}
+	return nil
+}
+
 func (m *MockDockerClient) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	if m.ImageListFunc != nil {
 		return m.ImageListFunc(ctx, options)
@@ -145,6 +200,17 @@ func (m *MockDockerClient) ImageRemove(ctx context.Context, imageID string, opti
 	return []image.DeleteResponse{}, nil
 }
 
+func (m *MockDockerClient) Events(ctx context.Context, options events.ListOptions) (<-chan events.Message, <-chan error) {
+	if m.EventsFunc != nil {
+		return m.EventsFunc(ctx, options)
+	}
+	msgCh := make(chan events.Message)
+	errCh := make(chan error)
+	close(msgCh)
+	close(errCh)
+	return msgCh, errCh
+}
+
 func (m *MockDockerClient) Info(ctx context.Context) (system.Info, error) {
 	if m.InfoFunc != nil {
 		return m.InfoFunc(ctx)
@@ -152,6 +218,34 @@ func (m *MockDockerClient) Info(ctx context.Context) (system.Info, error) {
 	return system.Info{}, nil
 }
 
+func (m *MockDockerClient) ContainerExecCreate(ctx context.Context, containerID string, options container.ExecOptions) (container.ExecCreateResponse, error) {
+	if m.ExecCreateFunc != nil {
+		return m.ExecCreateFunc(ctx, containerID, options)
+	}
+	return container.ExecCreateResponse{}, nil
+}
+
+func (m *MockDockerClient) ContainerExecAttach(ctx context.Context, execID string, options container.ExecAttachOptions) (types.HijackedResponse, error) {
+	if m.ExecAttachFunc != nil {
+		return m.ExecAttachFunc(ctx, execID, options)
+	}
+	return types.HijackedResponse{}, nil
+}
+
+func (m *MockDockerClient) ContainerExecInspect(ctx context.Context, execID string) (container.ExecInspect, error) {
+	if m.ExecInspectFunc != nil {
+		return m.ExecInspectFunc(ctx, execID)
+	}
+	return container.ExecInspect{}, nil
+}
+
+func (m *MockDockerClient) DiskUsage(ctx context.Context, options types.DiskUsageOptions) (types.DiskUsage, error) {
+	if m.DiskUsageFunc != nil {
+		return m.DiskUsageFunc(ctx, options)
+	}
+	return types.DiskUsage{}, nil
+}
+
 func (m *MockDockerClient) Ping(ctx context.Context) (types.Ping, error) {
 	if m.PingFunc != nil {
 		return m.PingFunc(ctx)